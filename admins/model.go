@@ -0,0 +1,18 @@
+package admins
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Admin é um operador da plataforma (back-office), com login próprio em /admin/login
+type Admin struct {
+	ID               bson.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name             string        `bson:"name" json:"name"`
+	Email            string        `bson:"email" json:"email"`
+	Password         string        `bson:"password" json:"-"`
+	TokensValidAfter *time.Time    `bson:"tokens_valid_after,omitempty" json:"-"` // tokens emitidos antes disso são rejeitados
+	CreatedAt        time.Time     `bson:"created_at" json:"created_at"`
+	UpdatedAt        time.Time     `bson:"updated_at" json:"updated_at"`
+}
@@ -0,0 +1,72 @@
+package admins
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type MongoRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoRepository(db *mongo.Database) *MongoRepository {
+	return &MongoRepository{
+		collection: db.Collection("admins"),
+	}
+}
+
+// EnsureIndexes cria o índice único de email
+func (r *MongoRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "email", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
+
+func (r *MongoRepository) Create(ctx context.Context, admin *Admin) error {
+	admin.ID = bson.NewObjectID()
+	admin.CreatedAt = time.Now()
+	admin.UpdatedAt = time.Now()
+
+	_, err := r.collection.InsertOne(ctx, admin)
+	return err
+}
+
+func (r *MongoRepository) GetByID(ctx context.Context, id string) (*Admin, error) {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var admin Admin
+	err = r.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&admin)
+	if err != nil {
+		return nil, err
+	}
+
+	return &admin, nil
+}
+
+func (r *MongoRepository) GetByEmail(ctx context.Context, email string) (*Admin, error) {
+	var admin Admin
+	err := r.collection.FindOne(ctx, bson.M{"email": email}).Decode(&admin)
+	if err != nil {
+		return nil, err
+	}
+
+	return &admin, nil
+}
+
+func (r *MongoRepository) Update(ctx context.Context, admin *Admin) error {
+	admin.UpdatedAt = time.Now()
+	filter := bson.M{"_id": admin.ID}
+	update := bson.M{"$set": admin}
+
+	_, err := r.collection.UpdateOne(ctx, filter, update)
+	return err
+}
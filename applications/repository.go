@@ -6,6 +6,7 @@ import (
 
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
 
 type MongoRepository struct {
@@ -127,6 +128,29 @@ func (r *MongoRepository) ExistsByJobAndCandidate(ctx context.Context, jobID, ca
 	return count > 0, nil
 }
 
+// FindReviewable retorna a candidatura mais recente do candidato na empresa que já permite avaliar
+// o processo: a empresa já agiu sobre ela (status diferente de "pending") ou ela foi enviada
+// antes de appliedBefore (sem resposta também é uma experiência a relatar).
+// Retorna mongo.ErrNoDocuments se não houver.
+func (r *MongoRepository) FindReviewable(ctx context.Context, companyID, candidateID bson.ObjectID, appliedBefore time.Time) (*Application, error) {
+	filter := bson.M{
+		"company_id":   companyID,
+		"candidate_id": candidateID,
+		"$or": []bson.M{
+			{"status": bson.M{"$ne": "pending"}},
+			{"applied_at": bson.M{"$lte": appliedBefore}},
+		},
+	}
+	opts := options.FindOne().SetSort(bson.D{{Key: "applied_at", Value: -1}})
+
+	var application Application
+	if err := r.collection.FindOne(ctx, filter, opts).Decode(&application); err != nil {
+		return nil, err
+	}
+
+	return &application, nil
+}
+
 func (r *MongoRepository) Update(ctx context.Context, application *Application) error {
 	application.UpdatedAt = time.Now()
 	filter := bson.M{"_id": application.ID}
@@ -16,20 +16,32 @@ type Experience struct {
 }
 
 type Candidate struct {
-	ID          bson.ObjectID `bson:"_id,omitempty" json:"id"`
-	Name        string        `bson:"name" json:"name" validate:"required"`
-	Email       string        `bson:"email" json:"email" validate:"required,email"`
-	Password    string        `bson:"password" json:"-"`
-	Phone       string        `bson:"phone" json:"phone"`
-	Resume      string        `bson:"resume,omitempty" json:"resume,omitempty"`
-	Skills      []string      `bson:"skills,omitempty" json:"skills,omitempty"`
-	Experiences []Experience  `bson:"experiences,omitempty" json:"experiences,omitempty"`
-	Location    string        `bson:"location" json:"location"`
-	LinkedIn    string        `bson:"linkedin,omitempty" json:"linkedin,omitempty"`
-	GitHub      string        `bson:"github,omitempty" json:"github,omitempty"`
-	Portfolio   string        `bson:"portfolio,omitempty" json:"portfolio,omitempty"`
-	CreatedAt   time.Time     `bson:"created_at" json:"created_at"`
-	UpdatedAt   time.Time     `bson:"updated_at" json:"updated_at"`
+	ID               bson.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name             string        `bson:"name" json:"name" validate:"required"`
+	Email            string        `bson:"email" json:"email" validate:"required,email"`
+	Password         string        `bson:"password" json:"-"`
+	Phone            string        `bson:"phone" json:"phone"`
+	Resume           string        `bson:"resume,omitempty" json:"resume,omitempty"`
+	Skills           []string      `bson:"skills,omitempty" json:"skills,omitempty"`
+	Experiences      []Experience  `bson:"experiences,omitempty" json:"experiences,omitempty"`
+	Location         string        `bson:"location" json:"location"`
+	LinkedIn         string        `bson:"linkedin,omitempty" json:"linkedin,omitempty"`
+	GitHub           string        `bson:"github,omitempty" json:"github,omitempty"`
+	Portfolio        string        `bson:"portfolio,omitempty" json:"portfolio,omitempty"`
+	SuspendedAt      *time.Time    `bson:"suspended_at,omitempty" json:"suspended_at,omitempty"` // conta suspensa por um admin: login e sessões bloqueados
+	SuspensionReason string        `bson:"suspension_reason,omitempty" json:"suspension_reason,omitempty"`
+	TokensValidAfter *time.Time    `bson:"tokens_valid_after,omitempty" json:"-"` // tokens emitidos antes disso são rejeitados
+	CreatedAt        time.Time     `bson:"created_at" json:"created_at"`
+	UpdatedAt        time.Time     `bson:"updated_at" json:"updated_at"`
+
+	// Banco de talentos: visibilidade escolhida pelo candidato, idiomas e disponibilidade, mais os
+	// campos recalculados a cada Create/Update (nível inferido das experiências e
+	// habilidades/idiomas normalizados para a busca)
+	TalentVisibility string   `bson:"talent_visibility,omitempty" json:"talent_visibility,omitempty"`
+	Languages        []string `bson:"languages" json:"languages,omitempty"` // ex: "Inglês avançado"
+	Availability     string   `bson:"availability" json:"availability,omitempty"`
+	Level            string   `bson:"level" json:"level,omitempty"`
+	SearchTerms      []string `bson:"search_terms" json:"-"`
 }
 
 type CandidateRepository interface {
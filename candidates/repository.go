@@ -2,10 +2,12 @@ package candidates
 
 import (
 	"context"
+	"regexp"
 	"time"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
 
 type MongoRepository struct {
@@ -18,10 +20,19 @@ func NewMongoRepository(db *mongo.Database) *MongoRepository {
 	}
 }
 
+// EnsureIndexes cria o índice da busca de talentos
+func (r *MongoRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "talent_visibility", Value: 1}, {Key: "search_terms", Value: 1}}},
+	})
+	return err
+}
+
 func (r *MongoRepository) Create(ctx context.Context, candidate *Candidate) error {
 	candidate.ID = bson.NewObjectID()
 	candidate.CreatedAt = time.Now()
 	candidate.UpdatedAt = time.Now()
+	candidate.refreshTalentFields()
 
 	_, err := r.collection.InsertOne(ctx, candidate)
 	return err
@@ -54,6 +65,7 @@ func (r *MongoRepository) GetByEmail(ctx context.Context, email string) (*Candid
 
 func (r *MongoRepository) Update(ctx context.Context, candidate *Candidate) error {
 	candidate.UpdatedAt = time.Now()
+	candidate.refreshTalentFields()
 	filter := bson.M{"_id": candidate.ID}
 	update := bson.M{"$set": candidate}
 
@@ -70,3 +82,63 @@ func (r *MongoRepository) Delete(ctx context.Context, id string) error {
 	_, err = r.collection.DeleteOne(ctx, bson.M{"_id": objectID})
 	return err
 }
+
+// SetSuspended suspende (com motivo) ou reativa a conta do candidato
+func (r *MongoRepository) SetSuspended(ctx context.Context, id bson.ObjectID, suspended bool, reason string) error {
+	now := time.Now()
+	update := bson.M{
+		"$set": bson.M{"suspended_at": now, "suspension_reason": reason, "updated_at": now},
+	}
+	if !suspended {
+		update = bson.M{
+			"$set":   bson.M{"updated_at": now},
+			"$unset": bson.M{"suspended_at": "", "suspension_reason": ""},
+		}
+	}
+
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	return err
+}
+
+// SearchTalent busca candidatos no banco de talentos com as visibilidades informadas, os mais
+// recentemente atualizados primeiro. Retorna a página e o total.
+func (r *MongoRepository) SearchTalent(ctx context.Context, filters TalentFilters, visibilities []string, limit, offset int64) ([]*Candidate, int64, error) {
+	filter := bson.M{
+		"talent_visibility": bson.M{"$in": visibilities},
+		"suspended_at":      bson.M{"$exists": false},
+	}
+	if terms := filters.terms(); len(terms) > 0 {
+		filter["search_terms"] = bson.M{"$all": terms}
+	}
+	if filters.Location != "" {
+		filter["location"] = bson.M{"$regex": regexp.QuoteMeta(filters.Location), "$options": "i"}
+	}
+	if filters.Level != "" {
+		filter["level"] = filters.Level
+	}
+	if len(filters.Availability) > 0 {
+		filter["availability"] = bson.M{"$in": filters.Availability}
+	}
+
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "updated_at", Value: -1}}).
+		SetSkip(offset).
+		SetLimit(limit)
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var found []*Candidate
+	if err = cursor.All(ctx, &found); err != nil {
+		return nil, 0, err
+	}
+
+	return found, total, nil
+}
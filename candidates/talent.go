@@ -0,0 +1,231 @@
+package candidates
+
+import (
+	"empregabemapi/internal/matching"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Visibilidade do perfil no banco de talentos. Sem o campo, o perfil é privado: só as empresas
+// que receberam candidatura veem o candidato.
+const (
+	TalentVisibilityPrivate  = "private"
+	TalentVisibilityVerified = "verified_companies" // busca e perfil para empresas verificadas
+	TalentVisibilityPublic   = "public"             // também página pública em GET /talent/{id}
+)
+
+// Disponibilidade informada pelo candidato no banco de talentos
+const (
+	AvailabilityImmediate = "imediata"
+	Availability15Days    = "15_dias"
+	Availability30Days    = "30_dias"
+	AvailabilityOpen      = "aberto_a_propostas" // empregado, mas ouve propostas
+)
+
+// Níveis inferidos pelo tempo de experiência
+const (
+	LevelJunior = "junior"
+	LevelPleno  = "pleno"
+	LevelSenior = "senior"
+)
+
+const maxTalentLanguages = 10
+
+// Prefixos dos termos guardados em search_terms
+const (
+	skillTermPrefix    = "s:"
+	languageTermPrefix = "l:"
+)
+
+// ValidTalentVisibility indica se a visibilidade existe
+func ValidTalentVisibility(v string) bool {
+	return v == TalentVisibilityPrivate || v == TalentVisibilityVerified || v == TalentVisibilityPublic
+}
+
+// ValidAvailability indica se a disponibilidade existe (vazia = não informada)
+func ValidAvailability(a string) bool {
+	switch a {
+	case "", AvailabilityImmediate, Availability15Days, Availability30Days, AvailabilityOpen:
+		return true
+	}
+	return false
+}
+
+// InTalentPool indica se o perfil aparece para empresas verificadas
+func (c *Candidate) InTalentPool() bool {
+	return c.SuspendedAt == nil && (c.TalentVisibility == TalentVisibilityVerified || c.TalentVisibility == TalentVisibilityPublic)
+}
+
+// InferLevel estima o nível pelo tempo total de experiência: até 2 anos júnior, até 5 pleno,
+// acima disso sênior. Sem experiências com datas reconhecidas, júnior.
+func InferLevel(experiences []Experience, now time.Time) string {
+	var months float64
+	for _, exp := range experiences {
+		start, ok := parseExperienceDate(exp.StartDate)
+		if !ok {
+			continue
+		}
+		end := now
+		if !exp.IsCurrent {
+			if parsed, ok := parseExperienceDate(exp.EndDate); ok {
+				end = parsed
+			}
+		}
+		if end.After(start) {
+			months += end.Sub(start).Hours() / 24 / 30
+		}
+	}
+
+	switch {
+	case months < 24:
+		return LevelJunior
+	case months < 60:
+		return LevelPleno
+	default:
+		return LevelSenior
+	}
+}
+
+// Formatos aceitos nas datas das experiências do perfil
+var experienceDateLayouts = []string{"2006-01-02", "2006-01", "01/2006", "02/01/2006", "2006", time.RFC3339}
+
+func parseExperienceDate(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	for _, layout := range experienceDateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// refreshTalentFields recalcula o nível e os termos de busca (habilidades e idiomas normalizados)
+// usados no banco de talentos. Chamado a cada Create/Update.
+func (c *Candidate) refreshTalentFields() {
+	c.Level = InferLevel(c.Experiences, time.Now())
+
+	seen := map[string]bool{}
+	c.SearchTerms = []string{}
+	add := func(prefix, value string) {
+		if term := matching.Normalize(value); term != "" && !seen[prefix+term] {
+			seen[prefix+term] = true
+			c.SearchTerms = append(c.SearchTerms, prefix+term)
+		}
+	}
+	for _, skill := range c.Skills {
+		add(skillTermPrefix, skill)
+	}
+	// Idiomas palavra por palavra: "Inglês avançado" é encontrado na busca por "inglês"
+	for _, language := range c.Languages {
+		for _, word := range strings.Fields(matching.Normalize(language)) {
+			add(languageTermPrefix, word)
+		}
+	}
+}
+
+// TalentFilters são os filtros da busca de talentos pelas empresas
+type TalentFilters struct {
+	Skills       []string // todas as habilidades
+	Languages    []string // todos os idiomas
+	Location     string
+	Level        string
+	Availability []string // qualquer uma delas
+}
+
+// Summary descreve a busca em uma linha (exibida no histórico do candidato)
+func (f TalentFilters) Summary() string {
+	var parts []string
+	if len(f.Skills) > 0 {
+		parts = append(parts, strings.Join(f.Skills, ", "))
+	}
+	if len(f.Languages) > 0 {
+		parts = append(parts, strings.Join(f.Languages, ", "))
+	}
+	for _, value := range []string{f.Location, f.Level} {
+		if value != "" {
+			parts = append(parts, value)
+		}
+	}
+	return strings.Join(parts, " · ")
+}
+
+func (f TalentFilters) terms() []string {
+	var terms []string
+	for _, skill := range f.Skills {
+		if term := matching.Normalize(skill); term != "" {
+			terms = append(terms, skillTermPrefix+term)
+		}
+	}
+	for _, language := range f.Languages {
+		for _, word := range strings.Fields(matching.Normalize(language)) {
+			terms = append(terms, languageTermPrefix+word)
+		}
+	}
+	return terms
+}
+
+// TalentProfile é o perfil exibido às empresas no banco de talentos (sem contatos nem currículo)
+type TalentProfile struct {
+	ID           bson.ObjectID `json:"id"`
+	Name         string        `json:"name"`
+	Location     string        `json:"location,omitempty"`
+	Skills       []string      `json:"skills,omitempty"`
+	Languages    []string      `json:"languages,omitempty"`
+	Experiences  []Experience  `json:"experiences,omitempty"`
+	Level        string        `json:"level"`
+	Availability string        `json:"availability,omitempty"`
+	GitHub       string        `json:"github,omitempty"`
+	Portfolio    string        `json:"portfolio,omitempty"`
+}
+
+// Contact são os dados de contato, liberados para a empresa quando o candidato aceita o pedido
+type Contact struct {
+	Email    string `json:"email"`
+	Phone    string `json:"phone,omitempty"`
+	LinkedIn string `json:"linkedin,omitempty"`
+	Resume   string `json:"resume,omitempty"`
+}
+
+// TalentProfile retorna os dados do candidato que as empresas podem ver sem contato aceito
+func (c *Candidate) TalentProfile() TalentProfile {
+	return TalentProfile{
+		ID:           c.ID,
+		Name:         c.Name,
+		Location:     c.Location,
+		Skills:       c.Skills,
+		Languages:    c.Languages,
+		Experiences:  c.Experiences,
+		Level:        c.Level,
+		Availability: c.Availability,
+		GitHub:       c.GitHub,
+		Portfolio:    c.Portfolio,
+	}
+}
+
+// Contact retorna os dados de contato do candidato
+func (c *Candidate) Contact() Contact {
+	return Contact{
+		Email:    c.Email,
+		Phone:    c.Phone,
+		LinkedIn: c.LinkedIn,
+		Resume:   c.Resume,
+	}
+}
+
+// NormalizeLanguages remove idiomas vazios e repetidos e limita a quantidade
+func NormalizeLanguages(languages []string) []string {
+	seen := map[string]bool{}
+	result := []string{}
+	for _, language := range languages {
+		language = strings.TrimSpace(language)
+		key := matching.Normalize(language)
+		if key == "" || seen[key] || len(result) == maxTalentLanguages {
+			continue
+		}
+		seen[key] = true
+		result = append(result, language)
+	}
+	return result
+}
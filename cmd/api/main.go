@@ -1,21 +1,51 @@
 package main
 
 import (
+	"context"
+	"empregabemapi/admins"
 	"empregabemapi/applications"
 	"empregabemapi/candidates"
 	"empregabemapi/companies"
 	"empregabemapi/database"
 	"empregabemapi/internal/auth"
 	"empregabemapi/internal/config"
+	"empregabemapi/internal/events"
 	"empregabemapi/internal/http"
+	"empregabemapi/internal/mail"
+	"empregabemapi/internal/media"
 	"empregabemapi/internal/middleware"
+	"empregabemapi/internal/notifications"
+	"empregabemapi/internal/ratelimit"
 	"empregabemapi/internal/repository"
+	"empregabemapi/internal/storage"
+	"empregabemapi/internal/webhooks"
 	"empregabemapi/jobs"
 	"fmt"
 	"log"
 	nethttp "net/http"
+	"time"
+	// Fusos horários embutidos: entrevistas usam nomes IANA mesmo em imagens sem tzdata
+	_ "time/tzdata"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
+// Limite padrão por IP para qualquer rota
+var defaultRateLimit = ratelimit.Rule{Name: "default", Limit: 300, Window: time.Minute}
+
+// Limites por rota (a primeira regra que casar é aplicada)
+var rateLimitRules = []ratelimit.Rule{
+	{Name: "login", PathPrefix: "/company/login", Methods: []string{"POST"}, Limit: 10, Window: time.Minute},
+	{Name: "login", PathPrefix: "/candidate/login", Methods: []string{"POST"}, Limit: 10, Window: time.Minute},
+	{Name: "login", PathPrefix: "/admin/login", Methods: []string{"POST"}, Limit: 10, Window: time.Minute},
+	{Name: "request-reset", PathPrefix: "/auth/request-reset", Limit: 5, Window: 15 * time.Minute},
+	{Name: "reset-password", PathPrefix: "/auth/reset-password", Limit: 10, Window: 15 * time.Minute},
+	{Name: "register", PathPrefix: "/company/register", Limit: 20, Window: time.Hour},
+	{Name: "register", PathPrefix: "/candidate/register", Limit: 20, Window: time.Hour},
+	{Name: "upload", PathPrefix: "/company/me/logo", Methods: []string{"POST"}, Limit: 30, Window: time.Hour},
+	{Name: "upload", PathPrefix: "/company/me/cover", Methods: []string{"POST"}, Limit: 30, Window: time.Hour},
+}
+
 func main() {
 	cfg := config.Load()
 
@@ -35,15 +65,110 @@ func main() {
 	}
 	defer mongodb.Close()
 
+	// Chaves de assinatura JWT (RS256/EdDSA com rotação; HS256 segue aceito durante a migração)
+	if err := auth.Configure(cfg.JWTAlgorithm, cfg.JWTAcceptHS256); err != nil {
+		log.Fatal("Configuração JWT inválida:", err)
+	}
+	if cfg.JWTAlgorithm != auth.AlgHS256 {
+		startKeyRotation(cfg, mongodb)
+	}
+
 	// Criar repositórios
 	companyRepo := companies.NewMongoRepository(mongodb.Database)
+	if err := companyRepo.EnsureIndexes(context.Background()); err != nil {
+		log.Println("Aviso: erro ao criar índices de companies:", err)
+	}
+	if n, err := companyRepo.BackfillSlugs(context.Background()); err != nil {
+		log.Println("Aviso: erro ao gerar slugs das empresas:", err)
+	} else if n > 0 {
+		log.Printf("Slugs gerados para %d empresas", n)
+	}
 	candidateRepo := candidates.NewMongoRepository(mongodb.Database)
+	if err := candidateRepo.EnsureIndexes(context.Background()); err != nil {
+		log.Println("Aviso: erro ao criar índices de candidates:", err)
+	}
 	jobsRepo := jobs.NewMongoRepository(mongodb.Database)
+	if err := jobsRepo.EnsureIndexes(context.Background()); err != nil {
+		log.Println("Aviso: erro ao criar índices de jobs:", err)
+	}
+	if n, err := jobsRepo.BackfillSimilarity(context.Background(), companySectorLookup(companyRepo)); err != nil {
+		log.Println("Aviso: erro ao calcular termos de semelhança das vagas:", err)
+	} else if n > 0 {
+		log.Printf("Termos de semelhança calculados para %d vagas", n)
+	}
 	appsRepo := applications.NewMongoRepository(mongodb.Database)
 	savedJobsRepo := repository.NewSavedJobsRepository(mongodb.Database)
 
+	// Primeiro administrador do back-office
+	if cfg.AdminEmail != "" {
+		bootstrapAdmin(cfg, admins.NewMongoRepository(mongodb.Database))
+	}
+
+	// Email transacional: handlers gravam no outbox e o worker envia em background
+	outboxRepo := repository.NewEmailOutboxRepository(mongodb.Database)
+	if err := outboxRepo.EnsureIndexes(context.Background()); err != nil {
+		log.Println("Aviso: erro ao criar índices do outbox de emails:", err)
+	}
+	mailer := mail.NewMailer(outboxRepo, cfg.AppURL, cfg.MailFrom)
+	mailWorker := mail.NewWorker(outboxRepo, newMailSender(cfg), 15*time.Second)
+	go mailWorker.Run(context.Background())
+
+	// Eventos em tempo real (SSE) distribuídos entre instâncias pelo broker
+	eventsHub := events.NewHub(newEventsBroker(cfg, mongodb))
+	go eventsHub.Run(context.Background())
+
+	// Central de notificações: eventos geram avisos por usuário; o worker cuida da validade das vagas
+	notificationsRepo := repository.NewNotificationsRepository(mongodb.Database)
+	if err := notificationsRepo.EnsureIndexes(context.Background()); err != nil {
+		log.Println("Aviso: erro ao criar índices de notifications:", err)
+	}
+	notifier := notifications.NewService(notificationsRepo, companies.NewMemberRepository(mongodb.Database), eventsHub)
+
+	// Webhooks das empresas: eventos viram entregas assinadas, enviadas pelo worker com retry.
+	// Fora de produção são aceitas URLs http e de rede local (ex: go run ./cmd/webhook-receiver)
+	webhooksRepo := repository.NewWebhooksRepository(mongodb.Database)
+	if err := webhooksRepo.EnsureIndexes(context.Background()); err != nil {
+		log.Println("Aviso: erro ao criar índices de webhooks:", err)
+	}
+	allowPrivateWebhooks := cfg.Environment != "production"
+	webhookDispatcher := webhooks.NewDispatcher(webhooksRepo, allowPrivateWebhooks)
+	webhookWorker := webhooks.NewWorker(webhooksRepo, webhooks.NewHTTPClient(allowPrivateWebhooks), 10*time.Second)
+	go webhookWorker.Run(context.Background())
+
+	expiryWorker := notifications.NewExpiryWorker(jobsRepo, notifier, webhookDispatcher, 10*time.Minute)
+	go expiryWorker.Run(context.Background())
+
+	// Buscas salvas dos candidatos: vagas novas geram alertas na hora ou em resumos diários/semanais
+	savedSearchesRepo := repository.NewSavedSearchesRepository(mongodb.Database)
+	if err := savedSearchesRepo.EnsureIndexes(context.Background()); err != nil {
+		log.Println("Aviso: erro ao criar índices de saved_searches:", err)
+	}
+	alertWorker := notifications.NewJobAlertWorker(jobsRepo, savedSearchesRepo, candidateRepo, notifier, mailer, 5*time.Minute)
+	go alertWorker.Run(context.Background())
+
+	// Imagens enviadas pelas empresas (logo e capa), servidas em /media
+	mediaStore, err := storage.NewFileSystemStore(cfg.StorageDir)
+	if err != nil {
+		log.Fatal("Erro ao preparar STORAGE_DIR:", err)
+	}
+	mediaLibrary := media.NewLibrary(mediaStore, cfg.MediaURL)
+
+	// Conversas por candidatura; os anexos ficam num storage privado (fora de /media)
+	messagesRepo := repository.NewMessagesRepository(mongodb.Database)
+	if err := messagesRepo.EnsureIndexes(context.Background()); err != nil {
+		log.Println("Aviso: erro ao criar índices de messages:", err)
+	}
+	attachmentStore, err := storage.NewFileSystemStore(cfg.AttachmentsDir)
+	if err != nil {
+		log.Fatal("Erro ao preparar ATTACHMENTS_DIR:", err)
+	}
+
+	// Rate limiting por IP com limites mais rígidos em login e reset de senha; o mesmo limiter
+	// aplica os limites por usuário de algumas rotas (ex: envio de mensagens)
+	rateLimiter := ratelimit.NewLimiter(newRateLimitStore(cfg, mongodb), defaultRateLimit, rateLimitRules...)
+
 	// Configurar rotas (passando database para password reset)
-	router := http.SetupRoutes(companyRepo, candidateRepo, jobsRepo, appsRepo, savedJobsRepo, mongodb.Database)
+	router := http.SetupRoutes(companyRepo, candidateRepo, jobsRepo, appsRepo, savedJobsRepo, mongodb.Database, mailer, newCNPJLookup(cfg), mediaLibrary, notificationsRepo, notifier, eventsHub, webhooksRepo, webhookDispatcher, messagesRepo, attachmentStore, rateLimiter, savedSearchesRepo)
 
 	// Configurar CORS - permitir frontend
 	allowedOrigins := parseOrigins(cfg.CORSOrigins)
@@ -53,9 +178,12 @@ func main() {
 	secureRouter := middleware.SecurityHeadersMiddleware(corsRouter)
 	secureRouter = middleware.SanitizeInputMiddleware(secureRouter)
 
-	// Rate limiting (100 requisições por janela)
-	rateLimiter := middleware.NewRateLimiter(100)
-	secureRouter = rateLimiter.Middleware(secureRouter)
+	// Proxies confiáveis para extrair o IP real do cliente
+	if invalid := middleware.SetTrustedProxies(splitAndTrim(cfg.TrustedProxies, ",")); len(invalid) > 0 {
+		log.Printf("Aviso: TRUSTED_PROXIES inválidos ignorados: %v", invalid)
+	}
+
+	secureRouter = middleware.RateLimitMiddleware(rateLimiter)(secureRouter)
 
 	addr := ":" + cfg.Port
 	fmt.Printf("🚀 API rodando em %s\n", addr)
@@ -67,6 +195,133 @@ func main() {
 	}
 }
 
+// startKeyRotation carrega (ou cria) as chaves JWT e agenda a rotação em background
+func startKeyRotation(cfg *config.Config, mongodb *database.MongoDB) {
+	keysRepo := repository.NewJWTKeysRepository(mongodb.Database)
+	if err := keysRepo.EnsureIndexes(context.Background()); err != nil {
+		log.Println("Aviso: erro ao criar índices de jwt_keys:", err)
+	}
+
+	rotation := auth.DefaultRotationConfig
+	rotation.Interval = cfg.JWTKeyRotation
+	rotator, err := auth.NewKeyRotator(keysRepo, rotation)
+	if err != nil {
+		log.Fatal("Erro ao configurar rotação de chaves JWT:", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := rotator.Sync(ctx); err != nil {
+		log.Fatal("Erro ao carregar chaves JWT:", err)
+	}
+	go rotator.Run(context.Background(), time.Minute)
+}
+
+// bootstrapAdmin cria o administrador de ADMIN_EMAIL se ele ainda não existir.
+// Os demais administradores são cadastrados direto no banco (não há rota pública).
+func bootstrapAdmin(cfg *config.Config, repo *admins.MongoRepository) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	email := auth.SanitizeEmail(cfg.AdminEmail)
+	if _, err := repo.GetByEmail(ctx, email); err == nil {
+		return
+	}
+
+	if err := auth.ValidatePasswordStrength(cfg.AdminPassword); err != nil {
+		log.Fatal("ADMIN_PASSWORD inválida:", err)
+	}
+	hashedPassword, err := auth.HashPassword(cfg.AdminPassword)
+	if err != nil {
+		log.Fatal("Erro ao processar ADMIN_PASSWORD:", err)
+	}
+
+	admin := &admins.Admin{
+		Name:     cfg.AdminName,
+		Email:    email,
+		Password: hashedPassword,
+	}
+	if err := repo.Create(ctx, admin); err != nil {
+		log.Fatal("Erro ao criar administrador inicial:", err)
+	}
+	log.Printf("Administrador inicial criado: %s", email)
+}
+
+// newRateLimitStore escolhe onde os contadores de rate limiting ficam conforme RATE_LIMIT_STORE
+func newRateLimitStore(cfg *config.Config, mongodb *database.MongoDB) ratelimit.Store {
+	if cfg.RateLimitStore == "mongo" {
+		store := ratelimit.NewMongoStore(mongodb.Database)
+		if err := store.EnsureIndexes(context.Background()); err != nil {
+			log.Println("Aviso: erro ao criar índices de rate_limits:", err)
+		}
+		return store
+	}
+	return ratelimit.NewMemoryStore(time.Minute)
+}
+
+// newEventsBroker escolhe como os eventos em tempo real chegam às instâncias conforme EVENTS_BROKER
+func newEventsBroker(cfg *config.Config, mongodb *database.MongoDB) events.Broker {
+	if cfg.EventsBroker == "mongo" {
+		broker := events.NewMongoBroker(mongodb.Database)
+		if err := broker.EnsureCollection(context.Background()); err != nil {
+			log.Println("Aviso: erro ao criar a collection events:", err)
+		}
+		return broker
+	}
+	return events.NewMemoryBroker(1024)
+}
+
+// companySectorLookup retorna o setor de cada empresa (consultando cada uma uma única vez) para o
+// preenchimento das vagas antigas
+func companySectorLookup(repo *companies.MongoRepository) func(companyID bson.ObjectID) string {
+	sectors := map[bson.ObjectID]string{}
+	return func(companyID bson.ObjectID) string {
+		sector, ok := sectors[companyID]
+		if !ok {
+			if company, err := repo.GetByID(context.Background(), companyID.Hex()); err == nil {
+				sector = company.Sector
+			}
+			sectors[companyID] = sector
+		}
+		return sector
+	}
+}
+
+// newCNPJLookup escolhe a consulta de CNPJ usada no cadastro de empresas conforme CNPJ_LOOKUP
+func newCNPJLookup(cfg *config.Config) companies.CNPJLookup {
+	switch cfg.CNPJLookup {
+	case "receitaws":
+		return companies.NewReceitaWSLookup(cfg.CNPJLookupURL, cfg.CNPJLookupToken)
+	case "fake":
+		if cfg.Environment == "production" {
+			log.Println("Aviso: CNPJ_LOOKUP=fake em produção, apenas os CNPJs de exemplo serão verificados")
+		}
+		return companies.NewFakeCNPJLookup(companies.FakeCNPJRecords...)
+	case "none", "":
+		return nil
+	default:
+		log.Printf("Aviso: CNPJ_LOOKUP %q desconhecido, consulta de CNPJ desativada", cfg.CNPJLookup)
+		return nil
+	}
+}
+
+// newMailSender escolhe o driver de envio de email conforme MAIL_DRIVER
+func newMailSender(cfg *config.Config) mail.Sender {
+	if cfg.MailDriver == "smtp" {
+		return mail.NewSMTPSender(mail.SMTPConfig{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     cfg.MailFrom,
+		})
+	}
+	if cfg.Environment == "production" {
+		log.Println("Aviso: MAIL_DRIVER não é 'smtp' em produção, emails serão apenas registrados em log")
+	}
+	return mail.NewLogSender(cfg.MailLogDir, cfg.MailFrom)
+}
+
 // parseOrigins converte string de origins separados por vírgula em slice
 func parseOrigins(origins string) []string {
 	if origins == "" {
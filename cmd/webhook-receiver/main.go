@@ -0,0 +1,74 @@
+// Receptor local de webhooks para desenvolvimento: confere a assinatura de cada entrega e
+// imprime o evento recebido. Cadastre http://localhost:9090/ como endpoint (fora de produção)
+// e informe o segredo devolvido no cadastro:
+//
+//	go run ./cmd/webhook-receiver -secret whsec_...
+//
+// Com -fail N as N primeiras entregas recebem 500, para exercitar o retry e o reenvio manual.
+package main
+
+import (
+	"bytes"
+	"empregabemapi/internal/webhooks"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync/atomic"
+)
+
+func main() {
+	addr := flag.String("addr", ":9090", "endereço de escuta")
+	secret := flag.String("secret", "", "segredo do endpoint (whsec_...); vazio = não confere a assinatura")
+	fail := flag.Int64("fail", 0, "responde 500 para as N primeiras entregas")
+	flag.Parse()
+
+	var received atomic.Int64
+
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+		if err != nil {
+			http.Error(w, "Erro ao ler corpo", http.StatusBadRequest)
+			return
+		}
+
+		n := received.Add(1)
+		log.Printf("#%d %s evento=%s event_id=%s entrega=%s",
+			n, r.URL.Path, r.Header.Get(webhooks.HeaderEvent), r.Header.Get(webhooks.HeaderEventID), r.Header.Get(webhooks.HeaderDelivery))
+
+		if *secret != "" {
+			if err := webhooks.Verify(r.Header.Get(webhooks.HeaderSignature), body, *secret, webhooks.DefaultTolerance); err != nil {
+				log.Printf("#%d assinatura rejeitada: %v", n, err)
+				http.Error(w, err.Error(), http.StatusUnauthorized)
+				return
+			}
+			log.Printf("#%d assinatura válida", n)
+		}
+
+		var pretty bytes.Buffer
+		if json.Indent(&pretty, body, "", "  ") == nil {
+			fmt.Println(pretty.String())
+		} else {
+			fmt.Println(string(body))
+		}
+
+		if n <= *fail {
+			log.Printf("#%d respondendo 500 (-fail %d)", n, *fail)
+			http.Error(w, "falha simulada", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	log.Printf("Receptor de webhooks ouvindo em %s", *addr)
+	if err := http.ListenAndServe(*addr, nil); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,66 @@
+package companies
+
+import "strings"
+
+// NormalizeCNPJ remove a pontuação de um CNPJ formatado ("12.345.678/0001-90" → "12345678000190").
+// Letras são convertidas para maiúsculas (CNPJ alfanumérico); outros caracteres são mantidos
+// para que ValidCNPJ os rejeite.
+func NormalizeCNPJ(cnpj string) string {
+	var b strings.Builder
+	for _, c := range strings.ToUpper(strings.TrimSpace(cnpj)) {
+		switch c {
+		case '.', '/', '-', ' ':
+			continue
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
+// ValidCNPJ verifica tamanho e dígitos verificadores de um CNPJ já normalizado.
+// Aceita também o formato alfanumérico (raiz e ordem com 0-9/A-Z, dígitos verificadores numéricos).
+func ValidCNPJ(cnpj string) bool {
+	if len(cnpj) != 14 {
+		return false
+	}
+
+	values := make([]int, 14)
+	allEqual := true
+	for i := 0; i < 14; i++ {
+		c := cnpj[i]
+		switch {
+		case c >= '0' && c <= '9':
+		case c >= 'A' && c <= 'Z' && i < 12:
+		default:
+			return false
+		}
+		// Valor do caractere = código ASCII - 48 (dígitos continuam valendo 0-9)
+		values[i] = int(c) - '0'
+		if c != cnpj[0] {
+			allEqual = false
+		}
+	}
+	// "00000000000000", "11111111111111"... passam no cálculo mas não são válidos
+	if allEqual {
+		return false
+	}
+
+	return cnpjCheckDigit(values[:12]) == values[12] && cnpjCheckDigit(values[:13]) == values[13]
+}
+
+// cnpjCheckDigit calcula o dígito verificador (módulo 11) para os 12 ou 13 primeiros valores
+func cnpjCheckDigit(values []int) int {
+	weight := len(values) - 7 // 5 para o 1º dígito, 6 para o 2º
+	sum := 0
+	for _, v := range values {
+		sum += v * weight
+		weight--
+		if weight < 2 {
+			weight = 9
+		}
+	}
+	if rest := sum % 11; rest >= 2 {
+		return 11 - rest
+	}
+	return 0
+}
@@ -0,0 +1,245 @@
+package companies
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Situação cadastral de empresas ativas na Receita Federal
+const CNPJStatusActive = "ATIVA"
+
+var ErrCNPJNotFound = errors.New("CNPJ não encontrado")
+
+// CNPJInfo são os dados cadastrais públicos de um CNPJ
+type CNPJInfo struct {
+	CNPJ      string
+	LegalName string // razão social
+	TradeName string // nome fantasia
+	Status    string // situação cadastral ("ATIVA", "BAIXADA", "SUSPENSA"...)
+	Address   Address
+	Email     string
+	Phone     string
+}
+
+// CNPJLookup consulta os dados cadastrais de um CNPJ (ReceitaWS, BrasilAPI, fake local...)
+type CNPJLookup interface {
+	Lookup(ctx context.Context, cnpj string) (*CNPJInfo, error)
+}
+
+// MatchesLegalName indica se a empresa está ativa e a razão social informada confere com a da consulta
+func (i *CNPJInfo) MatchesLegalName(legalName string) bool {
+	if i.Status != CNPJStatusActive || strings.TrimSpace(legalName) == "" {
+		return false
+	}
+	return normalizeLegalName(legalName) == normalizeLegalName(i.LegalName)
+}
+
+// MatchesContact indica se o email ou o telefone do cadastro confere com o registro do CNPJ.
+// Razão social e CNPJ são públicos: sem um contato que só a própria empresa controla, qualquer um
+// poderia se cadastrar como ela. Vale o mesmo email, o mesmo domínio (exceto provedores gratuitos,
+// ex: gmail.com) ou o mesmo telefone com DDD.
+func (i *CNPJInfo) MatchesContact(email, phone string) bool {
+	email = strings.ToLower(strings.TrimSpace(email))
+	recordEmail := strings.ToLower(strings.TrimSpace(i.Email))
+	if email != "" && recordEmail != "" {
+		if email == recordEmail {
+			return true
+		}
+		domain, recordDomain := emailDomain(email), emailDomain(recordEmail)
+		if domain != "" && domain == recordDomain && !freeEmailDomains[domain] {
+			return true
+		}
+	}
+
+	digits, recordDigits := phoneDigits(phone), phoneDigits(i.Phone)
+	return len(digits) >= 10 && digits == recordDigits
+}
+
+// Provedores de email gratuitos: o domínio não identifica a empresa
+var freeEmailDomains = map[string]bool{
+	"gmail.com": true, "googlemail.com": true, "hotmail.com": true, "hotmail.com.br": true,
+	"outlook.com": true, "outlook.com.br": true, "live.com": true, "yahoo.com": true,
+	"yahoo.com.br": true, "icloud.com": true, "bol.com.br": true, "uol.com.br": true,
+	"terra.com.br": true, "ig.com.br": true, "protonmail.com": true,
+}
+
+func emailDomain(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at == -1 {
+		return ""
+	}
+	return email[at+1:]
+}
+
+// phoneDigits mantém só os dígitos do telefone, sem o código do país ("+55 (11) 3333-4444" → "1133334444")
+func phoneDigits(phone string) string {
+	var b strings.Builder
+	for _, c := range phone {
+		if c >= '0' && c <= '9' {
+			b.WriteRune(c)
+		}
+	}
+	digits := b.String()
+	if len(digits) > 11 && strings.HasPrefix(digits, "55") {
+		digits = digits[2:]
+	}
+	return digits
+}
+
+var legalNameReplacer = strings.NewReplacer(
+	"Á", "A", "À", "A", "Â", "A", "Ã", "A", "Ä", "A",
+	"É", "E", "Ê", "E", "È", "E",
+	"Í", "I", "Î", "I",
+	"Ó", "O", "Ô", "O", "Õ", "O", "Ö", "O",
+	"Ú", "U", "Ü", "U",
+	"Ç", "C",
+	"LIMITADA", "LTDA",
+)
+
+// normalizeLegalName ignora caixa, acentos, pontuação e espaços ("Tech Soluções Ltda." == "TECH SOLUCOES LTDA")
+func normalizeLegalName(name string) string {
+	name = legalNameReplacer.Replace(strings.ToUpper(name))
+	var b strings.Builder
+	for _, c := range name {
+		if (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') {
+			b.WriteRune(c)
+		}
+	}
+	return b.String()
+}
+
+// ReceitaWSLookup consulta a API da ReceitaWS (GET {baseURL}/cnpj/{cnpj})
+type ReceitaWSLookup struct {
+	baseURL string
+	token   string
+	client  *http.Client
+}
+
+// NewReceitaWSLookup cria o cliente. token é opcional (plano comercial, sem limite de 3 consultas/min).
+func NewReceitaWSLookup(baseURL, token string) *ReceitaWSLookup {
+	return &ReceitaWSLookup{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		token:   token,
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type receitaWSResponse struct {
+	Status      string `json:"status"` // "OK" ou "ERROR"
+	Message     string `json:"message"`
+	CNPJ        string `json:"cnpj"`
+	Nome        string `json:"nome"`
+	Fantasia    string `json:"fantasia"`
+	Situacao    string `json:"situacao"`
+	Logradouro  string `json:"logradouro"`
+	Numero      string `json:"numero"`
+	Complemento string `json:"complemento"`
+	Bairro      string `json:"bairro"`
+	Municipio   string `json:"municipio"`
+	UF          string `json:"uf"`
+	CEP         string `json:"cep"`
+	Email       string `json:"email"`
+	Telefone    string `json:"telefone"`
+}
+
+func (l *ReceitaWSLookup) Lookup(ctx context.Context, cnpj string) (*CNPJInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, l.baseURL+"/cnpj/"+cnpj, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if l.token != "" {
+		req.Header.Set("Authorization", "Bearer "+l.token)
+	}
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrCNPJNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("receitaws: status %d", resp.StatusCode)
+	}
+
+	var data receitaWSResponse
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return nil, fmt.Errorf("receitaws: resposta inválida: %w", err)
+	}
+	if data.Status != "OK" {
+		// A API responde 200 com status ERROR para CNPJ inexistente ou rejeitado
+		return nil, ErrCNPJNotFound
+	}
+
+	return &CNPJInfo{
+		CNPJ:      NormalizeCNPJ(data.CNPJ),
+		LegalName: data.Nome,
+		TradeName: data.Fantasia,
+		Status:    strings.ToUpper(data.Situacao),
+		Address: Address{
+			Street:     data.Logradouro,
+			Number:     data.Numero,
+			Complement: data.Complemento,
+			District:   data.Bairro,
+			City:       data.Municipio,
+			State:      data.UF,
+			ZipCode:    strings.NewReplacer(".", "", "-", "").Replace(data.CEP),
+		},
+		Email: data.Email,
+		Phone: data.Telefone,
+	}, nil
+}
+
+// FakeCNPJLookup responde a partir de registros em memória (desenvolvimento e testes, sem rede)
+type FakeCNPJLookup struct {
+	records map[string]CNPJInfo
+}
+
+func NewFakeCNPJLookup(records ...CNPJInfo) *FakeCNPJLookup {
+	l := &FakeCNPJLookup{records: make(map[string]CNPJInfo)}
+	for _, record := range records {
+		l.records[NormalizeCNPJ(record.CNPJ)] = record
+	}
+	return l
+}
+
+func (l *FakeCNPJLookup) Lookup(ctx context.Context, cnpj string) (*CNPJInfo, error) {
+	record, ok := l.records[NormalizeCNPJ(cnpj)]
+	if !ok {
+		return nil, ErrCNPJNotFound
+	}
+	return &record, nil
+}
+
+// FakeCNPJRecords são os CNPJs de exemplo usados com CNPJ_LOOKUP=fake
+var FakeCNPJRecords = []CNPJInfo{
+	{
+		CNPJ:      "11222333000181",
+		LegalName: "Tech Solutions Tecnologia Ltda",
+		TradeName: "Tech Solutions",
+		Status:    CNPJStatusActive,
+		Address: Address{
+			Street: "Avenida Paulista", Number: "1000", District: "Bela Vista",
+			City: "São Paulo", State: "SP", ZipCode: "01310100",
+		},
+		Email: "financeiro@techsolutions.com",
+		Phone: "(11) 3333-4444",
+	},
+	{
+		CNPJ:      "45997418000153",
+		LegalName: "Comércio Exemplo Baixado ME",
+		Status:    "BAIXADA",
+		Address: Address{
+			Street: "Rua das Flores", Number: "12", District: "Centro",
+			City: "Curitiba", State: "PR", ZipCode: "80020000",
+		},
+	},
+}
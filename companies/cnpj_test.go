@@ -0,0 +1,122 @@
+package companies
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestNormalizeCNPJ(t *testing.T) {
+	tests := map[string]string{
+		"11.222.333/0001-81":   "11222333000181",
+		" 11222333000181 ":     "11222333000181",
+		"12.abc.345/01de-35":   "12ABC34501DE35",
+		"11.222.333/0001-8x":   "1122233300018X",
+		"11_222_333_0001_81":   "11_222_333_0001_81",
+		"":                     "",
+		"11 222 333 0001 81  ": "11222333000181",
+	}
+	for input, want := range tests {
+		if got := NormalizeCNPJ(input); got != want {
+			t.Errorf("NormalizeCNPJ(%q) = %q, esperado %q", input, got, want)
+		}
+	}
+}
+
+func TestValidCNPJ(t *testing.T) {
+	tests := []struct {
+		cnpj string
+		want bool
+	}{
+		{"11222333000181", true},
+		{"45997418000153", true},
+		{"12ABC34501DE35", true}, // exemplo alfanumérico da Receita
+		{"11222333000182", false},
+		{"11222333000191", false},
+		{"12ABC34501DE36", false},
+		{"00000000000000", false},
+		{"11111111111111", false},
+		{"1122233300018", false},
+		{"112223330001811", false},
+		{"1122233300018A", false}, // dígitos verificadores são sempre numéricos
+		{"11.222.333/0001-81", false},
+		{"12abc34501de35", false}, // ValidCNPJ espera o CNPJ já normalizado
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := ValidCNPJ(tt.cnpj); got != tt.want {
+			t.Errorf("ValidCNPJ(%q) = %v, esperado %v", tt.cnpj, got, tt.want)
+		}
+	}
+}
+
+func TestCNPJInfoMatchesLegalName(t *testing.T) {
+	info := &CNPJInfo{LegalName: "TECH SOLUÇÕES TECNOLOGIA LIMITADA", Status: CNPJStatusActive}
+
+	tests := []struct {
+		legalName string
+		want      bool
+	}{
+		{"Tech Soluções Tecnologia Ltda.", true},
+		{"tech solucoes tecnologia ltda", true},
+		{"TECH SOLUÇÕES TECNOLOGIA LIMITADA", true},
+		{"Tech Soluções", false},
+		{"", false},
+		{"   ", false},
+	}
+	for _, tt := range tests {
+		if got := info.MatchesLegalName(tt.legalName); got != tt.want {
+			t.Errorf("MatchesLegalName(%q) = %v, esperado %v", tt.legalName, got, tt.want)
+		}
+	}
+
+	inactive := &CNPJInfo{LegalName: info.LegalName, Status: "BAIXADA"}
+	if inactive.MatchesLegalName("Tech Soluções Tecnologia Ltda") {
+		t.Error("empresa baixada não pode conferir")
+	}
+}
+
+func TestCNPJInfoMatchesContact(t *testing.T) {
+	info := &CNPJInfo{Email: "Financeiro@TechSolutions.com.br", Phone: "(11) 3333-4444"}
+	webmail := &CNPJInfo{Email: "techsolutions@gmail.com"}
+
+	tests := []struct {
+		name         string
+		info         *CNPJInfo
+		email, phone string
+		want         bool
+	}{
+		{"mesmo email", info, "financeiro@techsolutions.com.br", "", true},
+		{"mesmo domínio", info, "rh@techsolutions.com.br", "", true},
+		{"domínio diferente", info, "rh@techsolutions.com", "", false},
+		{"subdomínio não conta", info, "rh@mail.techsolutions.com.br", "", false},
+		{"mesmo telefone formatado de outro jeito", info, "rh@outra.com", "+55 11 3333-4444", true},
+		{"telefone diferente", info, "rh@outra.com", "(11) 3333-5555", false},
+		{"telefone sem DDD", info, "rh@outra.com", "3333-4444", false},
+		{"sem contato", info, "", "", false},
+		{"provedor gratuito: só o mesmo email", webmail, "techsolutions@gmail.com", "", true},
+		{"provedor gratuito: domínio não basta", webmail, "golpista@gmail.com", "", false},
+		{"registro sem contatos", &CNPJInfo{}, "rh@techsolutions.com.br", "(11) 3333-4444", false},
+	}
+	for _, tt := range tests {
+		if got := tt.info.MatchesContact(tt.email, tt.phone); got != tt.want {
+			t.Errorf("%s: MatchesContact(%q, %q) = %v, esperado %v", tt.name, tt.email, tt.phone, got, tt.want)
+		}
+	}
+}
+
+func TestFakeCNPJLookup(t *testing.T) {
+	lookup := NewFakeCNPJLookup(FakeCNPJRecords...)
+
+	info, err := lookup.Lookup(context.Background(), "11.222.333/0001-81")
+	if err != nil {
+		t.Fatalf("Lookup: %v", err)
+	}
+	if !info.MatchesLegalName("Tech Solutions Tecnologia Ltda") || !info.MatchesContact("contato@techsolutions.com", "") {
+		t.Errorf("registro de exemplo não confere: %+v", info)
+	}
+
+	if _, err := lookup.Lookup(context.Background(), "12ABC34501DE35"); !errors.Is(err, ErrCNPJNotFound) {
+		t.Errorf("esperado ErrCNPJNotFound, veio %v", err)
+	}
+}
@@ -0,0 +1,101 @@
+package companies
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Role é o papel de um usuário dentro da empresa
+type Role string
+
+const (
+	RoleOwner     Role = "owner"     // conta principal da empresa (login com o email da empresa)
+	RoleAdmin     Role = "admin"     // gerencia perfil, vagas, candidaturas e equipe
+	RoleRecruiter Role = "recruiter" // gerencia vagas e candidaturas
+	RoleViewer    Role = "viewer"    // apenas visualiza
+)
+
+// Permission é uma ação do lado da empresa controlada por papel
+type Permission string
+
+const (
+	PermViewCompany        Permission = "company:view"
+	PermEditCompany        Permission = "company:edit"
+	PermManageMembers      Permission = "members:manage"
+	PermViewJobs           Permission = "jobs:view"
+	PermManageJobs         Permission = "jobs:manage"
+	PermAssignHiringTeam   Permission = "jobs:assign_team"
+	PermAccessAllJobs      Permission = "jobs:all" // candidatos de qualquer vaga, sem estar na equipe
+	PermViewApplications   Permission = "applications:view"
+	PermManageApplications Permission = "applications:manage"
+	PermManageWebhooks     Permission = "webhooks:manage" // endpoints de integração (ATS) e seus segredos
+	PermSearchTalent       Permission = "talent:search"   // banco de talentos: busca e perfis
+	PermContactTalent      Permission = "talent:contact"  // banco de talentos: pedidos de contato
+)
+
+var rolePermissions = map[Role][]Permission{
+	RoleOwner: {
+		PermViewCompany, PermEditCompany, PermManageMembers,
+		PermViewJobs, PermManageJobs, PermAssignHiringTeam, PermAccessAllJobs,
+		PermViewApplications, PermManageApplications, PermManageWebhooks,
+		PermSearchTalent, PermContactTalent,
+	},
+	RoleAdmin: {
+		PermViewCompany, PermEditCompany, PermManageMembers,
+		PermViewJobs, PermManageJobs, PermAssignHiringTeam, PermAccessAllJobs,
+		PermViewApplications, PermManageApplications, PermManageWebhooks,
+		PermSearchTalent, PermContactTalent,
+	},
+	RoleRecruiter: {
+		PermViewCompany, PermViewJobs, PermManageJobs, PermViewApplications, PermManageApplications,
+		PermSearchTalent, PermContactTalent,
+	},
+	RoleViewer: {
+		PermViewCompany, PermViewJobs, PermViewApplications, PermSearchTalent,
+	},
+}
+
+// Valid indica se o papel existe
+func (r Role) Valid() bool {
+	_, ok := rolePermissions[r]
+	return ok
+}
+
+// Can indica se o papel tem a permissão
+func (r Role) Can(p Permission) bool {
+	for _, perm := range rolePermissions[r] {
+		if perm == p {
+			return true
+		}
+	}
+	return false
+}
+
+// Member é um usuário adicional de uma empresa (recrutador, gestor...).
+// O dono continua sendo o documento Company; membros têm login próprio.
+type Member struct {
+	ID               bson.ObjectID `bson:"_id,omitempty" json:"id"`
+	CompanyID        bson.ObjectID `bson:"company_id" json:"company_id"`
+	Name             string        `bson:"name" json:"name"`
+	Email            string        `bson:"email" json:"email"`
+	Password         string        `bson:"password" json:"-"`
+	Role             Role          `bson:"role" json:"role"` // "admin", "recruiter" ou "viewer"
+	InvitedBy        string        `bson:"invited_by,omitempty" json:"invited_by,omitempty"`
+	TokensValidAfter *time.Time    `bson:"tokens_valid_after,omitempty" json:"-"` // tokens emitidos antes disso são rejeitados
+	CreatedAt        time.Time     `bson:"created_at" json:"created_at"`
+	UpdatedAt        time.Time     `bson:"updated_at" json:"updated_at"`
+}
+
+// Invitation é um convite pendente para entrar na equipe de uma empresa
+type Invitation struct {
+	ID        bson.ObjectID `bson:"_id,omitempty" json:"id"`
+	CompanyID bson.ObjectID `bson:"company_id" json:"company_id"`
+	Email     string        `bson:"email" json:"email"`
+	Role      Role          `bson:"role" json:"role"`
+	TokenHash string        `bson:"token_hash" json:"-"` // SHA-256 do token enviado por email
+	InvitedBy string        `bson:"invited_by" json:"invited_by"`
+	Used      bool          `bson:"used" json:"-"`
+	CreatedAt time.Time     `bson:"created_at" json:"created_at"`
+	ExpiresAt time.Time     `bson:"expires_at" json:"expires_at"`
+}
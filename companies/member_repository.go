@@ -0,0 +1,204 @@
+package companies
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// ErrInvitationUsed indica que o convite já foi aceito, revogado ou expirou
+var ErrInvitationUsed = errors.New("convite já utilizado ou expirado")
+
+type MemberRepository struct {
+	members     *mongo.Collection
+	invitations *mongo.Collection
+}
+
+func NewMemberRepository(db *mongo.Database) *MemberRepository {
+	return &MemberRepository{
+		members:     db.Collection("company_members"),
+		invitations: db.Collection("company_invitations"),
+	}
+}
+
+// EnsureIndexes cria email único para membros, índice por empresa e TTL dos convites
+func (r *MemberRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.members.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "email", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "company_id", Value: 1}}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = r.invitations.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+		{
+			Keys:    bson.D{{Key: "token_hash", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "company_id", Value: 1}, {Key: "email", Value: 1}}},
+	})
+	return err
+}
+
+func (r *MemberRepository) Create(ctx context.Context, member *Member) error {
+	member.ID = bson.NewObjectID()
+	member.CreatedAt = time.Now()
+	member.UpdatedAt = time.Now()
+
+	_, err := r.members.InsertOne(ctx, member)
+	return err
+}
+
+func (r *MemberRepository) GetByID(ctx context.Context, id string) (*Member, error) {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var member Member
+	err = r.members.FindOne(ctx, bson.M{"_id": objectID}).Decode(&member)
+	if err != nil {
+		return nil, err
+	}
+
+	return &member, nil
+}
+
+func (r *MemberRepository) GetByEmail(ctx context.Context, email string) (*Member, error) {
+	var member Member
+	err := r.members.FindOne(ctx, bson.M{"email": email}).Decode(&member)
+	if err != nil {
+		return nil, err
+	}
+
+	return &member, nil
+}
+
+// ListByCompany lista os membros da empresa em ordem de entrada
+func (r *MemberRepository) ListByCompany(ctx context.Context, companyID string) ([]*Member, error) {
+	objectID, err := bson.ObjectIDFromHex(companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cursor, err := r.members.Find(ctx, bson.M{"company_id": objectID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var members []*Member
+	if err = cursor.All(ctx, &members); err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
+
+func (r *MemberRepository) Update(ctx context.Context, member *Member) error {
+	member.UpdatedAt = time.Now()
+	filter := bson.M{"_id": member.ID}
+	update := bson.M{"$set": member}
+
+	_, err := r.members.UpdateOne(ctx, filter, update)
+	return err
+}
+
+func (r *MemberRepository) Delete(ctx context.Context, id bson.ObjectID) error {
+	_, err := r.members.DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
+
+// CreateInvitation salva um convite, revogando convites pendentes para o mesmo email na empresa
+func (r *MemberRepository) CreateInvitation(ctx context.Context, invitation *Invitation) error {
+	_, err := r.invitations.UpdateMany(ctx, bson.M{
+		"company_id": invitation.CompanyID,
+		"email":      invitation.Email,
+		"used":       false,
+	}, bson.M{"$set": bson.M{"used": true}})
+	if err != nil {
+		return err
+	}
+
+	invitation.ID = bson.NewObjectID()
+	invitation.CreatedAt = time.Now()
+	invitation.Used = false
+
+	_, err = r.invitations.InsertOne(ctx, invitation)
+	return err
+}
+
+// GetInvitationByToken busca um convite pendente pelo hash do token
+func (r *MemberRepository) GetInvitationByToken(ctx context.Context, tokenHash string) (*Invitation, error) {
+	var invitation Invitation
+	err := r.invitations.FindOne(ctx, bson.M{
+		"token_hash": tokenHash,
+		"used":       false,
+		"expires_at": bson.M{"$gt": time.Now()},
+	}).Decode(&invitation)
+	if err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// ListPendingInvitations lista os convites ainda não aceitos da empresa
+func (r *MemberRepository) ListPendingInvitations(ctx context.Context, companyID string) ([]*Invitation, error) {
+	objectID, err := bson.ObjectIDFromHex(companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := r.invitations.Find(ctx, bson.M{
+		"company_id": objectID,
+		"used":       false,
+		"expires_at": bson.M{"$gt": time.Now()},
+	}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var invitations []*Invitation
+	if err = cursor.All(ctx, &invitations); err != nil {
+		return nil, err
+	}
+
+	return invitations, nil
+}
+
+// MarkInvitationUsed consome o convite de forma atômica (aceite ou revogação).
+// companyID restringe a empresa dona do convite quando não for zero.
+func (r *MemberRepository) MarkInvitationUsed(ctx context.Context, id, companyID bson.ObjectID) error {
+	filter := bson.M{
+		"_id":        id,
+		"used":       false,
+		"expires_at": bson.M{"$gt": time.Now()},
+	}
+	if !companyID.IsZero() {
+		filter["company_id"] = companyID
+	}
+
+	result, err := r.invitations.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"used": true}})
+	if err != nil {
+		return err
+	}
+	if result.ModifiedCount == 0 {
+		return ErrInvitationUsed
+	}
+	return nil
+}
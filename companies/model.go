@@ -7,22 +7,83 @@ import (
 )
 
 type Company struct {
-	ID                 bson.ObjectID `bson:"_id,omitempty" json:"id"`
-	Name               string        `bson:"name" json:"name" validate:"required"`
-	LegalName          string        `bson:"legal_name" json:"legal_name" validate:"required"`
-	CNPJ               string        `bson:"cnpj" json:"cnpj" validate:"required"`
-	Email              string        `bson:"email" json:"email" validate:"required,email"`
-	Password           string        `bson:"password" json:"-"`
-	Phone              string        `bson:"phone" json:"phone"`
-	Website            string        `bson:"website,omitempty" json:"website,omitempty"`
-	Logo               string        `bson:"logo,omitempty" json:"logo,omitempty"`
-	About              string        `bson:"about,omitempty" json:"about,omitempty"`
-	EmployeeCount      string        `bson:"employee_count,omitempty" json:"employee_count,omitempty"` // "1-10", "11-50", "51-200", "201-500", "500+"
-	Location           string        `bson:"location" json:"location"`
-	Sector             string        `bson:"sector,omitempty" json:"sector,omitempty"`
-	VerificationStatus string        `bson:"verification_status" json:"verification_status"` // "pending", "verified", "rejected"
-	CreatedAt          time.Time     `bson:"created_at" json:"created_at"`
-	UpdatedAt          time.Time     `bson:"updated_at" json:"updated_at"`
+	ID                     bson.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name                   string        `bson:"name" json:"name" validate:"required"`
+	Slug                   string        `bson:"slug,omitempty" json:"slug"` // endereço público único (/companies/{slug}); não muda quando a empresa é renomeada
+	LegalName              string        `bson:"legal_name" json:"legal_name" validate:"required"`
+	CNPJ                   string        `bson:"cnpj" json:"cnpj" validate:"required"`
+	Email                  string        `bson:"email" json:"email" validate:"required,email"`
+	Password               string        `bson:"password" json:"-"`
+	Phone                  string        `bson:"phone" json:"phone"`
+	Website                string        `bson:"website,omitempty" json:"website,omitempty"`
+	Logo                   string        `bson:"logo,omitempty" json:"logo,omitempty"`             // URL do logo (a maior variante quando enviado por upload)
+	LogoImage              *Image        `bson:"logo_image,omitempty" json:"logo_image,omitempty"` // logo enviado por upload (POST /company/me/logo)
+	CoverImage             *Image        `bson:"cover_image,omitempty" json:"cover_image,omitempty"`
+	About                  string        `bson:"about,omitempty" json:"about,omitempty"`
+	EmployeeCount          string        `bson:"employee_count,omitempty" json:"employee_count,omitempty"` // "1-10", "11-50", "51-200", "201-500", "500+"
+	Location               string        `bson:"location" json:"location"`
+	Address                *Address      `bson:"address,omitempty" json:"address,omitempty"` // preenchido pela consulta de CNPJ
+	Sector                 string        `bson:"sector,omitempty" json:"sector,omitempty"`
+	VerificationStatus     string        `bson:"verification_status" json:"verification_status"`                     // "pending", "verified", "rejected"
+	VerificationReason     string        `bson:"verification_reason,omitempty" json:"verification_reason,omitempty"` // motivo informado pelo admin (obrigatório na rejeição)
+	VerificationReviewedAt *time.Time    `bson:"verification_reviewed_at,omitempty" json:"verification_reviewed_at,omitempty"`
+	SuspendedAt            *time.Time    `bson:"suspended_at,omitempty" json:"suspended_at,omitempty"` // conta suspensa por um admin: login e sessões bloqueados
+	SuspensionReason       string        `bson:"suspension_reason,omitempty" json:"suspension_reason,omitempty"`
+	TokensValidAfter       *time.Time    `bson:"tokens_valid_after,omitempty" json:"-"` // tokens emitidos antes disso são rejeitados
+	CreatedAt              time.Time     `bson:"created_at" json:"created_at"`
+	UpdatedAt              time.Time     `bson:"updated_at" json:"updated_at"`
+}
+
+// Address é o endereço cadastral da empresa (Receita Federal)
+type Address struct {
+	Street     string `bson:"street" json:"street"`
+	Number     string `bson:"number" json:"number"`
+	Complement string `bson:"complement,omitempty" json:"complement,omitempty"`
+	District   string `bson:"district" json:"district"`
+	City       string `bson:"city" json:"city"`
+	State      string `bson:"state" json:"state"`
+	ZipCode    string `bson:"zip_code" json:"zip_code"`
+}
+
+// Image é uma imagem enviada por upload, já processada em variantes redimensionadas
+type Image struct {
+	Variants   map[string]string `bson:"variants" json:"variants"` // tamanho em px -> URL ("64", "128", "256")
+	Width      int               `bson:"width" json:"width"`       // dimensões do arquivo original
+	Height     int               `bson:"height" json:"height"`
+	Files      []string          `bson:"files" json:"-"` // chaves no storage (removidas quando a imagem é trocada)
+	UploadedAt time.Time         `bson:"uploaded_at" json:"uploaded_at"`
+}
+
+// PublicProfile é o subconjunto do perfil exibido publicamente (sem CNPJ, contatos ou dados internos)
+type PublicProfile struct {
+	ID            bson.ObjectID `json:"id"`
+	Slug          string        `json:"slug"`
+	Name          string        `json:"name"`
+	Logo          string        `json:"logo,omitempty"`
+	LogoImage     *Image        `json:"logo_image,omitempty"`
+	CoverImage    *Image        `json:"cover_image,omitempty"`
+	About         string        `json:"about,omitempty"`
+	Sector        string        `json:"sector,omitempty"`
+	EmployeeCount string        `json:"employee_count,omitempty"`
+	Location      string        `json:"location,omitempty"`
+	Verified      bool          `json:"verified"`
+}
+
+// PublicProfile retorna os dados seguros para a página pública da empresa
+func (c *Company) PublicProfile() PublicProfile {
+	return PublicProfile{
+		ID:            c.ID,
+		Slug:          c.Slug,
+		Name:          c.Name,
+		Logo:          c.Logo,
+		LogoImage:     c.LogoImage,
+		CoverImage:    c.CoverImage,
+		About:         c.About,
+		Sector:        c.Sector,
+		EmployeeCount: c.EmployeeCount,
+		Location:      c.Location,
+		Verified:      c.VerificationStatus == "verified",
+	}
 }
 
 type CompanyRepository interface {
@@ -2,10 +2,12 @@ package companies
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
 
 type MongoRepository struct {
@@ -18,14 +20,39 @@ func NewMongoRepository(db *mongo.Database) *MongoRepository {
 	}
 }
 
+// EnsureIndexes cria o índice único de slug (empresas antigas sem slug ficam fora do índice)
+func (r *MongoRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "slug", Value: 1}},
+		Options: options.Index().SetUnique(true).
+			SetPartialFilterExpression(bson.M{"slug": bson.M{"$type": "string"}}),
+	})
+	return err
+}
+
 func (r *MongoRepository) Create(ctx context.Context, company *Company) error {
 	company.ID = bson.NewObjectID()
 	company.CreatedAt = time.Now()
 	company.UpdatedAt = time.Now()
-	company.VerificationStatus = "pending"
+	// Pode já vir "verified" quando a consulta de CNPJ confere no cadastro
+	if company.VerificationStatus == "" {
+		company.VerificationStatus = "pending"
+	}
 
-	_, err := r.collection.InsertOne(ctx, company)
-	return err
+	// Outra empresa com o mesmo nome pode ter pegado o slug entre a consulta e a inserção
+	for attempt := 0; attempt < 3; attempt++ {
+		slug, err := r.AvailableSlug(ctx, Slugify(company.Name), company.ID)
+		if err != nil {
+			return err
+		}
+		company.Slug = slug
+
+		_, err = r.collection.InsertOne(ctx, company)
+		if !mongo.IsDuplicateKeyError(err) {
+			return err
+		}
+	}
+	return ErrSlugTaken
 }
 
 func (r *MongoRepository) GetByID(ctx context.Context, id string) (*Company, error) {
@@ -63,12 +90,79 @@ func (r *MongoRepository) GetByCNPJ(ctx context.Context, cnpj string) (*Company,
 	return &company, nil
 }
 
+// GetBySlug busca a empresa pelo endereço público
+func (r *MongoRepository) GetBySlug(ctx context.Context, slug string) (*Company, error) {
+	var company Company
+	err := r.collection.FindOne(ctx, bson.M{"slug": slug}).Decode(&company)
+	if err != nil {
+		return nil, err
+	}
+
+	return &company, nil
+}
+
+// AvailableSlug retorna o primeiro slug livre a partir da base ("acme", "acme-2", "acme-3"...),
+// ignorando a própria empresa
+func (r *MongoRepository) AvailableSlug(ctx context.Context, base string, companyID bson.ObjectID) (string, error) {
+	slug := base
+	for n := 2; ; n++ {
+		count, err := r.collection.CountDocuments(ctx, bson.M{"slug": slug, "_id": bson.M{"$ne": companyID}})
+		if err != nil {
+			return "", err
+		}
+		if count == 0 {
+			return slug, nil
+		}
+
+		suffix := fmt.Sprintf("-%d", n)
+		if len(base)+len(suffix) > MaxSlugLength {
+			base = base[:MaxSlugLength-len(suffix)]
+		}
+		slug = base + suffix
+	}
+}
+
+// BackfillSlugs gera o slug das empresas cadastradas antes do perfil público
+func (r *MongoRepository) BackfillSlugs(ctx context.Context) (int, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"slug": bson.M{"$exists": false}})
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var pending []*Company
+	if err = cursor.All(ctx, &pending); err != nil {
+		return 0, err
+	}
+
+	updated := 0
+	for _, company := range pending {
+		slug, err := r.AvailableSlug(ctx, Slugify(company.Name), company.ID)
+		if err != nil {
+			return updated, err
+		}
+		_, err = r.collection.UpdateOne(ctx,
+			bson.M{"_id": company.ID, "slug": bson.M{"$exists": false}},
+			bson.M{"$set": bson.M{"slug": slug}},
+		)
+		if err != nil {
+			return updated, err
+		}
+		updated++
+	}
+
+	return updated, nil
+}
+
 func (r *MongoRepository) Update(ctx context.Context, company *Company) error {
 	company.UpdatedAt = time.Now()
 	filter := bson.M{"_id": company.ID}
 	update := bson.M{"$set": company}
 
 	_, err := r.collection.UpdateOne(ctx, filter, update)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrSlugTaken
+	}
 	return err
 }
 
@@ -81,3 +175,83 @@ func (r *MongoRepository) Delete(ctx context.Context, id string) error {
 	_, err = r.collection.DeleteOne(ctx, bson.M{"_id": objectID})
 	return err
 }
+
+// SetLogo grava o logo enviado por upload (o campo logo passa a apontar para a maior variante).
+// Com img nil, remove o logo.
+func (r *MongoRepository) SetLogo(ctx context.Context, id bson.ObjectID, img *Image, url string) error {
+	update := bson.M{"$set": bson.M{"logo_image": img, "logo": url, "updated_at": time.Now()}}
+	if img == nil {
+		update = bson.M{
+			"$set":   bson.M{"updated_at": time.Now()},
+			"$unset": bson.M{"logo_image": "", "logo": ""},
+		}
+	}
+
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	return err
+}
+
+// SetCover grava a imagem de capa enviada por upload; com img nil, remove a capa
+func (r *MongoRepository) SetCover(ctx context.Context, id bson.ObjectID, img *Image) error {
+	update := bson.M{"$set": bson.M{"cover_image": img, "updated_at": time.Now()}}
+	if img == nil {
+		update = bson.M{
+			"$set":   bson.M{"updated_at": time.Now()},
+			"$unset": bson.M{"cover_image": ""},
+		}
+	}
+
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	return err
+}
+
+// ListByVerificationStatus lista empresas por status de verificação (mais antigas primeiro, como fila)
+func (r *MongoRepository) ListByVerificationStatus(ctx context.Context, status string, limit int64) ([]*Company, error) {
+	filter := bson.M{}
+	if status != "" {
+		filter["verification_status"] = status
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}).SetLimit(limit)
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var companies []*Company
+	if err = cursor.All(ctx, &companies); err != nil {
+		return nil, err
+	}
+
+	return companies, nil
+}
+
+// SetVerification registra a decisão de verificação da empresa
+func (r *MongoRepository) SetVerification(ctx context.Context, id bson.ObjectID, status, reason string) error {
+	now := time.Now()
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{
+		"verification_status":      status,
+		"verification_reason":      reason,
+		"verification_reviewed_at": now,
+		"updated_at":               now,
+	}})
+	return err
+}
+
+// SetSuspended suspende (com motivo) ou reativa a conta da empresa
+func (r *MongoRepository) SetSuspended(ctx context.Context, id bson.ObjectID, suspended bool, reason string) error {
+	now := time.Now()
+	update := bson.M{
+		"$set": bson.M{"suspended_at": now, "suspension_reason": reason, "updated_at": now},
+	}
+	if !suspended {
+		update = bson.M{
+			"$set":   bson.M{"updated_at": now},
+			"$unset": bson.M{"suspended_at": "", "suspension_reason": ""},
+		}
+	}
+
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	return err
+}
@@ -0,0 +1,73 @@
+package companies
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// ErrSlugTaken indica que o endereço público já pertence a outra empresa
+var ErrSlugTaken = errors.New("slug já utilizado por outra empresa")
+
+const (
+	MinSlugLength = 3
+	MaxSlugLength = 60
+)
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+var slugAccentReplacer = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a",
+	"é", "e", "ê", "e", "è", "e",
+	"í", "i", "î", "i",
+	"ó", "o", "ô", "o", "õ", "o", "ö", "o",
+	"ú", "u", "ü", "u",
+	"ç", "c", "ñ", "n",
+	"&", " e ",
+)
+
+// Slugify gera o endereço público a partir do nome ("Café & Cia Ltda." -> "cafe-e-cia-ltda")
+func Slugify(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range slugAccentReplacer.Replace(strings.ToLower(name)) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+
+	slug := b.String()
+	if len(slug) > MaxSlugLength {
+		slug = slug[:MaxSlugLength]
+	}
+	slug = strings.Trim(slug, "-")
+
+	// Nomes curtos demais ou só com símbolos ganham um prefixo para continuar válidos
+	if len(slug) < MinSlugLength {
+		slug = strings.Trim("empresa-"+slug, "-")
+	}
+	// Um slug com cara de ID seria ambíguo em /companies/{id-ou-slug}
+	if looksLikeObjectID(slug) {
+		slug = "empresa-" + slug
+	}
+	return slug
+}
+
+// ValidSlug confere um slug escolhido pela empresa: minúsculas, números e hífens
+func ValidSlug(slug string) bool {
+	if len(slug) < MinSlugLength || len(slug) > MaxSlugLength {
+		return false
+	}
+	return slugPattern.MatchString(slug) && !looksLikeObjectID(slug)
+}
+
+func looksLikeObjectID(s string) bool {
+	_, err := bson.ObjectIDFromHex(s)
+	return err == nil
+}
@@ -11,8 +11,6 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
-var jwtSecret []byte
-
 const (
 	// Aumentar custo do bcrypt para maior segurança (padrão é 10, usaremos 12)
 	bcryptCost = 12
@@ -20,17 +18,23 @@ const (
 	minPasswordLength = 8
 )
 
-// Initialize configura o segredo JWT
+// Initialize configura o segredo JWT (assinatura HS256 legada e cifragem das chaves assimétricas)
 func Initialize(secret string) {
 	if len(secret) < 32 {
 		panic("JWT_SECRET deve ter no mínimo 32 caracteres")
 	}
-	jwtSecret = []byte(secret)
+	keys.mu.Lock()
+	keys.legacy = []byte(secret)
+	keys.mu.Unlock()
 }
 
 type Claims struct {
 	ID   string `json:"id"`
 	Type string `json:"type"` // "company" ou "candidate"
+	// Membros da equipe de uma empresa: ID é o da empresa, MemberID o do membro.
+	// Tokens de empresa sem member_id são do dono (papel "owner").
+	MemberID string `json:"member_id,omitempty"`
+	Role     string `json:"role,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -79,46 +83,54 @@ func boolToByte(b bool) byte {
 	return 0
 }
 
-// GenerateToken cria um token JWT com claims de segurança
+// GenerateToken cria um token JWT com claims de segurança.
+// Assina com a chave assimétrica ativa (header kid) ou, com JWT_ALG=HS256, com o segredo legado.
 func GenerateToken(id, userType string) (string, error) {
-	if len(jwtSecret) == 0 {
-		return "", errors.New("JWT secret não inicializado")
+	return signToken(Claims{ID: id, Type: userType})
+}
+
+// GenerateMemberToken cria o token de um membro da equipe de uma empresa
+func GenerateMemberToken(companyID, memberID, role string) (string, error) {
+	return signToken(Claims{ID: companyID, Type: "company", MemberID: memberID, Role: role})
+}
+
+func signToken(claims Claims) (string, error) {
+	now := time.Now()
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		ExpiresAt: jwt.NewNumericDate(now.Add(24 * time.Hour)),
+		IssuedAt:  jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(now),
+		Issuer:    "empregabem-api",
 	}
 
-	claims := Claims{
-		ID:   id,
-		Type: userType,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
-			Issuer:    "empregabem-api",
-		},
+	keys.mu.RLock()
+	defer keys.mu.RUnlock()
+
+	if keys.signingAlg == AlgHS256 {
+		if len(keys.legacy) == 0 {
+			return "", errors.New("JWT secret não inicializado")
+		}
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		return token.SignedString(keys.legacy)
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(jwtSecret)
+	key := keys.current(now)
+	if key == nil {
+		return "", errors.New("nenhuma chave de assinatura JWT ativa")
+	}
+	token := jwt.NewWithClaims(key.method, claims)
+	token.Header["kid"] = key.kid
+	return token.SignedString(key.private)
 }
 
 // ValidateToken valida e retorna os claims do token com verificações de segurança
 func ValidateToken(tokenString string) (*Claims, error) {
-	if len(jwtSecret) == 0 {
-		return nil, errors.New("JWT secret não inicializado")
-	}
-
 	// Validar tamanho do token para evitar ataques de DoS
 	if len(tokenString) > 1024 {
 		return nil, errors.New("token muito grande")
 	}
 
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		// Verificar algoritmo para evitar ataques de confusão de algoritmo
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("algoritmo de assinatura inválido")
-		}
-		return jwtSecret, nil
-	})
-
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, keys.verificationKey)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,388 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"empregabemapi/internal/models"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Algoritmos de assinatura suportados
+const (
+	AlgHS256 = "HS256" // legado: segredo compartilhado (JWT_SECRET)
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+)
+
+const rsaKeyBits = 2048
+
+// KeyStore persiste as chaves assimétricas para que todas as instâncias compartilhem o mesmo conjunto
+type KeyStore interface {
+	List(ctx context.Context) ([]*models.JWTKey, error)
+	Insert(ctx context.Context, key *models.JWTKey) error
+}
+
+// RotationConfig define o ciclo de vida das chaves
+type RotationConfig struct {
+	// Interval é por quanto tempo cada chave assina tokens
+	Interval time.Duration
+	// Prepublish é quanto tempo antes de ativar a próxima chave ela já aparece no JWKS
+	// (dá tempo para outros serviços atualizarem o cache)
+	Prepublish time.Duration
+	// Overlap é por quanto tempo uma chave continua validando tokens depois de parar de assinar.
+	// Deve ser maior que a validade dos tokens (24h).
+	Overlap time.Duration
+}
+
+// DefaultRotationConfig rotaciona a cada 30 dias, publica com 1 dia de antecedência
+// e mantém a chave antiga por 2 dias
+var DefaultRotationConfig = RotationConfig{
+	Interval:   30 * 24 * time.Hour,
+	Prepublish: 24 * time.Hour,
+	Overlap:    48 * time.Hour,
+}
+
+// signingKey é uma chave carregada em memória
+type signingKey struct {
+	kid         string
+	alg         string
+	method      jwt.SigningMethod
+	private     crypto.Signer
+	public      crypto.PublicKey
+	activatesAt time.Time
+	retiresAt   time.Time
+}
+
+// keyRing guarda as chaves em uso pelo processo
+type keyRing struct {
+	mu           sync.RWMutex
+	legacy       []byte // segredo HS256
+	signingAlg   string
+	acceptLegacy bool
+	keys         map[string]*signingKey
+}
+
+var keys = &keyRing{
+	signingAlg:   AlgHS256,
+	acceptLegacy: true,
+	keys:         map[string]*signingKey{},
+}
+
+// Configure define o algoritmo usado para assinar novos tokens e se tokens HS256
+// (sem kid) ainda são aceitos. Com AlgHS256 nada muda em relação ao comportamento antigo.
+func Configure(signingAlg string, acceptLegacy bool) error {
+	switch signingAlg {
+	case AlgHS256, AlgRS256, AlgEdDSA:
+	default:
+		return fmt.Errorf("algoritmo JWT não suportado: %s", signingAlg)
+	}
+	if signingAlg == AlgHS256 && !acceptLegacy {
+		return errors.New("HS256 não pode ser usado para assinar se tokens HS256 não forem aceitos")
+	}
+
+	keys.mu.Lock()
+	defer keys.mu.Unlock()
+	keys.signingAlg = signingAlg
+	keys.acceptLegacy = acceptLegacy
+	return nil
+}
+
+// setKeys substitui o conjunto de chaves assimétricas carregado
+func (k *keyRing) setKeys(loaded []*signingKey) {
+	m := make(map[string]*signingKey, len(loaded))
+	for _, key := range loaded {
+		m[key.kid] = key
+	}
+	k.mu.Lock()
+	k.keys = m
+	k.mu.Unlock()
+}
+
+// current retorna a chave que deve assinar agora (a ativada mais recentemente do algoritmo configurado)
+func (k *keyRing) current(now time.Time) *signingKey {
+	var best *signingKey
+	for _, key := range k.keys {
+		if key.alg != k.signingAlg || key.activatesAt.After(now) || !key.retiresAt.After(now) {
+			continue
+		}
+		if best == nil || key.activatesAt.After(best.activatesAt) {
+			best = key
+		}
+	}
+	return best
+}
+
+// verificationKey escolhe a chave pública (ou segredo legado) para validar um token
+func (k *keyRing) verificationKey(token *jwt.Token) (interface{}, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		// Tokens legados HS256 não têm kid
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok || !k.acceptLegacy || len(k.legacy) == 0 {
+			return nil, errors.New("algoritmo de assinatura inválido")
+		}
+		return k.legacy, nil
+	}
+
+	key, ok := k.keys[kid]
+	if !ok || !key.retiresAt.After(time.Now()) {
+		return nil, errors.New("chave de assinatura desconhecida")
+	}
+	// Verificar algoritmo para evitar ataques de confusão de algoritmo
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, errors.New("algoritmo de assinatura inválido")
+	}
+	return key.public, nil
+}
+
+// KeyRotator mantém as chaves do KeyStore sincronizadas e cria novas conforme a política de rotação
+type KeyRotator struct {
+	store  KeyStore
+	config RotationConfig
+	cipher cipher.AEAD
+}
+
+// NewKeyRotator cria o rotacionador. As chaves privadas são cifradas no banco com uma chave
+// derivada de JWT_SECRET, então Initialize deve ter sido chamado antes.
+func NewKeyRotator(store KeyStore, config RotationConfig) (*KeyRotator, error) {
+	keys.mu.RLock()
+	secret := keys.legacy
+	keys.mu.RUnlock()
+	if len(secret) == 0 {
+		return nil, errors.New("JWT secret não inicializado")
+	}
+
+	// Rotação curta: pré-publicar no máximo na metade do intervalo
+	if config.Prepublish > config.Interval/2 {
+		config.Prepublish = config.Interval / 2
+	}
+
+	sum := sha256.Sum256(append([]byte("empregabem-jwt-keys:"), secret...))
+	block, err := aes.NewCipher(sum[:])
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &KeyRotator{store: store, config: config, cipher: aead}, nil
+}
+
+// Sync carrega as chaves do banco, gera a próxima chave quando necessário e atualiza o conjunto em memória
+func (r *KeyRotator) Sync(ctx context.Context) error {
+	keys.mu.RLock()
+	alg := keys.signingAlg
+	keys.mu.RUnlock()
+
+	stored, err := r.store.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	loaded := r.decode(stored, now)
+
+	if alg != AlgHS256 {
+		next, err := r.nextKey(ctx, alg, loaded, now)
+		if err != nil {
+			return err
+		}
+		if next != nil {
+			loaded = append(loaded, next)
+		}
+	}
+
+	keys.setKeys(loaded)
+	return nil
+}
+
+// Run sincroniza periodicamente até o contexto ser cancelado
+func (r *KeyRotator) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			syncCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+			if err := r.Sync(syncCtx); err != nil {
+				log.Println("Erro ao sincronizar chaves JWT:", err)
+			}
+			cancel()
+		}
+	}
+}
+
+// decode converte as chaves do banco, ignorando as aposentadas ou ilegíveis
+func (r *KeyRotator) decode(stored []*models.JWTKey, now time.Time) []*signingKey {
+	var loaded []*signingKey
+	for _, s := range stored {
+		if !s.RetiresAt.After(now) {
+			continue
+		}
+		key, err := r.decodeKey(s)
+		if err != nil {
+			// Normalmente significa que JWT_SECRET mudou; uma nova chave será gerada
+			log.Printf("Aviso: chave JWT %s ignorada: %v", s.KID, err)
+			continue
+		}
+		loaded = append(loaded, key)
+	}
+	return loaded
+}
+
+// nextKey gera e persiste uma nova chave quando não há chave ativa ou quando a ativa está perto de expirar
+func (r *KeyRotator) nextKey(ctx context.Context, alg string, loaded []*signingKey, now time.Time) (*signingKey, error) {
+	var current, pending *signingKey
+	for _, key := range loaded {
+		if key.alg != alg {
+			continue
+		}
+		if key.activatesAt.After(now) {
+			pending = key
+		} else if current == nil || key.activatesAt.After(current.activatesAt) {
+			current = key
+		}
+	}
+
+	activatesAt := now
+	switch {
+	case current == nil:
+		// Sem chave ativa: ativar imediatamente
+	case pending != nil:
+		return nil, nil
+	case now.Before(current.activatesAt.Add(r.config.Interval - r.config.Prepublish)):
+		return nil, nil
+	default:
+		activatesAt = current.activatesAt.Add(r.config.Interval)
+	}
+
+	key, stored, err := r.generate(alg, now, activatesAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.store.Insert(ctx, stored); err != nil {
+		return nil, err
+	}
+	log.Printf("Nova chave JWT %s (%s) ativa a partir de %s", key.kid, alg, activatesAt.Format(time.RFC3339))
+	return key, nil
+}
+
+// generate cria um novo par de chaves e sua versão cifrada para o banco
+func (r *KeyRotator) generate(alg string, now, activatesAt time.Time) (*signingKey, *models.JWTKey, error) {
+	var private crypto.Signer
+	var err error
+	switch alg {
+	case AlgEdDSA:
+		_, private, err = ed25519.GenerateKey(rand.Reader)
+	case AlgRS256:
+		private, err = rsa.GenerateKey(rand.Reader, rsaKeyBits)
+	default:
+		err = fmt.Errorf("algoritmo JWT não suportado: %s", alg)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return nil, nil, err
+	}
+	nonce := make([]byte, r.cipher.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, nil, err
+	}
+	sealed := r.cipher.Seal(nonce, nonce, der, nil)
+
+	kid, err := GenerateSecureToken(8)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stored := &models.JWTKey{
+		KID:         kid,
+		Algorithm:   alg,
+		PrivateKey:  base64.StdEncoding.EncodeToString(sealed),
+		CreatedAt:   now,
+		ActivatesAt: activatesAt,
+		RetiresAt:   activatesAt.Add(r.config.Interval + r.config.Overlap),
+	}
+
+	key, err := newSigningKey(stored, private)
+	return key, stored, err
+}
+
+// decodeKey decifra a chave privada armazenada
+func (r *KeyRotator) decodeKey(s *models.JWTKey) (*signingKey, error) {
+	sealed, err := base64.StdEncoding.DecodeString(s.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < r.cipher.NonceSize() {
+		return nil, errors.New("chave cifrada inválida")
+	}
+	nonce, ciphertext := sealed[:r.cipher.NonceSize()], sealed[r.cipher.NonceSize():]
+	der, err := r.cipher.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, errors.New("não foi possível decifrar a chave")
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, err
+	}
+	private, ok := parsed.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("tipo de chave não suportado")
+	}
+	return newSigningKey(s, private)
+}
+
+func newSigningKey(s *models.JWTKey, private crypto.Signer) (*signingKey, error) {
+	key := &signingKey{
+		kid:         s.KID,
+		alg:         s.Algorithm,
+		private:     private,
+		public:      private.Public(),
+		activatesAt: s.ActivatesAt,
+		retiresAt:   s.RetiresAt,
+	}
+
+	switch private.(type) {
+	case ed25519.PrivateKey:
+		if s.Algorithm != AlgEdDSA {
+			return nil, errors.New("algoritmo não corresponde à chave")
+		}
+		key.method = jwt.SigningMethodEdDSA
+	case *rsa.PrivateKey:
+		if s.Algorithm != AlgRS256 {
+			return nil, errors.New("algoritmo não corresponde à chave")
+		}
+		key.method = jwt.SigningMethodRS256
+	default:
+		return nil, errors.New("tipo de chave não suportado")
+	}
+
+	return key, nil
+}
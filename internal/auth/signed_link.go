@@ -0,0 +1,64 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+)
+
+// Finalidades dos links assinados (a assinatura de uma não vale para outra)
+const (
+	LinkPurposeAlertUnsubscribe = "alert-unsubscribe"
+)
+
+var ErrInvalidSignedLink = errors.New("link inválido")
+
+// SignLink gera um token "{valor}.{assinatura}" para links enviados por email que não
+// expiram e não precisam de estado no banco (ex: descadastro de alertas). A assinatura é um
+// HMAC-SHA256 com chave derivada de JWT_SECRET, então Initialize deve ter sido chamado antes.
+func SignLink(purpose, value string) (string, error) {
+	mac, err := linkMAC(purpose, value)
+	if err != nil {
+		return "", err
+	}
+	return value + "." + base64.RawURLEncoding.EncodeToString(mac), nil
+}
+
+// VerifyLink confere um token gerado por SignLink e retorna o valor assinado
+func VerifyLink(purpose, token string) (string, error) {
+	value, signature, ok := strings.Cut(token, ".")
+	if !ok || value == "" {
+		return "", ErrInvalidSignedLink
+	}
+	got, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil {
+		return "", ErrInvalidSignedLink
+	}
+
+	expected, err := linkMAC(purpose, value)
+	if err != nil {
+		return "", err
+	}
+	if !hmac.Equal(got, expected) {
+		return "", ErrInvalidSignedLink
+	}
+	return value, nil
+}
+
+func linkMAC(purpose, value string) ([]byte, error) {
+	keys.mu.RLock()
+	secret := keys.legacy
+	keys.mu.RUnlock()
+	if len(secret) == 0 {
+		return nil, errors.New("JWT secret não inicializado")
+	}
+
+	key := sha256.Sum256(append([]byte("empregabem-links:"), secret...))
+	mac := hmac.New(sha256.New, key[:])
+	mac.Write([]byte(purpose))
+	mac.Write([]byte{0})
+	mac.Write([]byte(value))
+	return mac.Sum(nil), nil
+}
@@ -0,0 +1,93 @@
+package auth
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// useSecret troca o segredo global durante o teste e restaura o anterior no fim
+func useSecret(t *testing.T, secret string) {
+	t.Helper()
+	keys.mu.RLock()
+	previous := keys.legacy
+	keys.mu.RUnlock()
+	t.Cleanup(func() {
+		keys.mu.Lock()
+		keys.legacy = previous
+		keys.mu.Unlock()
+	})
+	keys.mu.Lock()
+	keys.legacy = []byte(secret)
+	keys.mu.Unlock()
+}
+
+func TestSignLinkRoundTrip(t *testing.T) {
+	useSecret(t, "segredo-de-teste")
+
+	for _, value := range []string{"65f1c2a9e4b0a1b2c3d4e5f6", "candidate:123", "ação"} {
+		token, err := SignLink(LinkPurposeAlertUnsubscribe, value)
+		if err != nil {
+			t.Fatalf("SignLink(%q): %v", value, err)
+		}
+		if strings.ContainsAny(token, "+/=") {
+			t.Errorf("token não é seguro para URL: %s", token)
+		}
+		got, err := VerifyLink(LinkPurposeAlertUnsubscribe, token)
+		if err != nil || got != value {
+			t.Errorf("VerifyLink(%q) = %q, %v; esperado %q", token, got, err, value)
+		}
+	}
+}
+
+func TestVerifyLinkRejects(t *testing.T) {
+	useSecret(t, "segredo-de-teste")
+
+	token, err := SignLink(LinkPurposeAlertUnsubscribe, "65f1c2a9e4b0a1b2c3d4e5f6")
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, signature, _ := strings.Cut(token, ".")
+	other, _ := SignLink(LinkPurposeAlertUnsubscribe, "65f1c2a9e4b0a1b2c3d4e5f7")
+	_, otherSignature, _ := strings.Cut(other, ".")
+
+	tests := []struct {
+		name    string
+		purpose string
+		token   string
+	}{
+		{"outra finalidade", "password-reset", token},
+		{"valor trocado", LinkPurposeAlertUnsubscribe, "65f1c2a9e4b0a1b2c3d4e5f7." + signature},
+		{"assinatura de outro valor", LinkPurposeAlertUnsubscribe, value + "." + otherSignature},
+		{"assinatura truncada", LinkPurposeAlertUnsubscribe, token[:len(token)-1]},
+		{"assinatura fora de base64", LinkPurposeAlertUnsubscribe, value + ".!!!"},
+		{"sem assinatura", LinkPurposeAlertUnsubscribe, value},
+		{"assinatura vazia", LinkPurposeAlertUnsubscribe, value + "."},
+		{"valor vazio", LinkPurposeAlertUnsubscribe, "." + signature},
+		{"token vazio", LinkPurposeAlertUnsubscribe, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := VerifyLink(tt.purpose, tt.token); !errors.Is(err, ErrInvalidSignedLink) {
+				t.Errorf("esperado ErrInvalidSignedLink, veio %v", err)
+			}
+		})
+	}
+
+	// Trocar JWT_SECRET invalida os links já enviados
+	useSecret(t, "outro-segredo")
+	if _, err := VerifyLink(LinkPurposeAlertUnsubscribe, token); !errors.Is(err, ErrInvalidSignedLink) {
+		t.Errorf("link aceito com outro segredo: %v", err)
+	}
+}
+
+func TestSignLinkWithoutSecret(t *testing.T) {
+	useSecret(t, "")
+
+	if _, err := SignLink(LinkPurposeAlertUnsubscribe, "123"); err == nil {
+		t.Error("SignLink sem segredo deveria falhar")
+	}
+	if _, err := VerifyLink(LinkPurposeAlertUnsubscribe, "123.YWJj"); err == nil || errors.Is(err, ErrInvalidSignedLink) {
+		t.Errorf("VerifyLink sem segredo deveria falhar com erro de configuração, veio %v", err)
+	}
+}
@@ -0,0 +1,153 @@
+// Package calendar gera convites iCalendar (RFC 5545) para anexar nos emails e baixar pela API.
+package calendar
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Métodos iTIP (RFC 5546) usados nos convites
+const (
+	MethodRequest = "REQUEST" // convite novo ou atualizado (mesmo UID, SEQUENCE maior)
+	MethodCancel  = "CANCEL"  // cancelamento do evento
+)
+
+// Filename é o nome do arquivo do convite nos anexos e downloads
+const Filename = "convite.ics"
+
+// ContentType retorna o Content-Type do convite; clientes de email usam o method para
+// mostrar os botões de aceitar/recusar ou remover o evento da agenda
+func ContentType(method string) string {
+	return "text/calendar; charset=utf-8; method=" + method
+}
+
+// Person é o organizador ou um participante do evento
+type Person struct {
+	Name  string
+	Email string
+}
+
+// Event é o evento do convite. UID identifica o evento em todas as versões do convite;
+// Sequence deve aumentar a cada alteração para os calendários substituírem a versão anterior.
+type Event struct {
+	UID         string
+	Sequence    int
+	Start       time.Time
+	End         time.Time
+	Summary     string
+	Description string
+	Location    string
+	URL         string
+	Organizer   Person
+	Attendees   []Person
+	Stamp       time.Time // DTSTAMP (zero = agora)
+}
+
+// Invite gera o arquivo .ics do evento para o método informado
+func Invite(method string, event Event) []byte {
+	stamp := event.Stamp
+	if stamp.IsZero() {
+		stamp = time.Now()
+	}
+	status := "CONFIRMED"
+	if method == MethodCancel {
+		status = "CANCELLED"
+	}
+
+	var b bytes.Buffer
+	line := func(s string) {
+		b.WriteString(fold(s))
+		b.WriteString("\r\n")
+	}
+
+	line("BEGIN:VCALENDAR")
+	line("PRODID:-//EmpregaBem//Entrevistas//PT-BR")
+	line("VERSION:2.0")
+	line("CALSCALE:GREGORIAN")
+	line("METHOD:" + method)
+	line("BEGIN:VEVENT")
+	line("UID:" + event.UID)
+	line("SEQUENCE:" + strconv.Itoa(event.Sequence))
+	line("DTSTAMP:" + formatTime(stamp))
+	line("DTSTART:" + formatTime(event.Start))
+	line("DTEND:" + formatTime(event.End))
+	line("SUMMARY:" + escapeText(event.Summary))
+	if event.Description != "" {
+		line("DESCRIPTION:" + escapeText(event.Description))
+	}
+	if event.Location != "" {
+		line("LOCATION:" + escapeText(event.Location))
+	}
+	if event.URL != "" {
+		line("URL:" + event.URL)
+	}
+	if event.Organizer.Email != "" {
+		line("ORGANIZER" + commonName(event.Organizer.Name) + ":mailto:" + event.Organizer.Email)
+	}
+	for _, attendee := range event.Attendees {
+		line("ATTENDEE" + commonName(attendee.Name) + ";ROLE=REQ-PARTICIPANT;PARTSTAT=NEEDS-ACTION:mailto:" + attendee.Email)
+	}
+	line("STATUS:" + status)
+	line("TRANSP:OPAQUE")
+	if method == MethodRequest {
+		line("BEGIN:VALARM")
+		line("ACTION:DISPLAY")
+		line("DESCRIPTION:" + escapeText(event.Summary))
+		line("TRIGGER:-PT30M")
+		line("END:VALARM")
+	}
+	line("END:VEVENT")
+	line("END:VCALENDAR")
+
+	return b.Bytes()
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// escapeText escapa valores TEXT (RFC 5545 §3.3.11)
+func escapeText(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`, "\r", "").Replace(s)
+}
+
+// commonName monta o parâmetro CN entre aspas (aspas não são permitidas dentro do valor)
+func commonName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if r == '"' || r < 0x20 || r == 0x7f {
+			return -1
+		}
+		return r
+	}, name)
+	if name == "" {
+		return ""
+	}
+	return `;CN="` + name + `"`
+}
+
+// fold quebra linhas com mais de 75 octetos; as continuações começam com um espaço (RFC 5545 §3.1).
+// A quebra nunca divide um caractere UTF-8.
+func fold(s string) string {
+	if len(s) <= 75 {
+		return s
+	}
+
+	var b strings.Builder
+	limit := 75
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		b.WriteString(s[:cut])
+		b.WriteString("\r\n ")
+		s = s[cut:]
+		limit = 74 // o espaço inicial conta no tamanho da linha
+	}
+	b.WriteString(s)
+	return b.String()
+}
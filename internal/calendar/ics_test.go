@@ -0,0 +1,140 @@
+package calendar
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestEscapeText(t *testing.T) {
+	tests := []struct {
+		input, want string
+	}{
+		{"Entrevista técnica", "Entrevista técnica"},
+		{"Go, Docker; Kubernetes", `Go\, Docker\; Kubernetes`},
+		{`C:\vagas`, `C:\\vagas`},
+		{"linha 1\nlinha 2", `linha 1\nlinha 2`},
+		{"linha 1\r\nlinha 2", `linha 1\nlinha 2`},
+		{"sem\rCR", "semCR"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := escapeText(tt.input); got != tt.want {
+			t.Errorf("escapeText(%q) = %q, esperado %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestFold(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"curta", "SUMMARY:Entrevista"},
+		{"exatamente 75 octetos", "DESCRIPTION:" + strings.Repeat("a", 63)},
+		{"76 octetos", "DESCRIPTION:" + strings.Repeat("a", 64)},
+		{"longa ASCII", "DESCRIPTION:" + strings.Repeat("abcdefghij", 30)},
+		{"longa com acentos", "DESCRIPTION:" + strings.Repeat("ação ", 60)},
+		{"emojis de 4 octetos", "SUMMARY:" + strings.Repeat("🗓", 50)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			folded := fold(tt.input)
+
+			for i, line := range strings.Split(folded, "\r\n") {
+				if len(line) > 75 {
+					t.Errorf("linha %d com %d octetos", i, len(line))
+				}
+				if i > 0 && !strings.HasPrefix(line, " ") {
+					t.Errorf("continuação %d sem espaço inicial: %q", i, line)
+				}
+				if !utf8.ValidString(line) {
+					t.Errorf("linha %d dividiu um caractere UTF-8: %q", i, line)
+				}
+			}
+
+			if unfolded := strings.ReplaceAll(folded, "\r\n ", ""); unfolded != tt.input {
+				t.Errorf("desdobrar não restaura o original:\n%q\n%q", unfolded, tt.input)
+			}
+			if len(tt.input) <= 75 && folded != tt.input {
+				t.Errorf("linha curta foi alterada: %q", folded)
+			}
+		})
+	}
+}
+
+func TestCommonName(t *testing.T) {
+	tests := map[string]string{
+		"Maria Silva":           `;CN="Maria Silva"`,
+		`Maria "Mari" Silva`:    `;CN="Maria Mari Silva"`,
+		"Maria\r\nATTENDEE:x":   `;CN="MariaATTENDEE:x"`,
+		"":                      "",
+		"\"\"":                  "",
+		"Tech Solutions; Ltda.": `;CN="Tech Solutions; Ltda."`,
+	}
+	for input, want := range tests {
+		if got := commonName(input); got != want {
+			t.Errorf("commonName(%q) = %q, esperado %q", input, got, want)
+		}
+	}
+}
+
+func TestInvite(t *testing.T) {
+	start := time.Date(2026, 3, 10, 14, 0, 0, 0, time.FixedZone("BRT", -3*60*60))
+	event := Event{
+		UID:         "interview-123@empregabem.com.br",
+		Sequence:    2,
+		Start:       start,
+		End:         start.Add(time.Hour),
+		Summary:     "Entrevista: Desenvolvedor Go, Tech Solutions",
+		Description: "Conversa técnica com o time.\nTraga dúvidas; vamos falar de Go, Docker e Kubernetes. " + strings.Repeat("Detalhes da vaga. ", 5),
+		Location:    "Av. Paulista, 1000",
+		Organizer:   Person{Name: "Tech Solutions", Email: "rh@techsolutions.com"},
+		Attendees:   []Person{{Name: "João Silva", Email: "joao@example.com"}},
+		Stamp:       time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC),
+	}
+
+	t.Run("REQUEST", func(t *testing.T) {
+		ics := string(Invite(MethodRequest, event))
+		if !strings.HasSuffix(ics, "END:VCALENDAR\r\n") || strings.Contains(strings.ReplaceAll(ics, "\r\n", ""), "\n") {
+			t.Fatal("linhas devem terminar em CRLF")
+		}
+
+		unfolded := strings.ReplaceAll(ics, "\r\n ", "")
+		for _, want := range []string{
+			"METHOD:REQUEST",
+			"UID:interview-123@empregabem.com.br",
+			"SEQUENCE:2",
+			"DTSTAMP:20260301T090000Z",
+			"DTSTART:20260310T170000Z", // convertido para UTC
+			"DTEND:20260310T180000Z",
+			`SUMMARY:Entrevista: Desenvolvedor Go\, Tech Solutions`,
+			`DESCRIPTION:Conversa técnica com o time.\nTraga dúvidas\; vamos falar de Go\, Docker e Kubernetes.`,
+			`LOCATION:Av. Paulista\, 1000`,
+			`ORGANIZER;CN="Tech Solutions":mailto:rh@techsolutions.com`,
+			`ATTENDEE;CN="João Silva";ROLE=REQ-PARTICIPANT;PARTSTAT=NEEDS-ACTION:mailto:joao@example.com`,
+			"STATUS:CONFIRMED",
+			"BEGIN:VALARM",
+		} {
+			if !strings.Contains(unfolded, want) {
+				t.Errorf("convite sem %q", want)
+			}
+		}
+		for _, line := range strings.Split(ics, "\r\n") {
+			if len(line) > 75 {
+				t.Errorf("linha com %d octetos: %q", len(line), line)
+			}
+		}
+	})
+
+	t.Run("CANCEL", func(t *testing.T) {
+		ics := string(Invite(MethodCancel, event))
+		if !strings.Contains(ics, "METHOD:CANCEL\r\n") || !strings.Contains(ics, "STATUS:CANCELLED\r\n") {
+			t.Error("cancelamento sem METHOD/STATUS")
+		}
+		if strings.Contains(ics, "VALARM") {
+			t.Error("cancelamento não deve ter alarme")
+		}
+	})
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"log"
 	"os"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -13,6 +14,48 @@ type Config struct {
 	Environment string
 	JWTSecret   string
 	CORSOrigins string
+
+	// Assinatura de tokens: "HS256" (legado, só JWT_SECRET), "EdDSA" ou "RS256" (chaves rotacionadas no MongoDB)
+	JWTAlgorithm string
+	// Aceitar tokens HS256 antigos durante a migração para chaves assimétricas
+	JWTAcceptHS256 bool
+	// Por quanto tempo cada chave assimétrica assina tokens antes de ser rotacionada
+	JWTKeyRotation time.Duration
+
+	// Rate limiting: "memory" (uma instância) ou "mongo" (compartilhado entre instâncias)
+	RateLimitStore string
+	// Eventos em tempo real (SSE): "memory" (uma instância) ou "mongo" (compartilhado entre instâncias)
+	EventsBroker string
+	// IPs/CIDRs de proxies confiáveis, separados por vírgula (X-Forwarded-For só é lido deles)
+	TrustedProxies string
+
+	// URL pública do frontend (usada nos links enviados por email)
+	AppURL string
+
+	// Email transacional
+	MailDriver   string // "smtp" ou "log"
+	MailFrom     string
+	MailLogDir   string // diretório onde o driver "log" grava os .eml (vazio = só log)
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+
+	// Consulta de CNPJ no cadastro de empresas: "none", "fake" (dados de exemplo) ou "receitaws"
+	CNPJLookup      string
+	CNPJLookupURL   string
+	CNPJLookupToken string
+
+	// Uploads de imagens (logo e capa das empresas): diretório do storage e URL pública de /media
+	StorageDir string
+	MediaURL   string
+	// Anexos das mensagens: diretório separado, nunca servido por /media (acesso só pelas rotas de mensagens)
+	AttachmentsDir string
+
+	// Primeiro administrador do back-office (criado na inicialização se ainda não existir)
+	AdminEmail    string
+	AdminPassword string
+	AdminName     string
 }
 
 func Load() *Config {
@@ -27,6 +70,37 @@ func Load() *Config {
 		Environment: getEnv("ENVIRONMENT", "development"),
 		JWTSecret:   getEnv("JWT_SECRET", ""),
 		CORSOrigins: getEnv("CORS_ORIGINS", "http://localhost:3000,http://localhost:5173"),
+
+		JWTAlgorithm:   getEnv("JWT_ALG", "HS256"),
+		JWTAcceptHS256: getEnv("JWT_ACCEPT_HS256", "true") == "true",
+		JWTKeyRotation: getEnvDuration("JWT_KEY_ROTATION", 30*24*time.Hour),
+
+		RateLimitStore: getEnv("RATE_LIMIT_STORE", "memory"),
+		EventsBroker:   getEnv("EVENTS_BROKER", "memory"),
+		TrustedProxies: getEnv("TRUSTED_PROXIES", ""),
+
+		AppURL: getEnv("APP_URL", "http://localhost:5173"),
+
+		MailDriver:   getEnv("MAIL_DRIVER", "log"),
+		MailFrom:     getEnv("MAIL_FROM", "EmpregaBem <nao-responda@empregabem.com.br>"),
+		MailLogDir:   getEnv("MAIL_LOG_DIR", ""),
+		SMTPHost:     getEnv("SMTP_HOST", ""),
+		SMTPPort:     getEnv("SMTP_PORT", "587"),
+		SMTPUsername: getEnv("SMTP_USERNAME", ""),
+		SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+
+		CNPJLookup:      getEnv("CNPJ_LOOKUP", "none"),
+		CNPJLookupURL:   getEnv("CNPJ_LOOKUP_URL", "https://receitaws.com.br/v1"),
+		CNPJLookupToken: getEnv("CNPJ_LOOKUP_TOKEN", ""),
+
+		StorageDir: getEnv("STORAGE_DIR", "./data/uploads"),
+		MediaURL:   getEnv("MEDIA_URL", "http://localhost:8080/media"),
+
+		AttachmentsDir: getEnv("ATTACHMENTS_DIR", "./data/attachments"),
+
+		AdminEmail:    getEnv("ADMIN_EMAIL", ""),
+		AdminPassword: getEnv("ADMIN_PASSWORD", ""),
+		AdminName:     getEnv("ADMIN_NAME", "Administrador"),
 	}
 }
 
@@ -36,3 +110,13 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if d, err := time.ParseDuration(value); err == nil && d > 0 {
+			return d
+		}
+		log.Printf("Aviso: %s inválido (%q), usando %s", key, value, defaultValue)
+	}
+	return defaultValue
+}
@@ -0,0 +1,29 @@
+package events
+
+import (
+	"context"
+	"time"
+)
+
+// Message é um evento destinado a um usuário (Key), entregue pelos brokers a todas as instâncias
+type Message struct {
+	ID   string    // identificador único, enviado ao cliente como id do SSE (Last-Event-ID)
+	Key  string    // destinatário, ver Key()
+	Type string    // nome do evento no SSE
+	Data []byte    // payload JSON
+	At   time.Time // momento da publicação
+}
+
+// Broker distribui as mensagens publicadas para todas as instâncias da API.
+// Subscribe entrega cada mensagem (inclusive as publicadas pela própria instância) ao handler
+// até o contexto ser cancelado; um erro faz o Hub assinar de novo.
+type Broker interface {
+	Publish(ctx context.Context, msg Message) error
+	Subscribe(ctx context.Context, handler func(Message)) error
+}
+
+// Key identifica o destinatário dos eventos, no mesmo formato das notificações
+// (tipo de destinatário + ID: candidato, dono da empresa ou membro da equipe)
+func Key(recipientType, recipientID string) string {
+	return recipientType + ":" + recipientID
+}
@@ -0,0 +1,190 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const (
+	// Eventos guardados por usuário para retomar a conexão com Last-Event-ID
+	replaySize   = 50
+	replayMaxAge = 5 * time.Minute
+	// Eventos pendentes por conexão; um cliente lento que enche o buffer é desconectado
+	// e retoma do último evento recebido
+	subscriberBuffer = 32
+	// Espera antes de assinar o broker de novo após um erro
+	brokerRetryDelay = 5 * time.Second
+)
+
+// Hub recebe os eventos do broker e os distribui para as conexões abertas de cada usuário
+// nesta instância, mantendo um buffer curto para retomada
+type Hub struct {
+	broker Broker
+
+	mu          sync.Mutex
+	subscribers map[string]map[*Subscription]struct{}
+	history     map[string][]Message
+}
+
+func NewHub(broker Broker) *Hub {
+	return &Hub{
+		broker:      broker,
+		subscribers: make(map[string]map[*Subscription]struct{}),
+		history:     make(map[string][]Message),
+	}
+}
+
+// Subscription é uma conexão aberta de um usuário. C é fechado quando a conexão é
+// encerrada pelo Hub (cliente lento).
+type Subscription struct {
+	C      <-chan Message
+	ch     chan Message
+	key    string
+	hub    *Hub
+	closed bool
+}
+
+// Publish envia um evento ao usuário em todas as instâncias. Falhas são registradas em log:
+// eventos em tempo real são best-effort.
+func (h *Hub) Publish(ctx context.Context, key, eventType string, payload interface{}) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		log.Printf("[events] erro ao serializar evento %s: %v", eventType, err)
+		return
+	}
+
+	msg := Message{ID: bson.NewObjectID().Hex(), Key: key, Type: eventType, Data: data, At: time.Now()}
+	if err := h.broker.Publish(ctx, msg); err != nil {
+		log.Printf("[events] erro ao publicar evento %s para %s: %v", eventType, key, err)
+	}
+}
+
+// Run consome o broker até o contexto ser cancelado
+func (h *Hub) Run(ctx context.Context) {
+	go h.pruneLoop(ctx)
+
+	for {
+		err := h.broker.Subscribe(ctx, h.dispatch)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("[events] erro ao receber eventos do broker: %v", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(brokerRetryDelay):
+		}
+	}
+}
+
+// dispatch guarda o evento no histórico do usuário e o entrega às conexões abertas
+func (h *Hub) dispatch(msg Message) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	history := h.history[msg.Key]
+	for _, previous := range history {
+		if previous.ID == msg.ID {
+			return // entregue de novo pelo broker
+		}
+	}
+	history = append(history, msg)
+	if len(history) > replaySize {
+		history = history[len(history)-replaySize:]
+	}
+	h.history[msg.Key] = history
+
+	for sub := range h.subscribers[msg.Key] {
+		select {
+		case sub.ch <- msg:
+		default:
+			h.removeLocked(sub)
+		}
+	}
+}
+
+// Subscribe abre uma conexão do usuário. Com lastEventID, retorna os eventos posteriores a ele;
+// resync indica que o evento não está mais no histórico e o cliente deve recarregar os dados.
+func (h *Hub) Subscribe(key, lastEventID string) (sub *Subscription, replay []Message, resync bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if lastEventID != "" {
+		resync = true
+		history := h.history[key]
+		for i, msg := range history {
+			if msg.ID == lastEventID {
+				replay = append(replay, history[i+1:]...)
+				resync = false
+				break
+			}
+		}
+	}
+
+	ch := make(chan Message, subscriberBuffer)
+	sub = &Subscription{C: ch, ch: ch, key: key, hub: h}
+	if h.subscribers[key] == nil {
+		h.subscribers[key] = make(map[*Subscription]struct{})
+	}
+	h.subscribers[key][sub] = struct{}{}
+
+	return sub, replay, resync
+}
+
+// Close encerra a conexão (pode ser chamado mais de uma vez)
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	s.hub.removeLocked(s)
+}
+
+func (h *Hub) removeLocked(sub *Subscription) {
+	if sub.closed {
+		return
+	}
+	sub.closed = true
+	close(sub.ch)
+
+	delete(h.subscribers[sub.key], sub)
+	if len(h.subscribers[sub.key]) == 0 {
+		delete(h.subscribers, sub.key)
+	}
+}
+
+// pruneLoop descarta periodicamente os eventos velhos demais para retomada
+func (h *Hub) pruneLoop(ctx context.Context) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			h.prune(time.Now().Add(-replayMaxAge))
+		}
+	}
+}
+
+func (h *Hub) prune(before time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for key, history := range h.history {
+		i := 0
+		for i < len(history) && history[i].At.Before(before) {
+			i++
+		}
+		if i == len(history) {
+			delete(h.history, key)
+		} else if i > 0 {
+			h.history[key] = history[i:]
+		}
+	}
+}
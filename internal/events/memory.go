@@ -0,0 +1,40 @@
+package events
+
+import (
+	"context"
+	"log"
+)
+
+// MemoryBroker entrega as mensagens apenas dentro do processo (uma instância da API)
+type MemoryBroker struct {
+	messages chan Message
+}
+
+func NewMemoryBroker(buffer int) *MemoryBroker {
+	return &MemoryBroker{
+		messages: make(chan Message, buffer),
+	}
+}
+
+// Publish nunca bloqueia quem publica: com o buffer cheio a mensagem é descartada
+// (eventos são avisos; o estado continua disponível nas rotas da API)
+func (b *MemoryBroker) Publish(ctx context.Context, msg Message) error {
+	select {
+	case b.messages <- msg:
+	default:
+		log.Printf("[events] buffer cheio, evento %s para %s descartado", msg.Type, msg.Key)
+	}
+	return nil
+}
+
+// Subscribe aceita um único assinante (o Hub)
+func (b *MemoryBroker) Subscribe(ctx context.Context, handler func(Message)) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case msg := <-b.messages:
+			handler(msg)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const (
+	// Tamanho da capped collection: os eventos mais antigos são sobrescritos
+	mongoEventsSize = 16 << 20
+	// Ao reabrir o cursor, volta um pouco no tempo para tolerar relógios levemente diferentes
+	// entre instâncias (repetições são descartadas pelo Hub)
+	mongoResumeMargin = 10 * time.Second
+	// Espera antes de reabrir um cursor encerrado (ex: collection ainda vazia)
+	mongoRetryDelay = time.Second
+)
+
+// MongoBroker compartilha os eventos entre instâncias usando uma capped collection
+// lida com cursor tailable (funciona em MongoDB standalone, sem replica set)
+type MongoBroker struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+func NewMongoBroker(db *mongo.Database) *MongoBroker {
+	return &MongoBroker{
+		db:         db,
+		collection: db.Collection("events"),
+	}
+}
+
+// EnsureCollection cria a capped collection "events" se ela ainda não existir
+func (b *MongoBroker) EnsureCollection(ctx context.Context) error {
+	err := b.db.CreateCollection(ctx, "events", options.CreateCollection().SetCapped(true).SetSizeInBytes(mongoEventsSize))
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) && cmdErr.Code == 48 { // NamespaceExists
+		return nil
+	}
+	return err
+}
+
+type mongoEvent struct {
+	ID   bson.ObjectID `bson:"_id"`
+	Key  string        `bson:"key"`
+	Type string        `bson:"type"`
+	Data []byte        `bson:"data"`
+	At   time.Time     `bson:"at"`
+}
+
+func (b *MongoBroker) Publish(ctx context.Context, msg Message) error {
+	id, err := bson.ObjectIDFromHex(msg.ID)
+	if err != nil {
+		return err
+	}
+	_, err = b.collection.InsertOne(ctx, mongoEvent{ID: id, Key: msg.Key, Type: msg.Type, Data: msg.Data, At: msg.At})
+	return err
+}
+
+// Subscribe acompanha os eventos inseridos a partir de agora até o contexto ser cancelado
+func (b *MongoBroker) Subscribe(ctx context.Context, handler func(Message)) error {
+	from := bson.NewObjectIDFromTimestamp(time.Now())
+	opts := options.Find().SetCursorType(options.TailableAwait).SetMaxAwaitTime(5 * time.Second)
+
+	for ctx.Err() == nil {
+		cursor, err := b.collection.Find(ctx, bson.M{"_id": bson.M{"$gt": from}}, opts)
+		if err != nil {
+			return err
+		}
+
+		for cursor.Next(ctx) {
+			var event mongoEvent
+			if err := cursor.Decode(&event); err != nil {
+				continue
+			}
+			handler(Message{ID: event.ID.Hex(), Key: event.Key, Type: event.Type, Data: event.Data, At: event.At})
+			from = bson.NewObjectIDFromTimestamp(event.ID.Timestamp().Add(-mongoResumeMargin))
+		}
+		err = cursor.Err()
+		cursor.Close(context.Background())
+		if err != nil && ctx.Err() == nil {
+			return err
+		}
+
+		// Cursor encerrado sem erro (collection vazia ou eventos sobrescritos): abre outro
+		select {
+		case <-ctx.Done():
+		case <-time.After(mongoRetryDelay):
+		}
+	}
+	return ctx.Err()
+}
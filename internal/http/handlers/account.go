@@ -0,0 +1,563 @@
+package handlers
+
+import (
+	"context"
+	"empregabemapi/candidates"
+	"empregabemapi/companies"
+	"empregabemapi/internal/auth"
+	"empregabemapi/internal/mail"
+	"empregabemapi/internal/middleware"
+	"empregabemapi/internal/models"
+	"empregabemapi/internal/repository"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	netmail "net/mail"
+	"net/url"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// Validade do link de confirmação de troca de email
+const emailChangeTTL = time.Hour
+
+// AccountHandler reúne as operações de conta comuns a empresas, membros de equipe e candidatos
+type AccountHandler struct {
+	companyRepo     *companies.MongoRepository
+	memberRepo      *companies.MemberRepository
+	candidateRepo   *candidates.MongoRepository
+	emailChangeRepo *repository.EmailChangeRepository
+	mailer          *mail.Mailer
+}
+
+func NewAccountHandler(
+	companyRepo *companies.MongoRepository,
+	memberRepo *companies.MemberRepository,
+	candidateRepo *candidates.MongoRepository,
+	emailChangeRepo *repository.EmailChangeRepository,
+	mailer *mail.Mailer,
+) *AccountHandler {
+	return &AccountHandler{
+		companyRepo:     companyRepo,
+		memberRepo:      memberRepo,
+		candidateRepo:   candidateRepo,
+		emailChangeRepo: emailChangeRepo,
+		mailer:          mailer,
+	}
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+type ChangeEmailRequest struct {
+	NewEmail        string `json:"new_email"`
+	CurrentPassword string `json:"current_password"`
+}
+
+type ConfirmEmailChangeRequest struct {
+	Token string `json:"token"`
+}
+
+// Tipo de conta usado internamente para membros de equipe (o token continua com type "company")
+const accountTypeCompanyMember = "company_member"
+
+// account encapsula a conta autenticada (empresa, membro de equipe ou candidato)
+type account struct {
+	company   *companies.Company
+	member    *companies.Member
+	candidate *candidates.Candidate
+}
+
+func (a *account) name() string {
+	if a.company != nil {
+		return a.company.Name
+	}
+	if a.member != nil {
+		return a.member.Name
+	}
+	return a.candidate.Name
+}
+
+func (a *account) email() string {
+	if a.company != nil {
+		return a.company.Email
+	}
+	if a.member != nil {
+		return a.member.Email
+	}
+	return a.candidate.Email
+}
+
+func (a *account) passwordHash() string {
+	if a.company != nil {
+		return a.company.Password
+	}
+	if a.member != nil {
+		return a.member.Password
+	}
+	return a.candidate.Password
+}
+
+func (a *account) setPassword(hash string, revokeBefore time.Time) {
+	if a.company != nil {
+		a.company.Password = hash
+		a.company.TokensValidAfter = &revokeBefore
+		return
+	}
+	if a.member != nil {
+		a.member.Password = hash
+		a.member.TokensValidAfter = &revokeBefore
+		return
+	}
+	a.candidate.Password = hash
+	a.candidate.TokensValidAfter = &revokeBefore
+}
+
+func (a *account) setEmail(email string) {
+	if a.company != nil {
+		a.company.Email = email
+		return
+	}
+	if a.member != nil {
+		a.member.Email = email
+		return
+	}
+	a.candidate.Email = email
+}
+
+// token gera um novo token de sessão para a conta
+func (a *account) token() (string, error) {
+	if a.company != nil {
+		return auth.GenerateToken(a.company.ID.Hex(), "company")
+	}
+	if a.member != nil {
+		return auth.GenerateMemberToken(a.member.CompanyID.Hex(), a.member.ID.Hex(), string(a.member.Role))
+	}
+	return auth.GenerateToken(a.candidate.ID.Hex(), "candidate")
+}
+
+// currentAccount identifica a conta autenticada; membros de equipe são identificados pelo member_id
+func currentAccount(r *http.Request) (string, string) {
+	userType := r.Context().Value(middleware.UserTypeKey).(string)
+	userID := r.Context().Value(middleware.UserIDKey).(string)
+	if memberID := middleware.CompanyMemberID(r); userType == "company" && memberID != "" {
+		return accountTypeCompanyMember, memberID
+	}
+	return userType, userID
+}
+
+func (h *AccountHandler) loadAccount(ctx context.Context, userType, id string) (*account, error) {
+	switch userType {
+	case accountTypeCompanyMember:
+		member, err := h.memberRepo.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		return &account{member: member}, nil
+	case "company":
+		company, err := h.companyRepo.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		return &account{company: company}, nil
+	case "candidate":
+		candidate, err := h.candidateRepo.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		return &account{candidate: candidate}, nil
+	}
+	return nil, errors.New("tipo de usuário inválido")
+}
+
+func (h *AccountHandler) saveAccount(ctx context.Context, a *account) error {
+	if a.company != nil {
+		return h.companyRepo.Update(ctx, a.company)
+	}
+	if a.member != nil {
+		return h.memberRepo.Update(ctx, a.member)
+	}
+	return h.candidateRepo.Update(ctx, a.candidate)
+}
+
+// emailInUse verifica se o email já pertence a alguma empresa, membro de equipe ou candidato (verificação cruzada)
+func (h *AccountHandler) emailInUse(ctx context.Context, email string) (bool, error) {
+	if _, err := h.companyRepo.GetByEmail(ctx, email); err == nil {
+		return true, nil
+	} else if err != mongo.ErrNoDocuments {
+		return false, err
+	}
+
+	if _, err := h.memberRepo.GetByEmail(ctx, email); err == nil {
+		return true, nil
+	} else if err != mongo.ErrNoDocuments {
+		return false, err
+	}
+
+	if _, err := h.candidateRepo.GetByEmail(ctx, email); err == nil {
+		return true, nil
+	} else if err != mongo.ErrNoDocuments {
+		return false, err
+	}
+
+	return false, nil
+}
+
+// ChangePassword altera a senha do usuário autenticado e encerra as demais sessões
+func (h *AccountHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	userType, userID := currentAccount(r)
+
+	var req ChangePasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Dados inválidos",
+		})
+		return
+	}
+
+	if req.CurrentPassword == "" || req.NewPassword == "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Campos obrigatórios: current_password, new_password",
+		})
+		return
+	}
+
+	// Validar força da nova senha
+	if err := auth.ValidatePasswordStrength(req.NewPassword); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": err.Error(),
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	acc, err := h.loadAccount(ctx, userType, userID)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Usuário não encontrado",
+		})
+		return
+	}
+
+	if !auth.CheckPasswordHash(req.CurrentPassword, acc.passwordHash()) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Senha atual incorreta",
+		})
+		return
+	}
+
+	if auth.CheckPasswordHash(req.NewPassword, acc.passwordHash()) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "A nova senha deve ser diferente da atual",
+		})
+		return
+	}
+
+	hashedPassword, err := auth.HashPassword(req.NewPassword)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao processar senha",
+		})
+		return
+	}
+
+	// Tokens emitidos antes deste momento deixam de valer (revoga as outras sessões)
+	acc.setPassword(hashedPassword, time.Now())
+	if err := h.saveAccount(ctx, acc); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao atualizar senha",
+		})
+		return
+	}
+
+	// Novo token para manter a sessão atual
+	token, err := acc.token()
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao gerar token",
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{
+		"mensagem": "Senha alterada com sucesso. As outras sessões foram encerradas.",
+		"token":    token,
+	})
+}
+
+// ChangeEmail inicia a troca de email: envia confirmação para o novo endereço e aviso para o atual
+func (h *AccountHandler) ChangeEmail(w http.ResponseWriter, r *http.Request) {
+	userType, userID := currentAccount(r)
+
+	var req ChangeEmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Dados inválidos",
+		})
+		return
+	}
+
+	if req.NewEmail == "" || req.CurrentPassword == "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Campos obrigatórios: new_email, current_password",
+		})
+		return
+	}
+
+	req.NewEmail = auth.SanitizeEmail(req.NewEmail)
+	if addr, err := netmail.ParseAddress(req.NewEmail); err != nil || addr.Address != req.NewEmail {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Email inválido",
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	acc, err := h.loadAccount(ctx, userType, userID)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Usuário não encontrado",
+		})
+		return
+	}
+
+	if !auth.CheckPasswordHash(req.CurrentPassword, acc.passwordHash()) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Senha atual incorreta",
+		})
+		return
+	}
+
+	if req.NewEmail == acc.email() {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "O novo email é igual ao atual",
+		})
+		return
+	}
+
+	// Verifica se email já existe em empresas ou candidatos (verificação cruzada)
+	inUse, err := h.emailInUse(ctx, req.NewEmail)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao verificar email",
+		})
+		return
+	}
+	if inUse {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Email já cadastrado",
+		})
+		return
+	}
+
+	token, tokenHash, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao gerar confirmação",
+		})
+		return
+	}
+
+	change := &models.EmailChange{
+		UserID:    userID,
+		UserType:  userType,
+		OldEmail:  acc.email(),
+		NewEmail:  req.NewEmail,
+		TokenHash: tokenHash,
+		ExpiresAt: time.Now().Add(emailChangeTTL),
+	}
+	if err := h.emailChangeRepo.Create(ctx, change); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao solicitar alteração de email",
+		})
+		return
+	}
+
+	// Confirmação para o novo endereço
+	err = h.mailer.Enqueue(ctx, req.NewEmail, mail.TemplateEmailVerification, map[string]interface{}{
+		"Name":             acc.name(),
+		"ConfirmURL":       h.mailer.URL("/confirm-email?token=" + url.QueryEscape(token)),
+		"ExpiresInMinutes": int(emailChangeTTL.Minutes()),
+	})
+	if err != nil {
+		log.Printf("Erro ao enfileirar confirmação de troca de email: %v", err)
+	}
+
+	// Aviso para o endereço atual
+	err = h.mailer.Enqueue(ctx, acc.email(), mail.TemplateEmailChangeNotice, map[string]interface{}{
+		"Name":     acc.name(),
+		"NewEmail": req.NewEmail,
+		"ResetURL": h.mailer.URL("/forgot-password"),
+	})
+	if err != nil {
+		log.Printf("Erro ao enfileirar aviso de troca de email: %v", err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]string{
+		"mensagem": "Enviamos um link de confirmação para o novo email. A alteração será concluída após a confirmação.",
+	})
+}
+
+// ConfirmEmailChange conclui a troca de email a partir do link enviado ao novo endereço
+func (h *AccountHandler) ConfirmEmailChange(w http.ResponseWriter, r *http.Request) {
+	var req ConfirmEmailChangeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Dados inválidos",
+		})
+		return
+	}
+
+	if err := auth.ValidateOpaqueToken(req.Token); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Link inválido ou expirado",
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	change, err := h.emailChangeRepo.GetByToken(ctx, auth.HashToken(req.Token))
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		if err == mongo.ErrNoDocuments {
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "Link inválido, expirado ou já utilizado",
+			})
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao verificar link",
+		})
+		return
+	}
+
+	if err := h.emailChangeRepo.MarkAsUsed(ctx, change.ID); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		if err == repository.ErrEmailChangeUsed {
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "Link inválido, expirado ou já utilizado",
+			})
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao verificar link",
+		})
+		return
+	}
+
+	// O email pode ter sido cadastrado por outra conta desde a solicitação
+	inUse, err := h.emailInUse(ctx, change.NewEmail)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao verificar email",
+		})
+		return
+	}
+	if inUse {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Email já cadastrado",
+		})
+		return
+	}
+
+	acc, err := h.loadAccount(ctx, change.UserType, change.UserID)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Usuário não encontrado",
+		})
+		return
+	}
+
+	// A conta mudou de email por outro caminho: a solicitação não vale mais
+	if acc.email() != change.OldEmail {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "O email da conta foi alterado desde a solicitação",
+		})
+		return
+	}
+
+	acc.setEmail(change.NewEmail)
+	if err := h.saveAccount(ctx, acc); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao atualizar email",
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{
+		"mensagem": "Email alterado com sucesso",
+		"email":    change.NewEmail,
+	})
+}
@@ -0,0 +1,907 @@
+package handlers
+
+import (
+	"context"
+	"empregabemapi/candidates"
+	"empregabemapi/companies"
+	"empregabemapi/internal/mail"
+	"empregabemapi/internal/middleware"
+	"empregabemapi/internal/models"
+	"empregabemapi/internal/repository"
+	"empregabemapi/internal/webhooks"
+	"empregabemapi/jobs"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// AdminHandler reúne as ações de back-office (moderação, verificação, suspensão).
+// Toda ação é registrada no log de auditoria.
+type AdminHandler struct {
+	companyRepo   *companies.MongoRepository
+	candidateRepo *candidates.MongoRepository
+	jobRepo       *jobs.MongoRepository
+	reportRepo    *repository.JobReportsRepository
+	reviewRepo    *repository.CompanyReviewsRepository
+	auditRepo     *repository.AuditRepository
+	mailer        *mail.Mailer
+	webhooks      *webhooks.Dispatcher
+}
+
+func NewAdminHandler(
+	companyRepo *companies.MongoRepository,
+	candidateRepo *candidates.MongoRepository,
+	jobRepo *jobs.MongoRepository,
+	reportRepo *repository.JobReportsRepository,
+	reviewRepo *repository.CompanyReviewsRepository,
+	auditRepo *repository.AuditRepository,
+	mailer *mail.Mailer,
+	webhookDispatcher *webhooks.Dispatcher,
+) *AdminHandler {
+	return &AdminHandler{
+		companyRepo:   companyRepo,
+		candidateRepo: candidateRepo,
+		jobRepo:       jobRepo,
+		reportRepo:    reportRepo,
+		reviewRepo:    reviewRepo,
+		auditRepo:     auditRepo,
+		mailer:        mailer,
+		webhooks:      webhookDispatcher,
+	}
+}
+
+type ReviewVerificationRequest struct {
+	Status string `json:"status"` // "verified" ou "rejected"
+	Reason string `json:"reason"`
+}
+
+type ReviewJobModerationRequest struct {
+	Status string `json:"status"` // "approved" ou "rejected"
+	Reason string `json:"reason"`
+}
+
+type ModerateReviewRequest struct {
+	Status string `json:"status"` // "approved" ou "rejected"
+	Reason string `json:"reason"`
+}
+
+type ResolveReportsRequest struct {
+	Action string `json:"action"` // "dismiss" ou "takedown"
+	Note   string `json:"note"`
+}
+
+// reportTriageItem é uma vaga denunciada na lista de triagem
+type reportTriageItem struct {
+	*models.JobReportSummary
+	Job *jobs.ManagedJob `json:"vaga,omitempty"`
+}
+
+type AdminReasonRequest struct {
+	Reason string `json:"reason"`
+}
+
+// logAdminAction grava uma ação administrativa no log de auditoria
+func logAdminAction(ctx context.Context, auditRepo *repository.AuditRepository, r *http.Request, adminID, action, targetType, targetID string, details map[string]interface{}) {
+	entry := &models.AuditLog{
+		Action:     action,
+		ActorType:  "admin",
+		ActorID:    adminID,
+		TargetType: targetType,
+		TargetID:   targetID,
+		IP:         middleware.ClientIP(r),
+		Details:    details,
+	}
+	if err := auditRepo.Log(ctx, entry); err != nil {
+		log.Printf("Erro ao registrar auditoria %s (%s %s): %v", action, targetType, targetID, err)
+	}
+}
+
+// adminPathID extrai o ID de URLs como /admin/companies/{id}/suspend
+func adminPathID(path, prefix, suffix string) (bson.ObjectID, error) {
+	return bson.ObjectIDFromHex(strings.TrimSuffix(strings.TrimPrefix(path, prefix), suffix))
+}
+
+// decodeReason lê {"reason": "..."}; obrigatório quando required é true
+func decodeReason(w http.ResponseWriter, r *http.Request, required bool) (string, bool) {
+	var req AdminReasonRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "Dados inválidos",
+			})
+			return "", false
+		}
+	}
+
+	req.Reason = strings.TrimSpace(req.Reason)
+	if required && req.Reason == "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Campo obrigatório: reason",
+		})
+		return "", false
+	}
+
+	return req.Reason, true
+}
+
+// ListCompanies lista empresas por status de verificação (fila de aprovação)
+func (h *AdminHandler) ListCompanies(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("verification_status")
+	limit := int64(50)
+	if l, err := strconv.ParseInt(r.URL.Query().Get("limit"), 10, 64); err == nil && l > 0 && l <= 200 {
+		limit = l
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	list, err := h.companyRepo.ListByVerificationStatus(ctx, status, limit)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao buscar empresas",
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"empresas": list,
+	})
+}
+
+// ReviewCompanyVerification aprova ou rejeita a verificação de uma empresa
+func (h *AdminHandler) ReviewCompanyVerification(w http.ResponseWriter, r *http.Request) {
+	adminID := r.Context().Value(middleware.UserIDKey).(string)
+
+	companyID, err := adminPathID(r.URL.Path, "/admin/companies/", "/verification")
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "ID inválido",
+		})
+		return
+	}
+
+	var req ReviewVerificationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Dados inválidos",
+		})
+		return
+	}
+
+	req.Reason = strings.TrimSpace(req.Reason)
+	if req.Status != "verified" && req.Status != "rejected" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Status inválido. Use: verified ou rejected",
+		})
+		return
+	}
+	if req.Status == "rejected" && req.Reason == "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Informe o motivo da rejeição (reason)",
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	company, err := h.companyRepo.GetByID(ctx, companyID.Hex())
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Empresa não encontrada",
+		})
+		return
+	}
+
+	if err := h.companyRepo.SetVerification(ctx, company.ID, req.Status, req.Reason); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao atualizar verificação",
+		})
+		return
+	}
+
+	// Atualiza o selo de verificação nas vagas já publicadas
+	if err := h.jobRepo.SetCompanyVerified(ctx, company.ID, req.Status == "verified"); err != nil {
+		log.Printf("Erro ao atualizar selo de verificação nas vagas da empresa %s: %v", company.ID.Hex(), err)
+	}
+	// Vagas retidas só por a empresa não ser verificada são publicadas
+	if req.Status == "verified" {
+		if err := h.jobRepo.ReleaseUnverifiedHolds(ctx, company.ID); err != nil {
+			log.Printf("Erro ao liberar vagas retidas da empresa %s: %v", company.ID.Hex(), err)
+		}
+	}
+
+	logAdminAction(ctx, h.auditRepo, r, adminID, "admin.company_verification", "company", company.ID.Hex(), map[string]interface{}{
+		"previous_status": company.VerificationStatus,
+		"status":          req.Status,
+		"reason":          req.Reason,
+	})
+
+	err = h.mailer.Enqueue(ctx, company.Email, mail.TemplateCompanyVerification, map[string]interface{}{
+		"Name":         company.Name,
+		"Approved":     req.Status == "verified",
+		"Reason":       req.Reason,
+		"DashboardURL": h.mailer.URL("/company/dashboard"),
+	})
+	if err != nil {
+		log.Printf("Erro ao enfileirar email de verificação da empresa %s: %v", company.ID.Hex(), err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{
+		"mensagem":            "Verificação atualizada com sucesso",
+		"verification_status": req.Status,
+	})
+}
+
+// SetCompanySuspension suspende (POST .../suspend) ou reativa (POST .../unsuspend) uma empresa.
+// A suspensão bloqueia o login e encerra as sessões do dono e de todos os membros da equipe.
+func (h *AdminHandler) SetCompanySuspension(suspend bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		adminID := r.Context().Value(middleware.UserIDKey).(string)
+
+		suffix := "/unsuspend"
+		if suspend {
+			suffix = "/suspend"
+		}
+		companyID, err := adminPathID(r.URL.Path, "/admin/companies/", suffix)
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "ID inválido",
+			})
+			return
+		}
+
+		reason, ok := decodeReason(w, r, suspend)
+		if !ok {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if _, err := h.companyRepo.GetByID(ctx, companyID.Hex()); err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "Empresa não encontrada",
+			})
+			return
+		}
+
+		if err := h.companyRepo.SetSuspended(ctx, companyID, suspend, reason); err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "Erro ao atualizar empresa",
+			})
+			return
+		}
+
+		action, message := "admin.company_unsuspended", "Empresa reativada com sucesso"
+		if suspend {
+			action, message = "admin.company_suspended", "Empresa suspensa com sucesso"
+		}
+		logAdminAction(ctx, h.auditRepo, r, adminID, action, "company", companyID.Hex(), map[string]interface{}{
+			"reason": reason,
+		})
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]string{
+			"mensagem": message,
+		})
+	}
+}
+
+// SetCandidateSuspension suspende (POST .../suspend) ou reativa (POST .../unsuspend) um candidato
+func (h *AdminHandler) SetCandidateSuspension(suspend bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		adminID := r.Context().Value(middleware.UserIDKey).(string)
+
+		suffix := "/unsuspend"
+		if suspend {
+			suffix = "/suspend"
+		}
+		candidateID, err := adminPathID(r.URL.Path, "/admin/candidates/", suffix)
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "ID inválido",
+			})
+			return
+		}
+
+		reason, ok := decodeReason(w, r, suspend)
+		if !ok {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if _, err := h.candidateRepo.GetByID(ctx, candidateID.Hex()); err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "Candidato não encontrado",
+			})
+			return
+		}
+
+		if err := h.candidateRepo.SetSuspended(ctx, candidateID, suspend, reason); err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "Erro ao atualizar candidato",
+			})
+			return
+		}
+
+		action, message := "admin.candidate_unsuspended", "Candidato reativado com sucesso"
+		if suspend {
+			action, message = "admin.candidate_suspended", "Candidato suspenso com sucesso"
+		}
+		logAdminAction(ctx, h.auditRepo, r, adminID, action, "candidate", candidateID.Hex(), map[string]interface{}{
+			"reason": reason,
+		})
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]string{
+			"mensagem": message,
+		})
+	}
+}
+
+// SetJobTakedown remove (POST .../takedown) ou libera (POST .../restore) uma vaga.
+// Vagas removidas ficam inativas, somem da listagem pública e a empresa não pode reativá-las.
+func (h *AdminHandler) SetJobTakedown(takeDown bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		adminID := r.Context().Value(middleware.UserIDKey).(string)
+
+		suffix := "/restore"
+		if takeDown {
+			suffix = "/takedown"
+		}
+		jobID, err := adminPathID(r.URL.Path, "/admin/jobs/", suffix)
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "ID inválido",
+			})
+			return
+		}
+
+		reason, ok := decodeReason(w, r, takeDown)
+		if !ok {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		job, err := h.jobRepo.GetByID(ctx, jobID.Hex())
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "Vaga não encontrada",
+			})
+			return
+		}
+
+		if err := h.jobRepo.SetTakenDown(ctx, job.ID, takeDown, reason); err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "Erro ao atualizar vaga",
+			})
+			return
+		}
+
+		// A vaga restaurada continua inativa até a empresa reativá-la (job.published sai nesse momento)
+		if takeDown && jobOpen(job) {
+			h.webhooks.JobClosed(ctx, job, webhooks.CloseReasonTakenDown)
+		}
+
+		action, message := "admin.job_restored", "Vaga liberada. A empresa pode reativá-la."
+		if takeDown {
+			action, message = "admin.job_taken_down", "Vaga removida com sucesso"
+		}
+		logAdminAction(ctx, h.auditRepo, r, adminID, action, "job", job.ID.Hex(), map[string]interface{}{
+			"reason":     reason,
+			"company_id": job.CompanyID.Hex(),
+			"title":      job.Title,
+		})
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]string{
+			"mensagem": message,
+		})
+	}
+}
+
+// ListModerationQueue lista as vagas retidas para revisão (mais antigas primeiro)
+func (h *AdminHandler) ListModerationQueue(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	if status == "" {
+		status = jobs.ModerationPendingReview
+	}
+	if status != jobs.ModerationPendingReview && status != jobs.ModerationRejected {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Status inválido. Use: pending_review ou rejected",
+		})
+		return
+	}
+
+	limit := int64(50)
+	if l, err := strconv.ParseInt(r.URL.Query().Get("limit"), 10, 64); err == nil && l > 0 && l <= 200 {
+		limit = l
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	queue, err := h.jobRepo.ListByModerationStatus(ctx, status, limit)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao buscar fila de moderação",
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"vagas": jobs.ManagedList(queue),
+	})
+}
+
+// ReviewJobModeration aprova ou rejeita uma vaga da fila de moderação e avisa a empresa
+func (h *AdminHandler) ReviewJobModeration(w http.ResponseWriter, r *http.Request) {
+	adminID := r.Context().Value(middleware.UserIDKey).(string)
+
+	jobID, err := adminPathID(r.URL.Path, "/admin/jobs/", "/moderation")
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "ID inválido",
+		})
+		return
+	}
+
+	var req ReviewJobModerationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Dados inválidos",
+		})
+		return
+	}
+
+	req.Reason = strings.TrimSpace(req.Reason)
+	if req.Status != jobs.ModerationApproved && req.Status != jobs.ModerationRejected {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Status inválido. Use: approved ou rejected",
+		})
+		return
+	}
+	if req.Status == jobs.ModerationRejected && req.Reason == "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Informe o motivo da rejeição (reason)",
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	job, err := h.jobRepo.GetByID(ctx, jobID.Hex())
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Vaga não encontrada",
+		})
+		return
+	}
+
+	if err := h.jobRepo.SetModeration(ctx, job.ID, req.Status, req.Reason); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao atualizar vaga",
+		})
+		return
+	}
+
+	logAdminAction(ctx, h.auditRepo, r, adminID, "admin.job_moderation", "job", job.ID.Hex(), map[string]interface{}{
+		"previous_status": job.ModerationStatus,
+		"status":          req.Status,
+		"reason":          req.Reason,
+		"flags":           job.ModerationFlags,
+		"company_id":      job.CompanyID.Hex(),
+	})
+
+	wasOpen := jobOpen(job)
+	job.ModerationStatus = req.Status
+	if open := jobOpen(job); open && !wasOpen {
+		h.webhooks.JobPublished(ctx, job)
+	} else if !open && wasOpen {
+		h.webhooks.JobClosed(ctx, job, webhooks.CloseReasonUnderReview)
+	}
+
+	if company, err := h.companyRepo.GetByID(ctx, job.CompanyID.Hex()); err == nil {
+		err = h.mailer.Enqueue(ctx, company.Email, mail.TemplateJobModeration, map[string]interface{}{
+			"CompanyName":  company.Name,
+			"JobTitle":     job.Title,
+			"Approved":     req.Status == jobs.ModerationApproved,
+			"Reason":       req.Reason,
+			"DashboardURL": h.mailer.URL("/company/dashboard"),
+		})
+		if err != nil {
+			log.Printf("Erro ao enfileirar email de moderação da vaga %s: %v", job.ID.Hex(), err)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{
+		"mensagem":          "Moderação da vaga atualizada com sucesso",
+		"moderation_status": req.Status,
+	})
+}
+
+// ListReports lista as vagas com denúncias abertas (mais denunciadas primeiro) ou,
+// com ?job_id=, as denúncias de uma vaga
+func (h *AdminHandler) ListReports(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if jobIDParam := r.URL.Query().Get("job_id"); jobIDParam != "" {
+		jobID, err := bson.ObjectIDFromHex(jobIDParam)
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "ID inválido",
+			})
+			return
+		}
+
+		reports, err := h.reportRepo.ListByJob(ctx, jobID, r.URL.Query().Get("status"))
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "Erro ao buscar denúncias",
+			})
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"denuncias": reports,
+		})
+		return
+	}
+
+	limit := int64(50)
+	if l, err := strconv.ParseInt(r.URL.Query().Get("limit"), 10, 64); err == nil && l > 0 && l <= 100 {
+		limit = l
+	}
+
+	summaries, err := h.reportRepo.SummarizeOpen(ctx, limit)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao buscar denúncias",
+		})
+		return
+	}
+
+	items := make([]reportTriageItem, 0, len(summaries))
+	for _, summary := range summaries {
+		item := reportTriageItem{JobReportSummary: summary}
+		if job, err := h.jobRepo.GetByID(ctx, summary.JobID.Hex()); err == nil {
+			managed := job.Managed()
+			item.Job = &managed
+		}
+		items = append(items, item)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"vagas_denunciadas": items,
+	})
+}
+
+// ResolveReports encerra as denúncias abertas de uma vaga: "dismiss" (improcedentes, a vaga volta
+// ao ar se estava retida só pelas denúncias) ou "takedown" (remove a vaga)
+func (h *AdminHandler) ResolveReports(w http.ResponseWriter, r *http.Request) {
+	adminID := r.Context().Value(middleware.UserIDKey).(string)
+
+	jobID, err := adminPathID(r.URL.Path, "/admin/jobs/", "/reports/resolve")
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "ID inválido",
+		})
+		return
+	}
+
+	var req ResolveReportsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Dados inválidos",
+		})
+		return
+	}
+
+	req.Note = strings.TrimSpace(req.Note)
+	if req.Action != "dismiss" && req.Action != "takedown" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Ação inválida. Use: dismiss ou takedown",
+		})
+		return
+	}
+	if req.Action == "takedown" && req.Note == "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Informe o motivo da remoção (note)",
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	job, err := h.jobRepo.GetByID(ctx, jobID.Hex())
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Vaga não encontrada",
+		})
+		return
+	}
+
+	status := models.ReportStatusDismissed
+	if req.Action == "takedown" {
+		status = models.ReportStatusActioned
+		if err := h.jobRepo.SetTakenDown(ctx, job.ID, true, req.Note); err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "Erro ao remover vaga",
+			})
+			return
+		}
+	}
+
+	resolved, err := h.reportRepo.ResolveOpenByJob(ctx, job.ID, status, adminID, req.Note)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao atualizar denúncias",
+		})
+		return
+	}
+
+	if req.Action == "dismiss" {
+		if err := h.jobRepo.ReleaseHold(ctx, job.ID, jobs.RuleReported); err != nil {
+			log.Printf("Erro ao liberar vaga denunciada %s: %v", job.ID.Hex(), err)
+		}
+	}
+
+	logAdminAction(ctx, h.auditRepo, r, adminID, "admin.job_reports_"+status, "job", job.ID.Hex(), map[string]interface{}{
+		"reports":    resolved,
+		"note":       req.Note,
+		"company_id": job.CompanyID.Hex(),
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"mensagem":             "Denúncias resolvidas com sucesso",
+		"denuncias_resolvidas": resolved,
+	})
+}
+
+// ListReviews lista as avaliações de empresas por status de moderação (padrão: pendentes, mais antigas primeiro)
+func (h *AdminHandler) ListReviews(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	if status == "" {
+		status = models.ReviewStatusPending
+	}
+	if status != models.ReviewStatusPending && status != models.ReviewStatusApproved && status != models.ReviewStatusRejected {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Status inválido. Use: pending, approved ou rejected",
+		})
+		return
+	}
+
+	limit := int64(50)
+	if l, err := strconv.ParseInt(r.URL.Query().Get("limit"), 10, 64); err == nil && l > 0 && l <= 200 {
+		limit = l
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	reviews, err := h.reviewRepo.ListByStatus(ctx, status, limit)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao buscar avaliações",
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"avaliacoes": reviews,
+	})
+}
+
+// ModerateReview aprova (publica) ou rejeita uma avaliação de empresa
+func (h *AdminHandler) ModerateReview(w http.ResponseWriter, r *http.Request) {
+	adminID := r.Context().Value(middleware.UserIDKey).(string)
+
+	reviewID, err := adminPathID(r.URL.Path, "/admin/reviews/", "/moderation")
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "ID inválido",
+		})
+		return
+	}
+
+	var req ModerateReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Dados inválidos",
+		})
+		return
+	}
+
+	req.Reason = strings.TrimSpace(req.Reason)
+	if req.Status != models.ReviewStatusApproved && req.Status != models.ReviewStatusRejected {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Status inválido. Use: approved ou rejected",
+		})
+		return
+	}
+	if req.Status == models.ReviewStatusRejected && req.Reason == "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Informe o motivo da rejeição (reason)",
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	review, err := h.reviewRepo.GetByID(ctx, reviewID)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Avaliação não encontrada",
+		})
+		return
+	}
+
+	if err := h.reviewRepo.SetModeration(ctx, review.ID, req.Status, req.Reason, adminID); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao atualizar avaliação",
+		})
+		return
+	}
+
+	logAdminAction(ctx, h.auditRepo, r, adminID, "admin.review_moderation", "company_review", review.ID.Hex(), map[string]interface{}{
+		"previous_status": review.Status,
+		"status":          req.Status,
+		"reason":          req.Reason,
+		"company_id":      review.CompanyID.Hex(),
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{
+		"mensagem": "Moderação da avaliação atualizada com sucesso",
+		"status":   req.Status,
+	})
+}
+
+// ListAudit lista o log de auditoria (mais recentes primeiro), opcionalmente filtrado por ação
+func (h *AdminHandler) ListAudit(w http.ResponseWriter, r *http.Request) {
+	limit := int64(100)
+	if l, err := strconv.ParseInt(r.URL.Query().Get("limit"), 10, 64); err == nil && l > 0 && l <= 500 {
+		limit = l
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	entries, err := h.auditRepo.List(ctx, r.URL.Query().Get("action"), limit)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao buscar auditoria",
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"registros": entries,
+	})
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"context"
+	"empregabemapi/admins"
+	"empregabemapi/internal/auth"
+	"empregabemapi/internal/lockout"
+	"empregabemapi/internal/middleware"
+	"empregabemapi/internal/repository"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+type AdminAuthHandler struct {
+	repo      *admins.MongoRepository
+	auditRepo *repository.AuditRepository
+	lockout   *lockout.Service
+}
+
+func NewAdminAuthHandler(repo *admins.MongoRepository, auditRepo *repository.AuditRepository, lockoutService *lockout.Service) *AdminAuthHandler {
+	return &AdminAuthHandler{
+		repo:      repo,
+		auditRepo: auditRepo,
+		lockout:   lockoutService,
+	}
+}
+
+func (h *AdminAuthHandler) Login(w http.ResponseWriter, r *http.Request) {
+	var req LoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Dados inválidos",
+		})
+		return
+	}
+
+	// Sanitizar email
+	req.Email = auth.SanitizeEmail(req.Email)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Bloqueio por tentativas excessivas (conta ou IP)
+	clientIP := middleware.ClientIP(r)
+	if wait, err := h.lockout.Check(ctx, "admin", req.Email, clientIP); err == nil && wait > 0 {
+		writeLoginLocked(w, wait)
+		return
+	}
+
+	admin, err := h.repo.GetByEmail(ctx, req.Email)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			h.lockout.RecordFailure(ctx, "admin", req.Email, clientIP)
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "Email ou senha incorretos",
+			})
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao buscar administrador",
+		})
+		return
+	}
+
+	// Verifica senha
+	if !auth.CheckPasswordHash(req.Password, admin.Password) {
+		h.lockout.RecordFailure(ctx, "admin", req.Email, clientIP)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Email ou senha incorretos",
+		})
+		return
+	}
+
+	h.lockout.RecordSuccess(ctx, "admin", req.Email)
+
+	// Gera token
+	token, err := auth.GenerateToken(admin.ID.Hex(), "admin")
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao gerar token",
+		})
+		return
+	}
+
+	logAdminAction(ctx, h.auditRepo, r, admin.ID.Hex(), "admin.login", "admin", admin.ID.Hex(), nil)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"token": token,
+		"admin": admin,
+	})
+}
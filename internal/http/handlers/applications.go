@@ -4,10 +4,16 @@ import (
 	"context"
 	"empregabemapi/applications"
 	"empregabemapi/candidates"
+	"empregabemapi/internal/mail"
+	"empregabemapi/internal/matching"
 	"empregabemapi/internal/middleware"
+	"empregabemapi/internal/notifications"
+	"empregabemapi/internal/webhooks"
 	"empregabemapi/jobs"
 	"encoding/json"
+	"log"
 	"net/http"
+	"sort"
 	"strings"
 	"time"
 
@@ -18,16 +24,33 @@ type ApplicationsHandler struct {
 	appRepo       *applications.MongoRepository
 	jobRepo       *jobs.MongoRepository
 	candidateRepo *candidates.MongoRepository
+	mailer        *mail.Mailer
+	notifier      *notifications.Service
+	webhooks      *webhooks.Dispatcher
 }
 
-func NewApplicationsHandler(appRepo *applications.MongoRepository, jobRepo *jobs.MongoRepository, candidateRepo *candidates.MongoRepository) *ApplicationsHandler {
+func NewApplicationsHandler(appRepo *applications.MongoRepository, jobRepo *jobs.MongoRepository, candidateRepo *candidates.MongoRepository, mailer *mail.Mailer, notifier *notifications.Service, webhookDispatcher *webhooks.Dispatcher) *ApplicationsHandler {
 	return &ApplicationsHandler{
 		appRepo:       appRepo,
 		jobRepo:       jobRepo,
 		candidateRepo: candidateRepo,
+		mailer:        mailer,
+		notifier:      notifier,
+		webhooks:      webhookDispatcher,
 	}
 }
 
+// applicationStatusLabels traduz os status de candidatura para exibição ao candidato
+var applicationStatusLabels = map[string]string{
+	"pending":     "Pendente",
+	"viewed":      "Visualizada pela empresa",
+	"in_review":   "Em análise",
+	"shortlisted": "Pré-selecionada",
+	"interview":   "Entrevista",
+	"rejected":    "Não selecionada",
+	"accepted":    "Aprovada",
+}
+
 type ApplyRequest struct {
 	JobID   string `json:"job_id"`
 	Message string `json:"message,omitempty"`
@@ -69,7 +92,7 @@ func (h *ApplicationsHandler) Apply(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if !job.IsActive {
+	if !job.IsActive || !job.IsPublic() {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusBadRequest)
 		json.NewEncoder(w).Encode(map[string]string{
@@ -125,6 +148,16 @@ func (h *ApplicationsHandler) Apply(w http.ResponseWriter, r *http.Request) {
 		// O candidato já foi criado com sucesso
 	}
 
+	// Avisa o dono e a equipe responsável pela vaga
+	h.notifier.ApplicationCreated(ctx, job, application)
+
+	// Integrações da empresa (ATS) recebem a candidatura com os dados do candidato
+	candidate, err := h.candidateRepo.GetByID(ctx, candidateID)
+	if err != nil {
+		log.Printf("Erro ao buscar candidato %s para o webhook: %v", candidateID, err)
+	}
+	h.webhooks.ApplicationCreated(ctx, job, application, candidate)
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(map[string]interface{}{
@@ -234,6 +267,17 @@ func (h *ApplicationsHandler) ListJobApplicants(w http.ResponseWriter, r *http.R
 		jobID = jobID[:len(jobID)-11]
 	}
 
+	// Ordenação opcional: ?sort=score lista primeiro os candidatos com maior aderência aos requisitos
+	sortBy := r.URL.Query().Get("sort")
+	if sortBy != "" && sortBy != "score" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Ordenação inválida. Use: score",
+		})
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
@@ -257,6 +301,16 @@ func (h *ApplicationsHandler) ListJobApplicants(w http.ResponseWriter, r *http.R
 		return
 	}
 
+	// Recrutadores e visualizadores só veem vagas em que estão na equipe de contratação
+	if !canAccessJobApplicants(r, job) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Você não está na equipe de contratação desta vaga",
+		})
+		return
+	}
+
 	// Busca candidaturas
 	apps, err := h.appRepo.GetByJobID(ctx, jobID)
 	if err != nil {
@@ -272,6 +326,8 @@ func (h *ApplicationsHandler) ListJobApplicants(w http.ResponseWriter, r *http.R
 	type ApplicantData struct {
 		Application *applications.Application `json:"application"`
 		Candidate   *candidates.Candidate     `json:"candidate"`
+		// Aderência das habilidades do candidato aos requisitos (null se a vaga não tem requisitos)
+		Match *matching.Result `json:"match"`
 	}
 
 	var applicants []ApplicantData
@@ -281,10 +337,18 @@ func (h *ApplicationsHandler) ListJobApplicants(w http.ResponseWriter, r *http.R
 			applicants = append(applicants, ApplicantData{
 				Application: app,
 				Candidate:   candidate,
+				Match:       matching.Score(job.Requirements, candidate.Skills),
 			})
 		}
 	}
 
+	// Maior aderência primeiro; empates mantêm a ordem das candidaturas
+	if sortBy == "score" {
+		sort.SliceStable(applicants, func(i, j int) bool {
+			return matchScore(applicants[i].Match) > matchScore(applicants[j].Match)
+		})
+	}
+
 	// Sincroniza o contador de candidatos com o número real
 	realCount := len(applicants)
 	if realCount != job.Applicants {
@@ -297,7 +361,7 @@ func (h *ApplicationsHandler) ListJobApplicants(w http.ResponseWriter, r *http.R
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(map[string]interface{}{
-		"vaga":       job,
+		"vaga":       job.Managed(),
 		"candidatos": applicants,
 	})
 }
@@ -382,7 +446,23 @@ func (h *ApplicationsHandler) UpdateApplicationStatus(w http.ResponseWriter, r *
 		return
 	}
 
+	// Recrutadores só movimentam candidaturas das vagas em que estão na equipe de contratação
+	job, err := h.jobRepo.GetByID(ctx, app.JobID.Hex())
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Vaga não encontrada"})
+		return
+	}
+	if !canAccessJobApplicants(r, job) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Você não está na equipe de contratação desta vaga"})
+		return
+	}
+
 	// Atualizar status
+	previousStatus := app.Status
 	app.Status = req.Status
 	app.UpdatedAt = time.Now()
 
@@ -399,6 +479,46 @@ func (h *ApplicationsHandler) UpdateApplicationStatus(w http.ResponseWriter, r *
 		return
 	}
 
+	// Notificar o candidato (email e central de notificações) quando o status muda de fato
+	if previousStatus != app.Status {
+		h.notifyStatusChange(ctx, app)
+		h.notifier.ApplicationStatusChanged(ctx, job, app, applicationStatusLabels[app.Status])
+		h.webhooks.ApplicationStatusChanged(ctx, job, app, previousStatus)
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]string{"message": "Status atualizado com sucesso"})
 }
+
+// notifyStatusChange enfileira o email de mudança de status para o candidato
+func (h *ApplicationsHandler) notifyStatusChange(ctx context.Context, app *applications.Application) {
+	candidate, err := h.candidateRepo.GetByID(ctx, app.CandidateID.Hex())
+	if err != nil {
+		return
+	}
+
+	var jobTitle, companyName string
+	if job, err := h.jobRepo.GetByID(ctx, app.JobID.Hex()); err == nil {
+		jobTitle = job.Title
+		companyName = job.Company
+	}
+
+	err = h.mailer.Enqueue(ctx, candidate.Email, mail.TemplateApplicationStatus, map[string]interface{}{
+		"Name":            candidate.Name,
+		"JobTitle":        jobTitle,
+		"Company":         companyName,
+		"StatusLabel":     applicationStatusLabels[app.Status],
+		"ApplicationsURL": h.mailer.URL("/candidate/applications"),
+	})
+	if err != nil {
+		log.Printf("Erro ao enfileirar email de status da candidatura %s: %v", app.ID.Hex(), err)
+	}
+}
+
+// matchScore retorna a pontuação da aderência (-1 sem requisitos, para ficar no fim da ordenação)
+func matchScore(match *matching.Result) int {
+	if match == nil {
+		return -1
+	}
+	return match.Score
+}
@@ -5,6 +5,8 @@ import (
 	"empregabemapi/candidates"
 	"empregabemapi/companies"
 	"empregabemapi/internal/auth"
+	"empregabemapi/internal/lockout"
+	"empregabemapi/internal/middleware"
 	"encoding/json"
 	"net/http"
 	"time"
@@ -15,12 +17,16 @@ import (
 type CandidateAuthHandler struct {
 	repo        *candidates.MongoRepository
 	companyRepo *companies.MongoRepository
+	memberRepo  *companies.MemberRepository
+	lockout     *lockout.Service
 }
 
-func NewCandidateAuthHandler(repo *candidates.MongoRepository, companyRepo *companies.MongoRepository) *CandidateAuthHandler {
+func NewCandidateAuthHandler(repo *candidates.MongoRepository, companyRepo *companies.MongoRepository, memberRepo *companies.MemberRepository, lockoutService *lockout.Service) *CandidateAuthHandler {
 	return &CandidateAuthHandler{
 		repo:        repo,
 		companyRepo: companyRepo,
+		memberRepo:  memberRepo,
+		lockout:     lockoutService,
 	}
 }
 
@@ -91,6 +97,17 @@ func (h *CandidateAuthHandler) Register(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	// Verifica se email já pertence a um membro de equipe de empresa
+	_, err = h.memberRepo.GetByEmail(ctx, req.Email)
+	if err == nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Email já cadastrado como empresa. Use outro email.",
+		})
+		return
+	}
+
 	// Criptografa senha
 	hashedPassword, err := auth.HashPassword(req.Password)
 	if err != nil {
@@ -156,9 +173,17 @@ func (h *CandidateAuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
+	// Bloqueio por tentativas excessivas (conta ou IP)
+	clientIP := middleware.ClientIP(r)
+	if wait, err := h.lockout.Check(ctx, "candidate", req.Email, clientIP); err == nil && wait > 0 {
+		writeLoginLocked(w, wait)
+		return
+	}
+
 	candidate, err := h.repo.GetByEmail(ctx, req.Email)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
+			h.lockout.RecordFailure(ctx, "candidate", req.Email, clientIP)
 			w.Header().Set("Content-Type", "application/json")
 			w.WriteHeader(http.StatusUnauthorized)
 			json.NewEncoder(w).Encode(map[string]string{
@@ -176,6 +201,7 @@ func (h *CandidateAuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 
 	// Verifica senha
 	if !auth.CheckPasswordHash(req.Password, candidate.Password) {
+		h.lockout.RecordFailure(ctx, "candidate", req.Email, clientIP)
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusUnauthorized)
 		json.NewEncoder(w).Encode(map[string]string{
@@ -184,6 +210,13 @@ func (h *CandidateAuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.lockout.RecordSuccess(ctx, "candidate", req.Email)
+
+	if candidate.SuspendedAt != nil {
+		writeAccountSuspended(w)
+		return
+	}
+
 	// Gera token
 	token, err := auth.GenerateToken(candidate.ID.Hex(), "candidate")
 	if err != nil {
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"context"
+	"empregabemapi/companies"
+	"empregabemapi/internal/repository"
+	"empregabemapi/jobs"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+type PublicCompanyHandler struct {
+	companyRepo *companies.MongoRepository
+	jobRepo     *jobs.MongoRepository
+	reviewRepo  *repository.CompanyReviewsRepository
+}
+
+func NewPublicCompanyHandler(companyRepo *companies.MongoRepository, jobRepo *jobs.MongoRepository, reviewRepo *repository.CompanyReviewsRepository) *PublicCompanyHandler {
+	return &PublicCompanyHandler{
+		companyRepo: companyRepo,
+		jobRepo:     jobRepo,
+		reviewRepo:  reviewRepo,
+	}
+}
+
+// findPublicCompany busca a empresa pelo ID ou slug. Empresas suspensas não têm página pública.
+// Slugs nunca têm formato de ID, então não há ambiguidade.
+func findPublicCompany(ctx context.Context, repo *companies.MongoRepository, ref string) (*companies.Company, error) {
+	var company *companies.Company
+	var err error
+	if _, idErr := bson.ObjectIDFromHex(ref); idErr == nil {
+		company, err = repo.GetByID(ctx, ref)
+	} else {
+		company, err = repo.GetBySlug(ctx, strings.ToLower(ref))
+	}
+	if err != nil {
+		return nil, err
+	}
+	if company.SuspendedAt != nil {
+		return nil, mongo.ErrNoDocuments
+	}
+	return company, nil
+}
+
+// GetProfile retorna a página pública da empresa com suas vagas ativas (GET /companies/{id-ou-slug})
+func (h *PublicCompanyHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
+	ref := strings.Trim(strings.TrimPrefix(r.URL.Path, "/companies/"), "/")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	company, err := findPublicCompany(ctx, h.companyRepo, ref)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Empresa não encontrada",
+		})
+		return
+	}
+
+	jobsList, err := h.jobRepo.ListPublicByCompany(ctx, company.ID)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao buscar vagas da empresa",
+		})
+		return
+	}
+	if jobsList == nil {
+		jobsList = []*jobs.Job{}
+	}
+
+	// Sem médias suficientes para publicar, "avaliacoes" vai como null
+	reviews, err := publishedReviewSummary(ctx, h.reviewRepo, company.ID)
+	if err != nil {
+		log.Printf("Erro ao calcular avaliações da empresa %s: %v", company.ID.Hex(), err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"empresa":    company.PublicProfile(),
+		"avaliacoes": reviews,
+		"vagas":      jobsList,
+		"total":      len(jobsList),
+	})
+}
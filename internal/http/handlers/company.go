@@ -4,18 +4,24 @@ import (
 	"context"
 	"empregabemapi/companies"
 	"empregabemapi/internal/middleware"
+	"empregabemapi/jobs"
 	"encoding/json"
+	"fmt"
+	"log"
 	"net/http"
+	"strings"
 	"time"
 )
 
 type CompanyHandler struct {
-	repo *companies.MongoRepository
+	repo    *companies.MongoRepository
+	jobRepo *jobs.MongoRepository
 }
 
-func NewCompanyHandler(repo *companies.MongoRepository) *CompanyHandler {
+func NewCompanyHandler(repo *companies.MongoRepository, jobRepo *jobs.MongoRepository) *CompanyHandler {
 	return &CompanyHandler{
-		repo: repo,
+		repo:    repo,
+		jobRepo: jobRepo,
 	}
 }
 
@@ -68,18 +74,46 @@ func (h *CompanyHandler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Slug só muda quando a empresa escolhe um novo (renomear não quebra links já divulgados)
+	if slug := strings.ToLower(strings.TrimSpace(updateData.Slug)); slug != "" && slug != company.Slug {
+		if !companies.ValidSlug(slug) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": fmt.Sprintf("Slug inválido. Use de %d a %d letras minúsculas, números e hífens", companies.MinSlugLength, companies.MaxSlugLength),
+			})
+			return
+		}
+		company.Slug = slug
+	}
+
 	// Mantém dados que não devem ser alterados
+	previousName, previousSector := company.Name, company.Sector
 	company.Name = updateData.Name
-	company.LegalName = updateData.LegalName
+	// Razão social de empresa verificada não muda (foi conferida na verificação)
+	if company.VerificationStatus != "verified" {
+		company.LegalName = updateData.LegalName
+	}
 	company.Phone = updateData.Phone
 	company.Website = updateData.Website
-	company.Logo = updateData.Logo
+	// Logo enviado por upload só é trocado por outro upload ou removido em DELETE /company/me/logo
+	if company.LogoImage == nil {
+		company.Logo = updateData.Logo
+	}
 	company.About = updateData.About
 	company.EmployeeCount = updateData.EmployeeCount
 	company.Location = updateData.Location
 	company.Sector = updateData.Sector
 
 	if err := h.repo.Update(ctx, company); err != nil {
+		if err == companies.ErrSlugTaken {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "Este slug já está em uso por outra empresa",
+			})
+			return
+		}
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode(map[string]string{
@@ -88,6 +122,18 @@ func (h *CompanyHandler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// As vagas guardam o nome da empresa; propaga a renomeação para não exibir o nome antigo
+	if company.Name != previousName {
+		if err := h.jobRepo.SetCompanyName(ctx, company.ID, company.Name); err != nil {
+			log.Printf("Erro ao propagar novo nome da empresa %s para as vagas: %v", company.ID.Hex(), err)
+		}
+	}
+	if company.Sector != previousSector {
+		if err := h.jobRepo.SetCompanySector(ctx, company.ID, company.Sector); err != nil {
+			log.Printf("Erro ao propagar novo setor da empresa %s para as vagas: %v", company.ID.Hex(), err)
+		}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(map[string]interface{}{
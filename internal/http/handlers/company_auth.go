@@ -5,8 +5,15 @@ import (
 	"empregabemapi/candidates"
 	"empregabemapi/companies"
 	"empregabemapi/internal/auth"
+	"empregabemapi/internal/lockout"
+	"empregabemapi/internal/middleware"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
 	"net/http"
+	"strconv"
 	"time"
 
 	"go.mongodb.org/mongo-driver/v2/mongo"
@@ -14,13 +21,19 @@ import (
 
 type CompanyAuthHandler struct {
 	repo          *companies.MongoRepository
+	memberRepo    *companies.MemberRepository
 	candidateRepo *candidates.MongoRepository
+	lockout       *lockout.Service
+	cnpjLookup    companies.CNPJLookup // nil = sem consulta (verificação apenas manual)
 }
 
-func NewCompanyAuthHandler(repo *companies.MongoRepository, candidateRepo *candidates.MongoRepository) *CompanyAuthHandler {
+func NewCompanyAuthHandler(repo *companies.MongoRepository, memberRepo *companies.MemberRepository, candidateRepo *candidates.MongoRepository, lockoutService *lockout.Service, cnpjLookup companies.CNPJLookup) *CompanyAuthHandler {
 	return &CompanyAuthHandler{
 		repo:          repo,
+		memberRepo:    memberRepo,
 		candidateRepo: candidateRepo,
+		lockout:       lockoutService,
+		cnpjLookup:    cnpjLookup,
 	}
 }
 
@@ -39,6 +52,27 @@ type LoginRequest struct {
 	Password string `json:"password"`
 }
 
+// writeLoginLocked responde 429 quando o login está temporariamente bloqueado
+func writeLoginLocked(w http.ResponseWriter, wait time.Duration) {
+	minutes := int(math.Ceil(wait.Minutes()))
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+	w.WriteHeader(http.StatusTooManyRequests)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"erro":            fmt.Sprintf("Muitas tentativas de login. Tente novamente em %d minuto(s) ou redefina sua senha.", minutes),
+		"retry_after_sec": int(math.Ceil(wait.Seconds())),
+	})
+}
+
+// writeAccountSuspended responde 403 para contas suspensas pela moderação
+func writeAccountSuspended(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusForbidden)
+	json.NewEncoder(w).Encode(map[string]string{
+		"erro": "Conta suspensa. Entre em contato com o suporte.",
+	})
+}
+
 func (h *CompanyAuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 	var req RegisterCompanyRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -73,12 +107,13 @@ func (h *CompanyAuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 	// Sanitizar email
 	req.Email = auth.SanitizeEmail(req.Email)
 
-	// Validar formato de CNPJ (14 dígitos)
-	if len(req.CNPJ) != 14 {
+	// Aceita CNPJ formatado (12.345.678/0001-90) e valida os dígitos verificadores
+	req.CNPJ = companies.NormalizeCNPJ(req.CNPJ)
+	if !companies.ValidCNPJ(req.CNPJ) {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusBadRequest)
 		json.NewEncoder(w).Encode(map[string]string{
-			"erro": "CNPJ deve conter exatamente 14 dígitos",
+			"erro": "CNPJ inválido",
 		})
 		return
 	}
@@ -108,6 +143,17 @@ func (h *CompanyAuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Verifica se email já pertence a um membro de equipe de outra empresa
+	_, err = h.memberRepo.GetByEmail(ctx, req.Email)
+	if err == nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Email já cadastrado",
+		})
+		return
+	}
+
 	// Verifica se CNPJ já existe
 	_, err = h.repo.GetByCNPJ(ctx, req.CNPJ)
 	if err == nil {
@@ -140,7 +186,14 @@ func (h *CompanyAuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 		Location:  req.Location,
 	}
 
-	if err := h.repo.Create(ctx, company); err != nil {
+	// Completa o cadastro com os dados da Receita e verifica automaticamente se conferem
+	h.applyCNPJLookup(company)
+
+	// A consulta pode levar até 5s: a gravação tem seu próprio prazo, não o que sobrou das verificações
+	createCtx, createCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer createCancel()
+
+	if err := h.repo.Create(createCtx, company); err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode(map[string]string{
@@ -169,6 +222,47 @@ func (h *CompanyAuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// applyCNPJLookup preenche razão social e endereço a partir da consulta do CNPJ e marca a
+// empresa como verificada quando ela está ativa, a razão social informada confere e o email ou o
+// telefone do cadastro batem com os da Receita. Sem isso (ou se a consulta falhar) a empresa fica
+// pendente de verificação manual.
+func (h *CompanyAuthHandler) applyCNPJLookup(company *companies.Company) {
+	if h.cnpjLookup == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	info, err := h.cnpjLookup.Lookup(ctx, company.CNPJ)
+	if err != nil {
+		if !errors.Is(err, companies.ErrCNPJNotFound) {
+			log.Printf("Erro ao consultar CNPJ %s: %v", company.CNPJ, err)
+		}
+		return
+	}
+
+	// Só confere a razão social que a própria empresa informou; o contato comprova que o cadastro
+	// é da empresa e não de alguém que conhece seus dados públicos
+	if info.MatchesLegalName(company.LegalName) && info.MatchesContact(company.Email, company.Phone) {
+		now := time.Now()
+		company.VerificationStatus = "verified"
+		company.VerificationReason = "Dados conferidos automaticamente na consulta do CNPJ"
+		company.VerificationReviewedAt = &now
+	}
+
+	if company.LegalName == "" {
+		company.LegalName = info.LegalName
+	}
+	if info.Address.City != "" {
+		address := info.Address
+		company.Address = &address
+		if company.Location == "" {
+			company.Location = info.Address.City + " - " + info.Address.State
+		}
+	}
+}
+
 func (h *CompanyAuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	var req LoginRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -186,9 +280,71 @@ func (h *CompanyAuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
+	// Bloqueio por tentativas excessivas (conta ou IP)
+	clientIP := middleware.ClientIP(r)
+	if wait, err := h.lockout.Check(ctx, "company", req.Email, clientIP); err == nil && wait > 0 {
+		writeLoginLocked(w, wait)
+		return
+	}
+
 	company, err := h.repo.GetByEmail(ctx, req.Email)
+	if err == mongo.ErrNoDocuments {
+		// Não é o dono: pode ser um membro da equipe de alguma empresa
+		h.loginMember(ctx, w, req, clientIP)
+		return
+	}
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao buscar empresa",
+		})
+		return
+	}
+
+	// Verifica senha
+	if !auth.CheckPasswordHash(req.Password, company.Password) {
+		h.lockout.RecordFailure(ctx, "company", req.Email, clientIP)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Email ou senha incorretos",
+		})
+		return
+	}
+
+	h.lockout.RecordSuccess(ctx, "company", req.Email)
+
+	if company.SuspendedAt != nil {
+		writeAccountSuspended(w)
+		return
+	}
+
+	// Gera token
+	token, err := auth.GenerateToken(company.ID.Hex(), "company")
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao gerar token",
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"token":   token,
+		"empresa": company,
+	})
+}
+
+// loginMember autentica um membro da equipe (recrutador, gestor...) de uma empresa
+func (h *CompanyAuthHandler) loginMember(ctx context.Context, w http.ResponseWriter, req LoginRequest, clientIP string) {
+	member, err := h.memberRepo.GetByEmail(ctx, req.Email)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
+			h.lockout.RecordFailure(ctx, "company", req.Email, clientIP)
 			w.Header().Set("Content-Type", "application/json")
 			w.WriteHeader(http.StatusUnauthorized)
 			json.NewEncoder(w).Encode(map[string]string{
@@ -204,8 +360,8 @@ func (h *CompanyAuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Verifica senha
-	if !auth.CheckPasswordHash(req.Password, company.Password) {
+	if !auth.CheckPasswordHash(req.Password, member.Password) {
+		h.lockout.RecordFailure(ctx, "company", req.Email, clientIP)
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusUnauthorized)
 		json.NewEncoder(w).Encode(map[string]string{
@@ -214,8 +370,24 @@ func (h *CompanyAuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Gera token
-	token, err := auth.GenerateToken(company.ID.Hex(), "company")
+	company, err := h.repo.GetByID(ctx, member.CompanyID.Hex())
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao buscar empresa",
+		})
+		return
+	}
+
+	h.lockout.RecordSuccess(ctx, "company", req.Email)
+
+	if company.SuspendedAt != nil {
+		writeAccountSuspended(w)
+		return
+	}
+
+	token, err := auth.GenerateMemberToken(company.ID.Hex(), member.ID.Hex(), string(member.Role))
 	if err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusInternalServerError)
@@ -230,5 +402,6 @@ func (h *CompanyAuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"token":   token,
 		"empresa": company,
+		"membro":  member,
 	})
 }
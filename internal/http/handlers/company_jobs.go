@@ -4,8 +4,10 @@ import (
 	"context"
 	"empregabemapi/companies"
 	"empregabemapi/internal/middleware"
+	"empregabemapi/internal/webhooks"
 	"empregabemapi/jobs"
 	"encoding/json"
+	"log"
 	"net/http"
 	"time"
 
@@ -15,15 +17,99 @@ import (
 type CompanyJobsHandler struct {
 	jobRepo     *jobs.MongoRepository
 	companyRepo *companies.MongoRepository
+	memberRepo  *companies.MemberRepository
+	webhooks    *webhooks.Dispatcher
 }
 
-func NewCompanyJobsHandler(jobRepo *jobs.MongoRepository, companyRepo *companies.MongoRepository) *CompanyJobsHandler {
+func NewCompanyJobsHandler(jobRepo *jobs.MongoRepository, companyRepo *companies.MongoRepository, memberRepo *companies.MemberRepository, webhookDispatcher *webhooks.Dispatcher) *CompanyJobsHandler {
 	return &CompanyJobsHandler{
 		jobRepo:     jobRepo,
 		companyRepo: companyRepo,
+		memberRepo:  memberRepo,
+		webhooks:    webhookDispatcher,
 	}
 }
 
+// Vagas com o mesmo título e descrição publicadas nesta janela contam como duplicadas.
+// A própria empresa pode repetir uma vaga (ex: cidades diferentes) até maxSameCompanyDuplicates vezes.
+const (
+	duplicateWindow          = 30 * 24 * time.Hour
+	maxSameCompanyDuplicates = 2
+)
+
+// validJobExpiry confere a validade informada pela empresa: no futuro e até jobs.MaxJobDuration
+func validJobExpiry(expiresAt time.Time) bool {
+	now := time.Now()
+	return expiresAt.After(now) && !expiresAt.After(now.Add(jobs.MaxJobDuration))
+}
+
+type UpdateHiringTeamRequest struct {
+	MemberIDs []string `json:"member_ids"`
+}
+
+// moderateJob aplica as regras de conteúdo e define o status de moderação da vaga.
+// Vagas com algum motivo, de empresa não verificada ou já rejeitadas antes vão para a fila de revisão.
+// Na edição (existing preenchido), a retenção por denúncias continua até um admin fazer a triagem.
+func (h *CompanyJobsHandler) moderateJob(ctx context.Context, job *jobs.Job, company *companies.Company, existing *jobs.Job) {
+	job.ContentHash = jobs.ContentHash(job)
+	flags := jobs.CheckContent(job)
+
+	previousStatus := ""
+	if existing != nil {
+		previousStatus = existing.ModerationStatus
+		if previousStatus == jobs.ModerationPendingReview {
+			for _, flag := range existing.ModerationFlags {
+				if flag.Rule == jobs.RuleReported {
+					flags = append(flags, flag)
+				}
+			}
+		}
+	}
+
+	same, others, err := h.jobRepo.CountDuplicates(ctx, job.ContentHash, job.CompanyID, job.ID, time.Now().Add(-duplicateWindow))
+	if err != nil {
+		log.Printf("Erro ao verificar vagas duplicadas (empresa %s): %v", job.CompanyID.Hex(), err)
+	} else if others > 0 || same >= maxSameCompanyDuplicates {
+		flags = append(flags, jobs.ModerationFlag{Rule: jobs.RuleDuplicate, Detail: "Conteúdo idêntico a outras vagas publicadas recentemente"})
+	}
+
+	if company.VerificationStatus != "verified" {
+		flags = append(flags, jobs.ModerationFlag{Rule: jobs.RuleCompanyUnverified, Detail: "Empresa ainda não verificada"})
+	}
+
+	job.ModerationFlags = flags
+	if len(flags) > 0 || previousStatus == jobs.ModerationRejected {
+		job.ModerationStatus = jobs.ModerationPendingReview
+	} else {
+		job.ModerationStatus = jobs.ModerationApproved
+	}
+}
+
+// jobOpen indica se a vaga está publicada recebendo candidaturas
+func jobOpen(job *jobs.Job) bool {
+	return job.IsActive && job.IsPublic()
+}
+
+// notifyJobVisibility envia job.published ou job.closed aos webhooks da empresa quando a vaga
+// entra ou sai do ar (wasOpen: estado antes da alteração)
+func (h *CompanyJobsHandler) notifyJobVisibility(ctx context.Context, job *jobs.Job, wasOpen bool, closeReason string) {
+	if open := jobOpen(job); open && !wasOpen {
+		h.webhooks.JobPublished(ctx, job)
+	} else if !open && wasOpen {
+		h.webhooks.JobClosed(ctx, job, closeReason)
+	}
+}
+
+// canAccessJobApplicants indica se o usuário da empresa pode ver e movimentar os candidatos da vaga:
+// owner/admin sempre; demais papéis só se estiverem na equipe de contratação
+func canAccessJobApplicants(r *http.Request, job *jobs.Job) bool {
+	if middleware.CompanyRole(r).Can(companies.PermAccessAllJobs) {
+		return true
+	}
+	memberID := middleware.CompanyMemberID(r)
+	return memberID != "" && job.HasTeamMember(memberID)
+}
+
 func (h *CompanyJobsHandler) Create(w http.ResponseWriter, r *http.Request) {
 	companyID := r.Context().Value(middleware.UserIDKey).(string)
 
@@ -47,6 +133,20 @@ func (h *CompanyJobsHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Sem expires_at a vaga vale por jobs.DefaultJobDuration
+	if job.ExpiresAt == nil {
+		expiresAt := time.Now().Add(jobs.DefaultJobDuration)
+		job.ExpiresAt = &expiresAt
+	} else if !validJobExpiry(*job.ExpiresAt) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "expires_at deve ser uma data futura de no máximo 180 dias",
+		})
+		return
+	}
+	job.ExpiryWarnedAt = nil
+
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
@@ -65,7 +165,22 @@ func (h *CompanyJobsHandler) Create(w http.ResponseWriter, r *http.Request) {
 	companyObjID, _ := bson.ObjectIDFromHex(companyID)
 	job.CompanyID = companyObjID
 	job.Company = company.Name
+	job.CompanySector = company.Sector
 	job.IsActive = true
+	job.CompanyVerified = company.VerificationStatus == "verified"
+	job.TakenDown, job.TakedownReason, job.TakenDownAt = false, "", nil
+	job.ModerationReason, job.ModeratedAt = "", nil
+
+	// Quem cria a vaga entra na equipe de contratação (o dono não é membro e vê tudo)
+	job.HiringTeam = nil
+	if memberID := middleware.CompanyMemberID(r); memberID != "" {
+		if memberObjID, err := bson.ObjectIDFromHex(memberID); err == nil {
+			job.HiringTeam = []bson.ObjectID{memberObjID}
+		}
+	}
+
+	// Conteúdo suspeito ou empresa não verificada: a vaga só aparece após revisão
+	h.moderateJob(ctx, &job, company, nil)
 
 	if err := h.jobRepo.Create(ctx, &job); err != nil {
 		w.Header().Set("Content-Type", "application/json")
@@ -76,11 +191,18 @@ func (h *CompanyJobsHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.notifyJobVisibility(ctx, &job, false, "")
+
+	message := "Vaga criada com sucesso"
+	if job.ModerationStatus == jobs.ModerationPendingReview {
+		message = "Vaga criada e enviada para revisão. Ela será publicada após a aprovação."
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(map[string]interface{}{
-		"mensagem": "Vaga criada com sucesso",
-		"vaga":     job,
+		"mensagem": message,
+		"vaga":     job.Managed(),
 	})
 }
 
@@ -91,7 +213,16 @@ func (h *CompanyJobsHandler) ListMine(w http.ResponseWriter, r *http.Request) {
 	defer cancel()
 
 	companyObjID, _ := bson.ObjectIDFromHex(companyID)
-	jobs, err := h.jobRepo.GetByCompanyID(ctx, companyObjID)
+
+	// ?assigned=me: apenas vagas em que o membro está na equipe de contratação
+	var companyJobs []*jobs.Job
+	var err error
+	if memberID := middleware.CompanyMemberID(r); r.URL.Query().Get("assigned") == "me" && memberID != "" {
+		memberObjID, _ := bson.ObjectIDFromHex(memberID)
+		companyJobs, err = h.jobRepo.GetByHiringTeamMember(ctx, companyObjID, memberObjID)
+	} else {
+		companyJobs, err = h.jobRepo.GetByCompanyID(ctx, companyObjID)
+	}
 	if err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusInternalServerError)
@@ -104,7 +235,7 @@ func (h *CompanyJobsHandler) ListMine(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(map[string]interface{}{
-		"vagas": jobs,
+		"vagas": jobs.ManagedList(companyJobs),
 	})
 }
 
@@ -160,6 +291,47 @@ func (h *CompanyJobsHandler) Update(w http.ResponseWriter, r *http.Request) {
 	job.CompanyID = existingJob.CompanyID
 	job.Company = existingJob.Company
 	job.CreatedAt = existingJob.CreatedAt
+	job.HiringTeam = existingJob.HiringTeam // alterada apenas por PUT /company/jobs/{id}/team
+	job.CompanyVerified = existingJob.CompanyVerified
+	// Moderação: alterados apenas por administradores
+	job.TakenDown = existingJob.TakenDown
+	job.TakedownReason = existingJob.TakedownReason
+	job.TakenDownAt = existingJob.TakenDownAt
+	if job.TakenDown {
+		job.IsActive = false
+	}
+	job.ModerationReason = existingJob.ModerationReason
+	job.ModeratedAt = existingJob.ModeratedAt
+
+	// Sem expires_at a validade atual é mantida; uma nova validade libera um novo aviso de expiração
+	job.ExpiryWarnedAt = existingJob.ExpiryWarnedAt
+	// O MongoDB guarda datas com precisão de milissegundos
+	expiryChanged := job.ExpiresAt != nil && (existingJob.ExpiresAt == nil || !job.ExpiresAt.Truncate(time.Millisecond).Equal(*existingJob.ExpiresAt))
+	if job.ExpiresAt == nil {
+		job.ExpiresAt = existingJob.ExpiresAt
+	} else if expiryChanged && !validJobExpiry(*job.ExpiresAt) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "expires_at deve ser uma data futura de no máximo 180 dias",
+		})
+		return
+	}
+
+	company, err := h.companyRepo.GetByID(ctx, companyID)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao buscar dados da empresa",
+		})
+		return
+	}
+
+	job.CompanySector = company.Sector
+
+	// Toda edição passa de novo pelas regras de moderação
+	h.moderateJob(ctx, &job, company, existingJob)
 
 	if err := h.jobRepo.Update(ctx, &job); err != nil {
 		w.Header().Set("Content-Type", "application/json")
@@ -170,11 +342,30 @@ func (h *CompanyJobsHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if expiryChanged {
+		if err := h.jobRepo.SetExpiry(ctx, job.ID, *job.ExpiresAt); err != nil {
+			log.Printf("Erro ao renovar validade da vaga %s: %v", job.ID.Hex(), err)
+		}
+		job.ExpiryWarnedAt = nil
+	}
+
+	// Desativada na edição ou retida para revisão: sai do ar
+	closeReason := webhooks.CloseReasonUnderReview
+	if !job.IsActive {
+		closeReason = webhooks.CloseReasonDeactivated
+	}
+	h.notifyJobVisibility(ctx, &job, jobOpen(existingJob), closeReason)
+
+	message := "Vaga atualizada com sucesso"
+	if job.ModerationStatus == jobs.ModerationPendingReview {
+		message = "Vaga atualizada e enviada para revisão. Ela volta a aparecer após a aprovação."
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(map[string]interface{}{
-		"mensagem": "Vaga atualizada com sucesso",
-		"vaga":     job,
+		"mensagem": message,
+		"vaga":     job.Managed(),
 	})
 }
 
@@ -223,6 +414,10 @@ func (h *CompanyJobsHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if jobOpen(job) {
+		h.webhooks.JobClosed(ctx, job, webhooks.CloseReasonDeleted)
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(map[string]string{
@@ -261,6 +456,7 @@ func (h *CompanyJobsHandler) Deactivate(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	wasOpen := jobOpen(job)
 	job.IsActive = false
 	if err := h.jobRepo.Update(ctx, job); err != nil {
 		w.Header().Set("Content-Type", "application/json")
@@ -271,11 +467,13 @@ func (h *CompanyJobsHandler) Deactivate(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	h.notifyJobVisibility(ctx, job, wasOpen, webhooks.CloseReasonDeactivated)
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"mensagem": "Vaga desativada com sucesso",
-		"vaga":     job,
+		"vaga":     job.Managed(),
 	})
 }
 
@@ -310,6 +508,23 @@ func (h *CompanyJobsHandler) Activate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if job.TakenDown {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Vaga removida pela moderação. Entre em contato com o suporte.",
+		})
+		return
+	}
+
+	// Vaga vencida (ou sem validade) volta ao ar com uma validade nova
+	renewed := job.ExpiresAt == nil || !job.ExpiresAt.After(time.Now())
+	if renewed {
+		expiresAt := time.Now().Add(jobs.DefaultJobDuration)
+		job.ExpiresAt = &expiresAt
+	}
+
+	wasOpen := jobOpen(job)
 	job.IsActive = true
 	if err := h.jobRepo.Update(ctx, job); err != nil {
 		w.Header().Set("Content-Type", "application/json")
@@ -320,10 +535,100 @@ func (h *CompanyJobsHandler) Activate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if renewed {
+		if err := h.jobRepo.SetExpiry(ctx, job.ID, *job.ExpiresAt); err != nil {
+			log.Printf("Erro ao renovar validade da vaga %s: %v", job.ID.Hex(), err)
+		}
+		job.ExpiryWarnedAt = nil
+	}
+
+	h.notifyJobVisibility(ctx, job, wasOpen, "")
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"mensagem": "Vaga ativada com sucesso",
-		"vaga":     job,
+		"vaga":     job.Managed(),
+	})
+}
+
+// UpdateHiringTeam define os membros da empresa responsáveis pela vaga
+func (h *CompanyJobsHandler) UpdateHiringTeam(w http.ResponseWriter, r *http.Request) {
+	companyID := r.Context().Value(middleware.UserIDKey).(string)
+	jobID := r.URL.Path[len("/company/jobs/"):]
+
+	// Remove "/team" do final se existir
+	if len(jobID) > 5 && jobID[len(jobID)-5:] == "/team" {
+		jobID = jobID[:len(jobID)-5]
+	}
+
+	var req UpdateHiringTeamRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Dados inválidos",
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	job, err := h.jobRepo.GetByID(ctx, jobID)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Vaga não encontrada",
+		})
+		return
+	}
+
+	if job.CompanyID.Hex() != companyID {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Você não tem permissão para alterar esta vaga",
+		})
+		return
+	}
+
+	// Todos os IDs precisam ser membros desta empresa
+	team := []bson.ObjectID{}
+	seen := map[string]bool{}
+	for _, memberID := range req.MemberIDs {
+		if seen[memberID] {
+			continue
+		}
+		seen[memberID] = true
+
+		member, err := h.memberRepo.GetByID(ctx, memberID)
+		if err != nil || member.CompanyID.Hex() != companyID {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "Membro não encontrado na equipe da empresa: " + memberID,
+			})
+			return
+		}
+		team = append(team, member.ID)
+	}
+
+	if err := h.jobRepo.SetHiringTeam(ctx, job.ID, team); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao atualizar equipe da vaga",
+		})
+		return
+	}
+	job.HiringTeam = team
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"mensagem": "Equipe da vaga atualizada com sucesso",
+		"vaga":     job.Managed(),
 	})
 }
@@ -0,0 +1,234 @@
+package handlers
+
+import (
+	"context"
+	"empregabemapi/companies"
+	"empregabemapi/internal/media"
+	"empregabemapi/internal/middleware"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Mensagens por tipo de imagem (logo é masculino, capa é feminino)
+var companyMediaMessages = map[string]struct {
+	updated, removed, missing string
+}{
+	"logo":  {"Logo atualizado com sucesso", "Logo removido com sucesso", "Nenhum logo enviado"},
+	"cover": {"Capa atualizada com sucesso", "Capa removida com sucesso", "Nenhuma capa enviada"},
+}
+
+type CompanyMediaHandler struct {
+	companyRepo *companies.MongoRepository
+	library     *media.Library
+}
+
+func NewCompanyMediaHandler(companyRepo *companies.MongoRepository, library *media.Library) *CompanyMediaHandler {
+	return &CompanyMediaHandler{
+		companyRepo: companyRepo,
+		library:     library,
+	}
+}
+
+// Upload recebe a imagem (multipart/form-data, campo "file"), gera as variantes da spec
+// e substitui a imagem anterior (POST /company/me/logo e /company/me/cover)
+func (h *CompanyMediaHandler) Upload(spec media.Spec) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		companyID := r.Context().Value(middleware.UserIDKey).(string)
+
+		data, status, msg := readUploadedFile(w, r)
+		if status != 0 {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": msg,
+			})
+			return
+		}
+
+		result, err := media.Process(data, spec)
+		if err != nil {
+			var validationErr *media.ValidationError
+			if errors.As(err, &validationErr) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusBadRequest)
+				json.NewEncoder(w).Encode(map[string]string{
+					"erro": validationErr.Message,
+				})
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "Erro ao processar imagem",
+			})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		company, err := h.companyRepo.GetByID(ctx, companyID)
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "Empresa não encontrada",
+			})
+			return
+		}
+
+		// Cada upload ganha um prefixo novo: URLs antigas em cache nunca mostram a imagem nova
+		prefix := fmt.Sprintf("companies/%s/%s/%s", company.ID.Hex(), spec.Kind, bson.NewObjectID().Hex())
+		files, urls, err := h.library.Save(ctx, prefix, result)
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "Erro ao salvar imagem",
+			})
+			return
+		}
+
+		image := &companies.Image{
+			Variants:   urls,
+			Width:      result.Width,
+			Height:     result.Height,
+			Files:      files,
+			UploadedAt: time.Now(),
+		}
+
+		previous := company.CoverImage
+		if spec.Kind == media.LogoSpec.Kind {
+			previous = company.LogoImage
+			err = h.companyRepo.SetLogo(ctx, company.ID, image, urls[spec.LargestSize()])
+		} else {
+			err = h.companyRepo.SetCover(ctx, company.ID, image)
+		}
+		if err != nil {
+			h.library.Delete(ctx, files)
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "Erro ao atualizar perfil",
+			})
+			return
+		}
+
+		if previous != nil {
+			h.library.Delete(ctx, previous.Files)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"mensagem": companyMediaMessages[spec.Kind].updated,
+			"imagem":   image,
+		})
+	}
+}
+
+// Remove apaga a imagem enviada (DELETE /company/me/logo e /company/me/cover)
+func (h *CompanyMediaHandler) Remove(spec media.Spec) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		companyID := r.Context().Value(middleware.UserIDKey).(string)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		company, err := h.companyRepo.GetByID(ctx, companyID)
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "Empresa não encontrada",
+			})
+			return
+		}
+
+		current := company.CoverImage
+		if spec.Kind == media.LogoSpec.Kind {
+			current = company.LogoImage
+		}
+		if current == nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": companyMediaMessages[spec.Kind].missing,
+			})
+			return
+		}
+
+		if spec.Kind == media.LogoSpec.Kind {
+			err = h.companyRepo.SetLogo(ctx, company.ID, nil, "")
+		} else {
+			err = h.companyRepo.SetCover(ctx, company.ID, nil)
+		}
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "Erro ao atualizar perfil",
+			})
+			return
+		}
+
+		h.library.Delete(ctx, current.Files)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]string{
+			"mensagem": companyMediaMessages[spec.Kind].removed,
+		})
+	}
+}
+
+// readUploadedFile lê o campo "file" do multipart sem gravar em disco, respeitando media.MaxUploadSize.
+// Em caso de erro retorna o status HTTP e a mensagem para o cliente.
+func readUploadedFile(w http.ResponseWriter, r *http.Request) ([]byte, int, string) {
+	// Folga para os cabeçalhos do multipart
+	r.Body = http.MaxBytesReader(w, r.Body, media.MaxUploadSize+64<<10)
+	tooLarge := fmt.Sprintf("Arquivo maior que %dMB", media.MaxUploadSize>>20)
+
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, http.StatusBadRequest, "Envie a imagem como multipart/form-data no campo file"
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return nil, http.StatusBadRequest, "Envie a imagem como multipart/form-data no campo file"
+		}
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				return nil, http.StatusRequestEntityTooLarge, tooLarge
+			}
+			return nil, http.StatusBadRequest, "Dados inválidos"
+		}
+		if part.FormName() != "file" {
+			part.Close()
+			continue
+		}
+
+		data, err := io.ReadAll(io.LimitReader(part, media.MaxUploadSize+1))
+		part.Close()
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				return nil, http.StatusRequestEntityTooLarge, tooLarge
+			}
+			return nil, http.StatusBadRequest, "Dados inválidos"
+		}
+		if len(data) > media.MaxUploadSize {
+			return nil, http.StatusRequestEntityTooLarge, tooLarge
+		}
+		return data, 0, ""
+	}
+}
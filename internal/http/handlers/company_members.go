@@ -0,0 +1,574 @@
+package handlers
+
+import (
+	"context"
+	"empregabemapi/candidates"
+	"empregabemapi/companies"
+	"empregabemapi/internal/auth"
+	"empregabemapi/internal/mail"
+	"empregabemapi/internal/middleware"
+	"empregabemapi/jobs"
+	"encoding/json"
+	"log"
+	"net/http"
+	netmail "net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// Validade do convite para a equipe
+const invitationTTL = 7 * 24 * time.Hour
+
+var companyRoleLabels = map[companies.Role]string{
+	companies.RoleOwner:     "Proprietário",
+	companies.RoleAdmin:     "Administrador",
+	companies.RoleRecruiter: "Recrutador",
+	companies.RoleViewer:    "Visualizador",
+}
+
+// CompanyMembersHandler gerencia a equipe (membros e convites) de uma empresa
+type CompanyMembersHandler struct {
+	companyRepo   *companies.MongoRepository
+	memberRepo    *companies.MemberRepository
+	candidateRepo *candidates.MongoRepository
+	jobRepo       *jobs.MongoRepository
+	mailer        *mail.Mailer
+}
+
+func NewCompanyMembersHandler(
+	companyRepo *companies.MongoRepository,
+	memberRepo *companies.MemberRepository,
+	candidateRepo *candidates.MongoRepository,
+	jobRepo *jobs.MongoRepository,
+	mailer *mail.Mailer,
+) *CompanyMembersHandler {
+	return &CompanyMembersHandler{
+		companyRepo:   companyRepo,
+		memberRepo:    memberRepo,
+		candidateRepo: candidateRepo,
+		jobRepo:       jobRepo,
+		mailer:        mailer,
+	}
+}
+
+type InviteMemberRequest struct {
+	Email string         `json:"email"`
+	Role  companies.Role `json:"role"`
+}
+
+type UpdateMemberRoleRequest struct {
+	Role companies.Role `json:"role"`
+}
+
+type AcceptInvitationRequest struct {
+	Token    string `json:"token"`
+	Name     string `json:"name"`
+	Password string `json:"password"`
+}
+
+// assignableRole indica se o papel pode ser dado a um membro (o dono é sempre a conta da empresa)
+func assignableRole(role companies.Role) bool {
+	return role.Valid() && role != companies.RoleOwner
+}
+
+// emailInUse verifica se o email já pertence a alguma empresa, membro ou candidato
+func (h *CompanyMembersHandler) emailInUse(ctx context.Context, email string) (bool, error) {
+	if _, err := h.companyRepo.GetByEmail(ctx, email); err == nil {
+		return true, nil
+	} else if err != mongo.ErrNoDocuments {
+		return false, err
+	}
+
+	if _, err := h.memberRepo.GetByEmail(ctx, email); err == nil {
+		return true, nil
+	} else if err != mongo.ErrNoDocuments {
+		return false, err
+	}
+
+	if _, err := h.candidateRepo.GetByEmail(ctx, email); err == nil {
+		return true, nil
+	} else if err != mongo.ErrNoDocuments {
+		return false, err
+	}
+
+	return false, nil
+}
+
+// List retorna os membros da equipe e os convites pendentes
+func (h *CompanyMembersHandler) List(w http.ResponseWriter, r *http.Request) {
+	companyID := r.Context().Value(middleware.UserIDKey).(string)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	members, err := h.memberRepo.ListByCompany(ctx, companyID)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao buscar membros",
+		})
+		return
+	}
+
+	response := map[string]interface{}{
+		"membros": members,
+	}
+
+	// Convites só interessam a quem pode gerenciar a equipe
+	if middleware.CompanyRole(r).Can(companies.PermManageMembers) {
+		invitations, err := h.memberRepo.ListPendingInvitations(ctx, companyID)
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "Erro ao buscar convites",
+			})
+			return
+		}
+		response["convites"] = invitations
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// Invite envia um convite por email para entrar na equipe
+func (h *CompanyMembersHandler) Invite(w http.ResponseWriter, r *http.Request) {
+	companyID := r.Context().Value(middleware.UserIDKey).(string)
+
+	var req InviteMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Dados inválidos",
+		})
+		return
+	}
+
+	req.Email = auth.SanitizeEmail(req.Email)
+	if addr, err := netmail.ParseAddress(req.Email); err != nil || addr.Address != req.Email {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Email inválido",
+		})
+		return
+	}
+
+	if !assignableRole(req.Role) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Papel inválido. Use: admin, recruiter ou viewer",
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	company, err := h.companyRepo.GetByID(ctx, companyID)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Empresa não encontrada",
+		})
+		return
+	}
+
+	inUse, err := h.emailInUse(ctx, req.Email)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao verificar email",
+		})
+		return
+	}
+	if inUse {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Email já cadastrado na plataforma",
+		})
+		return
+	}
+
+	token, tokenHash, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao gerar convite",
+		})
+		return
+	}
+
+	// Quem convidou: o dono (conta da empresa) ou um membro
+	inviterName := company.Name
+	invitedBy := companyID
+	if memberID := middleware.CompanyMemberID(r); memberID != "" {
+		invitedBy = memberID
+		if inviter, err := h.memberRepo.GetByID(ctx, memberID); err == nil {
+			inviterName = inviter.Name
+		}
+	}
+
+	invitation := &companies.Invitation{
+		CompanyID: company.ID,
+		Email:     req.Email,
+		Role:      req.Role,
+		TokenHash: tokenHash,
+		InvitedBy: invitedBy,
+		ExpiresAt: time.Now().Add(invitationTTL),
+	}
+	if err := h.memberRepo.CreateInvitation(ctx, invitation); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao criar convite",
+		})
+		return
+	}
+
+	err = h.mailer.Enqueue(ctx, req.Email, mail.TemplateTeamInvitation, map[string]interface{}{
+		"CompanyName":   company.Name,
+		"InviterName":   inviterName,
+		"RoleLabel":     companyRoleLabels[req.Role],
+		"AcceptURL":     h.mailer.URL("/company/invitations/accept?token=" + url.QueryEscape(token)),
+		"ExpiresInDays": int(invitationTTL.Hours() / 24),
+	})
+	if err != nil {
+		log.Printf("Erro ao enfileirar convite de equipe: %v", err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"mensagem": "Convite enviado com sucesso",
+		"convite":  invitation,
+	})
+}
+
+// RevokeInvitation cancela um convite pendente
+func (h *CompanyMembersHandler) RevokeInvitation(w http.ResponseWriter, r *http.Request) {
+	companyID := r.Context().Value(middleware.UserIDKey).(string)
+
+	// Extrair ID do convite da URL: /company/members/invitations/{id}
+	invitationID, err := bson.ObjectIDFromHex(strings.TrimPrefix(r.URL.Path, "/company/members/invitations/"))
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "ID inválido",
+		})
+		return
+	}
+
+	companyObjectID, err := bson.ObjectIDFromHex(companyID)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "ID inválido",
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := h.memberRepo.MarkInvitationUsed(ctx, invitationID, companyObjectID); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		if err == companies.ErrInvitationUsed {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "Convite não encontrado",
+			})
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao revogar convite",
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{
+		"mensagem": "Convite revogado com sucesso",
+	})
+}
+
+// loadCompanyMember busca o membro da URL /company/members/{id} garantindo que pertence à empresa
+func (h *CompanyMembersHandler) loadCompanyMember(ctx context.Context, w http.ResponseWriter, r *http.Request) (*companies.Member, bool) {
+	companyID := r.Context().Value(middleware.UserIDKey).(string)
+	memberID := strings.TrimPrefix(r.URL.Path, "/company/members/")
+
+	if memberID == middleware.CompanyMemberID(r) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Você não pode alterar o seu próprio acesso",
+		})
+		return nil, false
+	}
+
+	member, err := h.memberRepo.GetByID(ctx, memberID)
+	if err != nil || member.CompanyID.Hex() != companyID {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Membro não encontrado",
+		})
+		return nil, false
+	}
+
+	return member, true
+}
+
+// UpdateRole altera o papel de um membro. As sessões dele são encerradas
+// (o token carrega o papel antigo).
+func (h *CompanyMembersHandler) UpdateRole(w http.ResponseWriter, r *http.Request) {
+	var req UpdateMemberRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Dados inválidos",
+		})
+		return
+	}
+
+	if !assignableRole(req.Role) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Papel inválido. Use: admin, recruiter ou viewer",
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	member, ok := h.loadCompanyMember(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	member.Role = req.Role
+	if err := h.memberRepo.Update(ctx, member); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao atualizar membro",
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"mensagem": "Papel atualizado com sucesso",
+		"membro":   member,
+	})
+}
+
+// Remove tira um membro da equipe (as sessões dele deixam de valer)
+func (h *CompanyMembersHandler) Remove(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	member, ok := h.loadCompanyMember(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.memberRepo.Delete(ctx, member.ID); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao remover membro",
+		})
+		return
+	}
+
+	if err := h.jobRepo.RemoveFromHiringTeams(ctx, member.ID); err != nil {
+		log.Printf("Erro ao remover membro %s das equipes de vagas: %v", member.ID.Hex(), err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{
+		"mensagem": "Membro removido com sucesso",
+	})
+}
+
+// AcceptInvitation cria o acesso do convidado a partir do link enviado por email
+func (h *CompanyMembersHandler) AcceptInvitation(w http.ResponseWriter, r *http.Request) {
+	var req AcceptInvitationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Dados inválidos",
+		})
+		return
+	}
+
+	if req.Token == "" || req.Name == "" || req.Password == "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Campos obrigatórios: token, name, password",
+		})
+		return
+	}
+
+	if err := auth.ValidateOpaqueToken(req.Token); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Convite inválido ou expirado",
+		})
+		return
+	}
+
+	if err := auth.ValidatePasswordStrength(req.Password); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": err.Error(),
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	invitation, err := h.memberRepo.GetInvitationByToken(ctx, auth.HashToken(req.Token))
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		if err == mongo.ErrNoDocuments {
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "Convite inválido, expirado ou já utilizado",
+			})
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao verificar convite",
+		})
+		return
+	}
+
+	// O email pode ter sido cadastrado por outra conta desde o convite
+	inUse, err := h.emailInUse(ctx, invitation.Email)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao verificar email",
+		})
+		return
+	}
+	if inUse {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Email já cadastrado na plataforma",
+		})
+		return
+	}
+
+	company, err := h.companyRepo.GetByID(ctx, invitation.CompanyID.Hex())
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Empresa não encontrada",
+		})
+		return
+	}
+
+	// Consome o convite antes de criar o membro (uso único mesmo com requisições simultâneas)
+	if err := h.memberRepo.MarkInvitationUsed(ctx, invitation.ID, bson.ObjectID{}); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		if err == companies.ErrInvitationUsed {
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "Convite inválido, expirado ou já utilizado",
+			})
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao verificar convite",
+		})
+		return
+	}
+
+	hashedPassword, err := auth.HashPassword(req.Password)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao processar senha",
+		})
+		return
+	}
+
+	member := &companies.Member{
+		CompanyID: invitation.CompanyID,
+		Name:      strings.TrimSpace(req.Name),
+		Email:     invitation.Email,
+		Password:  hashedPassword,
+		Role:      invitation.Role,
+		InvitedBy: invitation.InvitedBy,
+	}
+	if err := h.memberRepo.Create(ctx, member); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		if mongo.IsDuplicateKeyError(err) {
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "Email já cadastrado na plataforma",
+			})
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao criar acesso",
+		})
+		return
+	}
+
+	token, err := auth.GenerateMemberToken(company.ID.Hex(), member.ID.Hex(), string(member.Role))
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao gerar token",
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"mensagem": "Convite aceito com sucesso",
+		"token":    token,
+		"empresa":  company,
+		"membro":   member,
+	})
+}
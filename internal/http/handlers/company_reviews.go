@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"context"
+	"empregabemapi/applications"
+	"empregabemapi/companies"
+	"empregabemapi/internal/middleware"
+	"empregabemapi/internal/models"
+	"empregabemapi/internal/repository"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// Avaliações só são publicadas (médias e textos) quando a empresa tem pelo menos este número
+// de avaliações aprovadas, para que a empresa não identifique quem avaliou
+const minPublishedReviews = 3
+
+// Candidatura sem resposta da empresa dá direito a avaliar depois deste prazo
+const reviewEligibilityWait = 14 * 24 * time.Hour
+
+const (
+	minReviewCommentLength = 20
+	maxReviewCommentLength = 3000
+	maxReviewTitleLength   = 120
+)
+
+type CompanyReviewsHandler struct {
+	reviewRepo  *repository.CompanyReviewsRepository
+	companyRepo *companies.MongoRepository
+	appsRepo    *applications.MongoRepository
+}
+
+func NewCompanyReviewsHandler(reviewRepo *repository.CompanyReviewsRepository, companyRepo *companies.MongoRepository, appsRepo *applications.MongoRepository) *CompanyReviewsHandler {
+	return &CompanyReviewsHandler{
+		reviewRepo:  reviewRepo,
+		companyRepo: companyRepo,
+		appsRepo:    appsRepo,
+	}
+}
+
+type CreateReviewRequest struct {
+	Rating              int    `json:"rating"`
+	Communication       int    `json:"communication"`
+	ResponseTime        int    `json:"response_time"`
+	InterviewDifficulty int    `json:"interview_difficulty"`
+	Title               string `json:"title"`
+	Comment             string `json:"comment"`
+}
+
+// publishedReviewSummary retorna as médias das avaliações aprovadas, ou nil enquanto a empresa
+// não tiver avaliações suficientes para publicar
+func publishedReviewSummary(ctx context.Context, reviewRepo *repository.CompanyReviewsRepository, companyID bson.ObjectID) (*models.CompanyReviewSummary, error) {
+	summary, err := reviewRepo.Summarize(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	if summary.Total < minPublishedReviews {
+		return nil, nil
+	}
+	return summary, nil
+}
+
+// Create registra a avaliação do processo seletivo (POST /companies/{id-ou-slug}/reviews).
+// Só candidatos com candidatura na empresa podem avaliar; a avaliação entra na fila de moderação.
+func (h *CompanyReviewsHandler) Create(w http.ResponseWriter, r *http.Request) {
+	candidateID := r.Context().Value(middleware.UserIDKey).(string)
+
+	ref := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/companies/"), "/reviews")
+
+	var req CreateReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Dados inválidos",
+		})
+		return
+	}
+
+	for _, score := range []int{req.Rating, req.Communication, req.ResponseTime, req.InterviewDifficulty} {
+		if score < 1 || score > 5 {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "As notas rating, communication, response_time e interview_difficulty devem ir de 1 a 5",
+			})
+			return
+		}
+	}
+
+	req.Title = strings.TrimSpace(req.Title)
+	req.Comment = strings.TrimSpace(req.Comment)
+	if n := utf8.RuneCountInString(req.Comment); n < minReviewCommentLength || n > maxReviewCommentLength {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": fmt.Sprintf("Comentário deve ter entre %d e %d caracteres", minReviewCommentLength, maxReviewCommentLength),
+		})
+		return
+	}
+	if utf8.RuneCountInString(req.Title) > maxReviewTitleLength {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": fmt.Sprintf("Título deve ter no máximo %d caracteres", maxReviewTitleLength),
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	company, err := findPublicCompany(ctx, h.companyRepo, ref)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Empresa não encontrada",
+		})
+		return
+	}
+
+	// Elegibilidade conferida na coleção applications
+	candidateObjID, _ := bson.ObjectIDFromHex(candidateID)
+	application, err := h.appsRepo.FindReviewable(ctx, company.ID, candidateObjID, time.Now().Add(-reviewEligibilityWait))
+	if err == mongo.ErrNoDocuments {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Só é possível avaliar empresas em que você participou de um processo seletivo (candidaturas sem resposta podem ser avaliadas após 14 dias)",
+		})
+		return
+	}
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao verificar candidaturas",
+		})
+		return
+	}
+
+	review := &models.CompanyReview{
+		CompanyID:           company.ID,
+		CandidateID:         candidateObjID,
+		ApplicationID:       application.ID,
+		Rating:              req.Rating,
+		Communication:       req.Communication,
+		ResponseTime:        req.ResponseTime,
+		InterviewDifficulty: req.InterviewDifficulty,
+		Title:               req.Title,
+		Comment:             req.Comment,
+	}
+
+	if err := h.reviewRepo.Create(ctx, review); err != nil {
+		if err == repository.ErrAlreadyReviewed {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "Você já avaliou esta empresa",
+			})
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao registrar avaliação",
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"mensagem": "Avaliação enviada. Ela será publicada após a moderação, sem identificar você.",
+		"status":   review.Status,
+	})
+}
+
+// List retorna as médias e as avaliações aprovadas, anonimizadas (GET /companies/{id-ou-slug}/reviews)
+func (h *CompanyReviewsHandler) List(w http.ResponseWriter, r *http.Request) {
+	ref := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/companies/"), "/reviews")
+
+	limit := int64(20)
+	if l, err := strconv.ParseInt(r.URL.Query().Get("limit"), 10, 64); err == nil && l > 0 && l <= 100 {
+		limit = l
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	company, err := findPublicCompany(ctx, h.companyRepo, ref)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Empresa não encontrada",
+		})
+		return
+	}
+
+	summary, err := publishedReviewSummary(ctx, h.reviewRepo, company.ID)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao buscar avaliações",
+		})
+		return
+	}
+
+	reviews := []models.PublicReview{}
+	if summary != nil {
+		approved, err := h.reviewRepo.ListApprovedByCompany(ctx, company.ID, limit)
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "Erro ao buscar avaliações",
+			})
+			return
+		}
+		for _, review := range approved {
+			reviews = append(reviews, review.Public())
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"resumo":     summary,
+		"avaliacoes": reviews,
+	})
+}
@@ -0,0 +1,500 @@
+package handlers
+
+import (
+	"context"
+	"empregabemapi/internal/middleware"
+	"empregabemapi/internal/models"
+	"empregabemapi/internal/repository"
+	"empregabemapi/internal/webhooks"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+const (
+	// Limite de endpoints cadastrados por empresa
+	maxWebhookEndpoints = 10
+	// Após a rotação, o segredo anterior ainda assina as entregas por este período
+	webhookSecretGracePeriod = 24 * time.Hour
+)
+
+// CompanyWebhooksHandler gerencia os endpoints de webhook da empresa (integração com ATS)
+type CompanyWebhooksHandler struct {
+	repo       *repository.WebhooksRepository
+	dispatcher *webhooks.Dispatcher
+}
+
+func NewCompanyWebhooksHandler(repo *repository.WebhooksRepository, dispatcher *webhooks.Dispatcher) *CompanyWebhooksHandler {
+	return &CompanyWebhooksHandler{
+		repo:       repo,
+		dispatcher: dispatcher,
+	}
+}
+
+type WebhookEndpointRequest struct {
+	URL         string   `json:"url"`
+	Description string   `json:"description"`
+	Events      []string `json:"events"`
+	Active      *bool    `json:"active"` // padrão: true
+}
+
+// validate normaliza a requisição e retorna a mensagem de erro (vazia se válida)
+func (req *WebhookEndpointRequest) validate(dispatcher *webhooks.Dispatcher) string {
+	req.URL = strings.TrimSpace(req.URL)
+	req.Description = strings.TrimSpace(req.Description)
+
+	if req.URL == "" || len(req.Events) == 0 {
+		return "Campos obrigatórios: url, events"
+	}
+	if err := dispatcher.ValidateURL(req.URL); err != nil {
+		return err.Error()
+	}
+	if len(req.Description) > 200 {
+		return "A descrição deve ter no máximo 200 caracteres"
+	}
+
+	seen := make(map[string]bool)
+	events := make([]string, 0, len(req.Events))
+	for _, event := range req.Events {
+		if !models.ValidWebhookEvents[event] {
+			return "Evento inválido: " + event + ". Use: application.created, application.status_changed, job.published, job.closed"
+		}
+		if !seen[event] {
+			seen[event] = true
+			events = append(events, event)
+		}
+	}
+	req.Events = events
+	return ""
+}
+
+// webhookPathParts retorna os segmentos após /company/webhooks/
+// (ex: {id}, {id}/deliveries, {id}/deliveries/{deliveryID}/redeliver)
+func webhookPathParts(r *http.Request) []string {
+	return strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/company/webhooks/"), "/"), "/")
+}
+
+// loadEndpoint busca o endpoint do path garantindo que pertence à empresa do usuário
+func (h *CompanyWebhooksHandler) loadEndpoint(ctx context.Context, w http.ResponseWriter, r *http.Request) (*models.WebhookEndpoint, bool) {
+	companyID, _ := bson.ObjectIDFromHex(r.Context().Value(middleware.UserIDKey).(string))
+
+	endpointID, err := bson.ObjectIDFromHex(webhookPathParts(r)[0])
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Webhook não encontrado",
+		})
+		return nil, false
+	}
+
+	endpoint, err := h.repo.GetEndpoint(ctx, endpointID, companyID)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "Webhook não encontrado",
+			})
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "Erro ao buscar webhook",
+			})
+		}
+		return nil, false
+	}
+
+	return endpoint, true
+}
+
+// List retorna os endpoints da empresa (GET /company/webhooks)
+func (h *CompanyWebhooksHandler) List(w http.ResponseWriter, r *http.Request) {
+	companyID, _ := bson.ObjectIDFromHex(r.Context().Value(middleware.UserIDKey).(string))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	endpoints, err := h.repo.ListEndpoints(ctx, companyID)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao listar webhooks",
+		})
+		return
+	}
+	if endpoints == nil {
+		endpoints = []*models.WebhookEndpoint{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"webhooks": endpoints,
+	})
+}
+
+// Create cadastra um endpoint (POST /company/webhooks). O segredo de assinatura só é
+// exibido nesta resposta e na rotação.
+func (h *CompanyWebhooksHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req WebhookEndpointRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Dados inválidos",
+		})
+		return
+	}
+
+	if msg := req.validate(h.dispatcher); msg != "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": msg,
+		})
+		return
+	}
+
+	companyID, _ := bson.ObjectIDFromHex(r.Context().Value(middleware.UserIDKey).(string))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	count, err := h.repo.CountEndpoints(ctx, companyID)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao cadastrar webhook",
+		})
+		return
+	}
+	if count >= maxWebhookEndpoints {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Limite de " + strconv.Itoa(maxWebhookEndpoints) + " webhooks por empresa atingido",
+		})
+		return
+	}
+
+	secret, err := webhooks.GenerateSecret()
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao gerar segredo do webhook",
+		})
+		return
+	}
+
+	endpoint := &models.WebhookEndpoint{
+		CompanyID:   companyID,
+		URL:         req.URL,
+		Description: req.Description,
+		Events:      req.Events,
+		Active:      req.Active == nil || *req.Active,
+		Secret:      secret,
+		CreatedBy:   middleware.CompanyMemberID(r),
+	}
+	if err := h.repo.CreateEndpoint(ctx, endpoint); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao cadastrar webhook",
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"mensagem": "Webhook cadastrado. Guarde o segredo: ele não será exibido novamente",
+		"webhook":  endpoint,
+		"secret":   secret,
+	})
+}
+
+// Get retorna um endpoint (GET /company/webhooks/{id})
+func (h *CompanyWebhooksHandler) Get(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	endpoint, ok := h.loadEndpoint(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(endpoint)
+}
+
+// Update altera URL, descrição, eventos e status (PUT /company/webhooks/{id})
+func (h *CompanyWebhooksHandler) Update(w http.ResponseWriter, r *http.Request) {
+	var req WebhookEndpointRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Dados inválidos",
+		})
+		return
+	}
+
+	if msg := req.validate(h.dispatcher); msg != "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": msg,
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	endpoint, ok := h.loadEndpoint(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	endpoint.URL = req.URL
+	endpoint.Description = req.Description
+	endpoint.Events = req.Events
+	if req.Active != nil {
+		endpoint.Active = *req.Active
+	}
+
+	if err := h.repo.UpdateEndpoint(ctx, endpoint); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao atualizar webhook",
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"mensagem": "Webhook atualizado com sucesso",
+		"webhook":  endpoint,
+	})
+}
+
+// Delete remove o endpoint e o histórico de entregas (DELETE /company/webhooks/{id})
+func (h *CompanyWebhooksHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	endpoint, ok := h.loadEndpoint(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	if _, err := h.repo.DeleteEndpoint(ctx, endpoint.ID, endpoint.CompanyID); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao remover webhook",
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{
+		"mensagem": "Webhook removido com sucesso",
+	})
+}
+
+// RotateSecret gera um novo segredo (POST /company/webhooks/{id}/rotate-secret).
+// Durante a carência as entregas levam as duas assinaturas, para o receptor trocar o segredo sem perder eventos.
+func (h *CompanyWebhooksHandler) RotateSecret(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	endpoint, ok := h.loadEndpoint(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	secret, err := webhooks.GenerateSecret()
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao gerar segredo do webhook",
+		})
+		return
+	}
+
+	previousExpiresAt := time.Now().Add(webhookSecretGracePeriod)
+	if err := h.repo.RotateSecret(ctx, endpoint.ID, endpoint.CompanyID, secret, endpoint.Secret, previousExpiresAt); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao rotacionar segredo do webhook",
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"mensagem":                   "Segredo rotacionado. O anterior continua válido até previous_secret_expires_at",
+		"secret":                     secret,
+		"previous_secret_expires_at": previousExpiresAt,
+	})
+}
+
+// Test enfileira uma entrega "ping" para o endpoint (POST /company/webhooks/{id}/test)
+func (h *CompanyWebhooksHandler) Test(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	endpoint, ok := h.loadEndpoint(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	delivery, err := h.dispatcher.Ping(ctx, endpoint)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao enviar entrega de teste",
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"mensagem": "Entrega de teste enfileirada",
+		"entrega":  delivery,
+	})
+}
+
+// ListDeliveries retorna o histórico de entregas do endpoint
+// (GET /company/webhooks/{id}/deliveries?status=&limit=)
+func (h *CompanyWebhooksHandler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "", models.WebhookDeliveryPending, models.WebhookDeliveryDelivering, models.WebhookDeliveryDelivered, models.WebhookDeliveryFailed:
+	default:
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Status inválido. Use: pending, delivering, delivered ou failed",
+		})
+		return
+	}
+
+	limit := int64(50)
+	if l, err := strconv.ParseInt(r.URL.Query().Get("limit"), 10, 64); err == nil && l > 0 && l <= 100 {
+		limit = l
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	endpoint, ok := h.loadEndpoint(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	deliveries, err := h.repo.ListDeliveries(ctx, endpoint.ID, status, limit)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao listar entregas",
+		})
+		return
+	}
+	if deliveries == nil {
+		deliveries = []*models.WebhookDelivery{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"entregas": deliveries,
+	})
+}
+
+// Redeliver reenvia uma entrega concluída ou falha com o mesmo evento e payload
+// (POST /company/webhooks/{id}/deliveries/{deliveryID}/redeliver)
+func (h *CompanyWebhooksHandler) Redeliver(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	endpoint, ok := h.loadEndpoint(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	parts := webhookPathParts(r)
+	deliveryID, err := bson.ObjectIDFromHex(parts[2])
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Entrega não encontrada",
+		})
+		return
+	}
+
+	original, err := h.repo.GetDelivery(ctx, deliveryID, endpoint.ID, endpoint.CompanyID)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "Entrega não encontrada",
+			})
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "Erro ao buscar entrega",
+			})
+		}
+		return
+	}
+
+	if original.Status != models.WebhookDeliveryDelivered && original.Status != models.WebhookDeliveryFailed {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "A entrega ainda está em andamento",
+		})
+		return
+	}
+
+	delivery, err := h.dispatcher.Redeliver(ctx, original)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao reenviar entrega",
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"mensagem": "Reenvio enfileirado",
+		"entrega":  delivery,
+	})
+}
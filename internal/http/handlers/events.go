@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"context"
+	"empregabemapi/internal/events"
+	"empregabemapi/internal/notifications"
+	"empregabemapi/internal/repository"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	// Comentário enviado periodicamente para manter a conexão aberta em proxies e balanceadores
+	eventsHeartbeat = 25 * time.Second
+	// Conexões são encerradas após este tempo; o navegador reconecta sozinho (com Last-Event-ID)
+	// e o token é validado de novo, então sessões revogadas ou expiradas não continuam recebendo eventos
+	eventsMaxStream = 30 * time.Minute
+	// Intervalo de reconexão sugerido ao EventSource (ms)
+	eventsRetryMillis = 5000
+)
+
+type EventsHandler struct {
+	hub               *events.Hub
+	notificationsRepo *repository.NotificationsRepository
+}
+
+func NewEventsHandler(hub *events.Hub, notificationsRepo *repository.NotificationsRepository) *EventsHandler {
+	return &EventsHandler{
+		hub:               hub,
+		notificationsRepo: notificationsRepo,
+	}
+}
+
+// writeEvent escreve um evento no formato text/event-stream. Eventos sem id não mudam o
+// Last-Event-ID do cliente.
+func writeEvent(w http.ResponseWriter, id, eventType string, data []byte) error {
+	if id != "" {
+		if _, err := fmt.Fprintf(w, "id: %s\n", id); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", eventType, data)
+	return err
+}
+
+// Stream mantém a conexão SSE do usuário (GET /events): novas candidaturas para a empresa,
+// mudanças de status para o candidato e o total de notificações não lidas
+func (h *EventsHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	recipientType, recipientID, ok := notificationRecipient(r)
+	if !ok {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Eventos disponíveis apenas para candidatos e empresas",
+		})
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Streaming não suportado",
+		})
+		return
+	}
+
+	// O EventSource envia Last-Event-ID ao reconectar; a query permite retomar em uma conexão nova
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("last_event_id")
+	}
+
+	sub, replay, resync := h.hub.Subscribe(events.Key(recipientType, recipientID.Hex()), lastEventID)
+	defer sub.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	unread, err := h.notificationsRepo.CountUnread(ctx, recipientType, recipientID)
+	cancel()
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao contar notificações não lidas",
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no") // nginx não deve segurar os eventos em buffer
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprintf(w, "retry: %d\n\n", eventsRetryMillis)
+	if resync {
+		// Eventos perdidos não estão mais no buffer: o cliente deve recarregar os dados
+		writeEvent(w, "", "resync", []byte("{}"))
+	}
+	for _, msg := range replay {
+		writeEvent(w, msg.ID, msg.Type, msg.Data)
+	}
+	count, _ := json.Marshal(map[string]int64{"nao_lidas": unread})
+	if err := writeEvent(w, "", notifications.EventNotificationCount, count); err != nil {
+		return
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(eventsHeartbeat)
+	defer heartbeat.Stop()
+	deadline := time.NewTimer(eventsMaxStream)
+	defer deadline.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-deadline.C:
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+		case msg, ok := <-sub.C:
+			if !ok {
+				return // conexão lenta demais; o cliente reconecta e retoma pelo Last-Event-ID
+			}
+			if err := writeEvent(w, msg.ID, msg.Type, msg.Data); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
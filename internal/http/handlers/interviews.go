@@ -0,0 +1,881 @@
+package handlers
+
+import (
+	"context"
+	"empregabemapi/applications"
+	"empregabemapi/candidates"
+	"empregabemapi/internal/calendar"
+	"empregabemapi/internal/mail"
+	"empregabemapi/internal/middleware"
+	"empregabemapi/internal/models"
+	"empregabemapi/internal/notifications"
+	"empregabemapi/internal/repository"
+	"empregabemapi/internal/webhooks"
+	"empregabemapi/jobs"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"mime"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+const (
+	maxInterviewSlots    = 5
+	maxInterviewDuration = 8 * time.Hour
+	// Entrevistas só podem ser marcadas até este prazo à frente
+	maxInterviewAdvance      = 180 * 24 * time.Hour
+	defaultInterviewTimezone = "America/Sao_Paulo"
+)
+
+var interviewModeLabels = map[string]string{
+	models.InterviewModeVideo:    "Vídeo",
+	models.InterviewModeInPerson: "Presencial",
+	models.InterviewModePhone:    "Telefone",
+}
+
+var weekdayLabels = [...]string{"dom", "seg", "ter", "qua", "qui", "sex", "sáb"}
+
+// InterviewsHandler cuida do agendamento de entrevistas: a empresa propõe horários a partir da
+// candidatura, o candidato escolhe um deles e os dois baixam o convite .ics
+type InterviewsHandler struct {
+	repo          *repository.InterviewsRepository
+	appRepo       *applications.MongoRepository
+	jobRepo       *jobs.MongoRepository
+	candidateRepo *candidates.MongoRepository
+	mailer        *mail.Mailer
+	notifier      *notifications.Service
+	webhooks      *webhooks.Dispatcher
+}
+
+func NewInterviewsHandler(
+	repo *repository.InterviewsRepository,
+	appRepo *applications.MongoRepository,
+	jobRepo *jobs.MongoRepository,
+	candidateRepo *candidates.MongoRepository,
+	mailer *mail.Mailer,
+	notifier *notifications.Service,
+	webhookDispatcher *webhooks.Dispatcher,
+) *InterviewsHandler {
+	return &InterviewsHandler{
+		repo:          repo,
+		appRepo:       appRepo,
+		jobRepo:       jobRepo,
+		candidateRepo: candidateRepo,
+		mailer:        mailer,
+		notifier:      notifier,
+		webhooks:      webhookDispatcher,
+	}
+}
+
+type InterviewSlotRequest struct {
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+}
+
+type InterviewRequest struct {
+	Title    string                 `json:"title"`
+	Mode     string                 `json:"mode"` // padrão: video
+	Location string                 `json:"location"`
+	VideoURL string                 `json:"video_url"`
+	Notes    string                 `json:"notes"`
+	Timezone string                 `json:"timezone"` // padrão: America/Sao_Paulo
+	Slots    []InterviewSlotRequest `json:"slots"`
+	// Só na criação: move a candidatura para a etapa "interview"
+	MoveToInterviewStage bool `json:"move_to_interview_stage"`
+}
+
+// validate normaliza a requisição e retorna a mensagem de erro (vazia se válida)
+func (req *InterviewRequest) validate() string {
+	req.Title = strings.TrimSpace(req.Title)
+	req.Mode = strings.TrimSpace(req.Mode)
+	req.Location = strings.TrimSpace(req.Location)
+	req.VideoURL = strings.TrimSpace(req.VideoURL)
+	req.Notes = strings.TrimSpace(req.Notes)
+	req.Timezone = strings.TrimSpace(req.Timezone)
+
+	if req.Mode == "" {
+		req.Mode = models.InterviewModeVideo
+	}
+	if _, ok := interviewModeLabels[req.Mode]; !ok {
+		return "Formato inválido. Use: video, in_person, phone"
+	}
+	if req.Mode == models.InterviewModeVideo {
+		u, err := url.Parse(req.VideoURL)
+		if req.VideoURL == "" || err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return "Informe em video_url o link da chamada (http ou https)"
+		}
+	} else {
+		req.VideoURL = ""
+	}
+	if req.Mode == models.InterviewModeInPerson && req.Location == "" {
+		return "Informe em location o endereço da entrevista presencial"
+	}
+	if utf8.RuneCountInString(req.Title) > 150 || utf8.RuneCountInString(req.Location) > 300 || len(req.VideoURL) > 500 {
+		return "title, location ou video_url longos demais"
+	}
+	if utf8.RuneCountInString(req.Notes) > 2000 {
+		return "As instruções devem ter no máximo 2000 caracteres"
+	}
+
+	if req.Timezone == "" {
+		req.Timezone = defaultInterviewTimezone
+	}
+	if _, err := time.LoadLocation(req.Timezone); err != nil {
+		return "Fuso horário inválido (use o nome IANA, ex: America/Sao_Paulo)"
+	}
+
+	if len(req.Slots) == 0 || len(req.Slots) > maxInterviewSlots {
+		return fmt.Sprintf("Proponha de 1 a %d horários em slots", maxInterviewSlots)
+	}
+	now := time.Now()
+	for _, slot := range req.Slots {
+		if slot.StartsAt.IsZero() || slot.EndsAt.IsZero() {
+			return "Informe starts_at e ends_at de cada horário"
+		}
+		if !slot.StartsAt.After(now) || slot.StartsAt.After(now.Add(maxInterviewAdvance)) {
+			return "Os horários devem estar no futuro e em até 180 dias"
+		}
+		if !slot.EndsAt.After(slot.StartsAt) || slot.EndsAt.Sub(slot.StartsAt) > maxInterviewDuration {
+			return "Cada horário deve terminar depois de começar e durar no máximo 8 horas"
+		}
+	}
+	sort.Slice(req.Slots, func(i, j int) bool { return req.Slots[i].StartsAt.Before(req.Slots[j].StartsAt) })
+
+	return ""
+}
+
+// apply copia a requisição para a entrevista. Com um único horário a entrevista fica agendada;
+// com vários, volta a aguardar a escolha do candidato.
+func (req *InterviewRequest) apply(interview *models.Interview) {
+	interview.Title = req.Title
+	if interview.Title == "" {
+		interview.Title = "Entrevista - " + interview.JobTitle
+	}
+	interview.Mode = req.Mode
+	interview.Location = req.Location
+	interview.VideoURL = req.VideoURL
+	interview.Notes = req.Notes
+	interview.Timezone = req.Timezone
+
+	interview.Slots = make([]models.InterviewSlot, 0, len(req.Slots))
+	for _, slot := range req.Slots {
+		interview.Slots = append(interview.Slots, models.InterviewSlot{
+			ID:       bson.NewObjectID(),
+			StartsAt: slot.StartsAt.UTC(),
+			EndsAt:   slot.EndsAt.UTC(),
+		})
+	}
+
+	interview.SelectedSlotID = nil
+	interview.StartsAt = nil
+	interview.EndsAt = nil
+	if len(interview.Slots) == 1 {
+		interview.Schedule(&interview.Slots[0])
+	} else {
+		interview.Status = models.InterviewStatusProposed
+		interview.SortAt = interview.Slots[0].StartsAt
+	}
+}
+
+// interviewPathParts retorna os segmentos após /{company|candidate}/interviews/
+// (ex: {id}, {id}/cancel, {id}/invite.ics)
+func interviewPathParts(r *http.Request, side string) []string {
+	prefix := "/" + side + "/interviews/"
+	return strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, prefix), "/"), "/")
+}
+
+// loadInterview busca a entrevista do path garantindo que pertence ao candidato ou à empresa
+// (e, para recrutadores, que estão na equipe de contratação da vaga)
+func (h *InterviewsHandler) loadInterview(ctx context.Context, w http.ResponseWriter, r *http.Request, side string) (*models.Interview, *jobs.Job, bool) {
+	userID := r.Context().Value(middleware.UserIDKey).(string)
+
+	var interview *models.Interview
+	id, err := bson.ObjectIDFromHex(interviewPathParts(r, side)[0])
+	if err == nil {
+		interview, err = h.repo.GetByID(ctx, id)
+	}
+	if err == nil {
+		owner := interview.CandidateID.Hex()
+		if side == models.RecipientCompany {
+			owner = interview.CompanyID.Hex()
+		}
+		if owner != userID {
+			err = mongo.ErrNoDocuments
+		}
+	}
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		if errors.Is(err, mongo.ErrNoDocuments) || errors.Is(err, bson.ErrInvalidHex) {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "Entrevista não encontrada",
+			})
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "Erro ao buscar entrevista",
+			})
+		}
+		return nil, nil, false
+	}
+
+	job, err := h.jobRepo.GetByID(ctx, interview.JobID.Hex())
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Vaga não encontrada",
+		})
+		return nil, nil, false
+	}
+
+	if side == models.RecipientCompany && !canAccessJobApplicants(r, job) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Você não está na equipe de contratação desta vaga",
+		})
+		return nil, nil, false
+	}
+
+	return interview, job, true
+}
+
+// Create agenda uma entrevista para a candidatura: um horário agenda direto (convite .ics por email),
+// vários são propostos para o candidato escolher (POST /company/applications/{id}/interviews)
+func (h *InterviewsHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req InterviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Dados inválidos",
+		})
+		return
+	}
+	if msg := req.validate(); msg != "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": msg,
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	applicationID := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/company/applications/"), "/"), "/")[0]
+	app, job, ok := loadOwnApplication(ctx, w, r, h.appRepo, h.jobRepo, models.RecipientCompany, applicationID)
+	if !ok {
+		return
+	}
+	if app.Status == "rejected" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Não é possível agendar entrevista para uma candidatura recusada",
+		})
+		return
+	}
+
+	candidate, err := h.candidateRepo.GetByID(ctx, app.CandidateID.Hex())
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Candidato não encontrado",
+		})
+		return
+	}
+
+	interview := &models.Interview{
+		ApplicationID: app.ID,
+		JobID:         app.JobID,
+		CompanyID:     app.CompanyID,
+		CandidateID:   app.CandidateID,
+		JobTitle:      job.Title,
+		CompanyName:   job.Company,
+		CandidateName: candidate.Name,
+		CreatedBy:     middleware.CompanyMemberID(r),
+	}
+	req.apply(interview)
+	interview.InviteSent = interview.Status == models.InterviewStatusScheduled
+
+	if err := h.repo.Create(ctx, interview); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao agendar entrevista",
+		})
+		return
+	}
+
+	// A candidatura avança: para "interview" quando pedido; de "pending" para "viewed" no mínimo,
+	// para o candidato não cancelar uma candidatura com entrevista marcada
+	status := app.Status
+	if req.MoveToInterviewStage {
+		status = "interview"
+	} else if app.Status == "pending" {
+		status = "viewed"
+	}
+	h.moveApplication(ctx, job, app, status)
+
+	h.sendToCandidate(ctx, interview, candidate, false, nil)
+	if interview.Status == models.InterviewStatusScheduled {
+		h.notifier.InterviewScheduled(ctx, job, interview, formatInterviewTime(interview), false)
+	} else {
+		h.notifier.InterviewProposed(ctx, interview)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(interview)
+}
+
+// moveApplication muda o status da candidatura (webhook e notificação no app; o email da
+// entrevista já avisa o candidato)
+func (h *InterviewsHandler) moveApplication(ctx context.Context, job *jobs.Job, app *applications.Application, status string) {
+	if status == app.Status {
+		return
+	}
+
+	previousStatus := app.Status
+	now := time.Now()
+	app.Status = status
+	app.UpdatedAt = now
+	if app.ViewedAt == nil {
+		app.ViewedAt = &now
+	}
+	if err := h.appRepo.Update(ctx, app); err != nil {
+		log.Printf("Erro ao mover candidatura %s para %s: %v", app.ID.Hex(), status, err)
+		return
+	}
+
+	h.notifier.ApplicationStatusChanged(ctx, job, app, applicationStatusLabels[app.Status])
+	h.webhooks.ApplicationStatusChanged(ctx, job, app, previousStatus)
+}
+
+// ListForApplication retorna as entrevistas da candidatura, mais recentes primeiro
+// (GET /company/applications/{id}/interviews)
+func (h *InterviewsHandler) ListForApplication(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	applicationID := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/company/applications/"), "/"), "/")[0]
+	app, _, ok := loadOwnApplication(ctx, w, r, h.appRepo, h.jobRepo, models.RecipientCompany, applicationID)
+	if !ok {
+		return
+	}
+
+	interviews, err := h.repo.ListByApplication(ctx, app.ID)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao listar entrevistas",
+		})
+		return
+	}
+	if interviews == nil {
+		interviews = []*models.Interview{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"entrevistas": interviews,
+	})
+}
+
+// List retorna a agenda de entrevistas do usuário em ordem cronológica
+// (GET /company/interviews e /candidate/interviews?from=&to=&status=&limit=).
+// Sem from, começa no início do dia atual.
+func (h *InterviewsHandler) List(side string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, _ := bson.ObjectIDFromHex(r.Context().Value(middleware.UserIDKey).(string))
+		query := r.URL.Query()
+
+		filter := repository.InterviewFilter{
+			From:   time.Now().Truncate(24 * time.Hour),
+			Status: query.Get("status"),
+			Limit:  50,
+		}
+		if l, err := strconv.ParseInt(query.Get("limit"), 10, 64); err == nil && l > 0 && l <= 200 {
+			filter.Limit = l
+		}
+		for param, target := range map[string]*time.Time{"from": &filter.From, "to": &filter.To} {
+			if value := query.Get(param); value != "" {
+				t, err := time.Parse(time.RFC3339, value)
+				if err != nil {
+					w.Header().Set("Content-Type", "application/json")
+					w.WriteHeader(http.StatusBadRequest)
+					json.NewEncoder(w).Encode(map[string]string{
+						"erro": "Parâmetro " + param + " inválido (use RFC 3339, ex: 2024-12-01T00:00:00-03:00)",
+					})
+					return
+				}
+				*target = t
+			}
+		}
+		if filter.Status != "" && filter.Status != models.InterviewStatusProposed &&
+			filter.Status != models.InterviewStatusScheduled && filter.Status != models.InterviewStatusCanceled {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "Status inválido. Use: proposed, scheduled, canceled",
+			})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		var interviews []*models.Interview
+		var err error
+		if side == models.RecipientCompany {
+			var jobIDs []bson.ObjectID
+			jobIDs, err = companyJobFilter(ctx, r, h.jobRepo, userID)
+			if err == nil {
+				interviews, err = h.repo.ListByCompany(ctx, userID, jobIDs, filter)
+			}
+		} else {
+			interviews, err = h.repo.ListByCandidate(ctx, userID, filter)
+		}
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "Erro ao listar entrevistas",
+			})
+			return
+		}
+		if interviews == nil {
+			interviews = []*models.Interview{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"entrevistas": interviews,
+		})
+	}
+}
+
+// Get retorna a entrevista (GET /company/interviews/{id} e /candidate/interviews/{id})
+func (h *InterviewsHandler) Get(side string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		interview, _, ok := h.loadInterview(ctx, w, r, side)
+		if !ok {
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(interview)
+	}
+}
+
+// Update remarca a entrevista ou altera os detalhes (PUT /company/interviews/{id}).
+// Os horários são substituídos: um horário reagenda direto, vários voltam para a escolha do candidato.
+func (h *InterviewsHandler) Update(w http.ResponseWriter, r *http.Request) {
+	var req InterviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Dados inválidos",
+		})
+		return
+	}
+	if msg := req.validate(); msg != "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": msg,
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	interview, job, ok := h.loadInterview(ctx, w, r, models.RecipientCompany)
+	if !ok {
+		return
+	}
+	if interview.Status == models.InterviewStatusCanceled {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Entrevista cancelada não pode ser alterada",
+		})
+		return
+	}
+
+	candidate, err := h.candidateRepo.GetByID(ctx, interview.CandidateID.Hex())
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Candidato não encontrado",
+		})
+		return
+	}
+
+	// Voltando para a escolha de horário, o evento que está na agenda do candidato é desmarcado (CANCEL)
+	var unscheduled *models.Interview
+	if interview.InviteSent {
+		previous := *interview
+		unscheduled = &previous
+	}
+
+	previousSequence := interview.Sequence
+	interview.Sequence++
+	req.apply(interview)
+	if unscheduled != nil && interview.Status == models.InterviewStatusProposed {
+		unscheduled.Sequence = interview.Sequence
+	} else {
+		unscheduled = nil
+	}
+	interview.InviteSent = interview.Status == models.InterviewStatusScheduled
+
+	if !h.save(ctx, w, interview, previousSequence) {
+		return
+	}
+
+	h.sendToCandidate(ctx, interview, candidate, true, unscheduled)
+	if interview.Status == models.InterviewStatusScheduled {
+		h.notifier.InterviewScheduled(ctx, job, interview, formatInterviewTime(interview), false)
+	} else {
+		h.notifier.InterviewProposed(ctx, interview)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(interview)
+}
+
+// Cancel cancela a entrevista e, se ela estava na agenda do candidato, envia o convite de
+// cancelamento (POST /company/interviews/{id}/cancel)
+func (h *InterviewsHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	reason, ok := decodeReason(w, r, false)
+	if !ok {
+		return
+	}
+	if utf8.RuneCountInString(reason) > 500 {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "O motivo deve ter no máximo 500 caracteres",
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	interview, _, ok := h.loadInterview(ctx, w, r, models.RecipientCompany)
+	if !ok {
+		return
+	}
+	if interview.Status == models.InterviewStatusCanceled {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Entrevista já cancelada",
+		})
+		return
+	}
+
+	now := time.Now()
+	previousSequence := interview.Sequence
+	interview.Sequence++
+	interview.Status = models.InterviewStatusCanceled
+	interview.CancelReason = reason
+	interview.CanceledAt = &now
+	sendCancel := interview.InviteSent
+	interview.InviteSent = false
+
+	if !h.save(ctx, w, interview, previousSequence) {
+		return
+	}
+
+	if candidate, err := h.candidateRepo.GetByID(ctx, interview.CandidateID.Hex()); err == nil {
+		data := h.emailData(interview, candidate)
+		data["Reason"] = reason
+		data["InviteAttached"] = sendCancel
+		var attachments []models.EmailAttachment
+		if sendCancel {
+			attachments = append(attachments, h.inviteAttachment(interview, candidate, calendar.MethodCancel))
+		}
+		if err := h.mailer.Enqueue(ctx, candidate.Email, mail.TemplateInterviewCanceled, data, attachments...); err != nil {
+			log.Printf("Erro ao enfileirar email de cancelamento da entrevista %s: %v", interview.ID.Hex(), err)
+		}
+	}
+	h.notifier.InterviewCanceled(ctx, interview)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"mensagem":   "Entrevista cancelada",
+		"entrevista": interview,
+	})
+}
+
+// Choose registra o horário escolhido pelo candidato entre os propostos
+// (POST /candidate/interviews/{id}/choose)
+func (h *InterviewsHandler) Choose(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		SlotID string `json:"slot_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Dados inválidos",
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	interview, job, ok := h.loadInterview(ctx, w, r, models.RecipientCandidate)
+	if !ok {
+		return
+	}
+	if interview.Status != models.InterviewStatusProposed {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Esta entrevista não está aguardando a escolha de horário",
+		})
+		return
+	}
+
+	var slot *models.InterviewSlot
+	if slotID, err := bson.ObjectIDFromHex(req.SlotID); err == nil {
+		slot = interview.Slot(slotID)
+	}
+	if slot == nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Horário inválido: use o id de um dos horários propostos",
+		})
+		return
+	}
+	if !slot.StartsAt.After(time.Now()) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Este horário já passou. Escolha outro ou peça novos horários à empresa",
+		})
+		return
+	}
+
+	candidate, err := h.candidateRepo.GetByID(ctx, interview.CandidateID.Hex())
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Candidato não encontrado",
+		})
+		return
+	}
+
+	previousSequence := interview.Sequence
+	interview.Sequence++
+	interview.Schedule(slot)
+	interview.InviteSent = true
+
+	if !h.save(ctx, w, interview, previousSequence) {
+		return
+	}
+
+	h.sendToCandidate(ctx, interview, candidate, false, nil)
+	h.notifier.InterviewScheduled(ctx, job, interview, formatInterviewTime(interview), true)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(interview)
+}
+
+// Invite baixa o convite .ics da entrevista: REQUEST se agendada, CANCEL se foi cancelada depois
+// de ter horário (GET /company/interviews/{id}/invite.ics e /candidate/interviews/{id}/invite.ics)
+func (h *InterviewsHandler) Invite(side string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		interview, _, ok := h.loadInterview(ctx, w, r, side)
+		if !ok {
+			return
+		}
+
+		method := calendar.MethodRequest
+		if interview.Status == models.InterviewStatusCanceled {
+			method = calendar.MethodCancel
+		}
+		if interview.StartsAt == nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "A entrevista ainda não tem horário definido",
+			})
+			return
+		}
+
+		candidate, err := h.candidateRepo.GetByID(ctx, interview.CandidateID.Hex())
+		if err != nil {
+			candidate = &candidates.Candidate{Name: interview.CandidateName}
+		}
+		attachment := h.inviteAttachment(interview, candidate, method)
+
+		w.Header().Set("Content-Type", attachment.ContentType)
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename}))
+		w.Header().Set("Cache-Control", "private, no-store")
+		w.WriteHeader(http.StatusOK)
+		w.Write(attachment.Content)
+	}
+}
+
+// save grava a entrevista com controle de concorrência; responde 409 se outra alteração venceu
+func (h *InterviewsHandler) save(ctx context.Context, w http.ResponseWriter, interview *models.Interview, previousSequence int) bool {
+	updated, err := h.repo.Update(ctx, interview, previousSequence)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao atualizar entrevista",
+		})
+		return false
+	}
+	if !updated {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "A entrevista foi alterada ao mesmo tempo por outra pessoa. Recarregue e tente novamente",
+		})
+		return false
+	}
+	return true
+}
+
+// sendToCandidate envia ao candidato os horários propostos ou a confirmação com o convite .ics.
+// unscheduled é a versão anterior da entrevista quando o horário que estava na agenda dele foi
+// desmarcado: o email leva o CANCEL dela.
+func (h *InterviewsHandler) sendToCandidate(ctx context.Context, interview *models.Interview, candidate *candidates.Candidate, updated bool, unscheduled *models.Interview) {
+	data := h.emailData(interview, candidate)
+	data["Updated"] = updated
+
+	template := mail.TemplateInterviewProposed
+	var attachments []models.EmailAttachment
+	if interview.Status == models.InterviewStatusScheduled {
+		template = mail.TemplateInterviewScheduled
+		attachments = append(attachments, h.inviteAttachment(interview, candidate, calendar.MethodRequest))
+	} else {
+		slots := make([]string, 0, len(interview.Slots))
+		for _, slot := range interview.Slots {
+			slots = append(slots, formatInterviewSlot(slot.StartsAt, slot.EndsAt, interview.Timezone))
+		}
+		data["Slots"] = slots
+		data["InviteAttached"] = unscheduled != nil
+		if unscheduled != nil {
+			attachments = append(attachments, h.inviteAttachment(unscheduled, candidate, calendar.MethodCancel))
+		}
+	}
+
+	if err := h.mailer.Enqueue(ctx, candidate.Email, template, data, attachments...); err != nil {
+		log.Printf("Erro ao enfileirar email da entrevista %s: %v", interview.ID.Hex(), err)
+	}
+}
+
+func (h *InterviewsHandler) emailData(interview *models.Interview, candidate *candidates.Candidate) map[string]interface{} {
+	data := map[string]interface{}{
+		"Name":            candidate.Name,
+		"JobTitle":        interview.JobTitle,
+		"Company":         interview.CompanyName,
+		"ModeLabel":       interviewModeLabels[interview.Mode],
+		"Location":        interview.Location,
+		"VideoURL":        interview.VideoURL,
+		"Notes":           interview.Notes,
+		"InterviewURL":    h.mailer.URL("/candidate/interviews/" + interview.ID.Hex()),
+		"ApplicationsURL": h.mailer.URL("/candidate/applications"),
+	}
+	if interview.StartsAt != nil {
+		data["When"] = formatInterviewTime(interview)
+	}
+	return data
+}
+
+// inviteAttachment gera o convite .ics da entrevista (a empresa organiza, o candidato participa)
+func (h *InterviewsHandler) inviteAttachment(interview *models.Interview, candidate *candidates.Candidate, method string) models.EmailAttachment {
+	description := "Entrevista para a vaga " + interview.JobTitle + " (" + interview.CompanyName + ")\n" +
+		"Formato: " + interviewModeLabels[interview.Mode]
+	location := interview.Location
+	if interview.VideoURL != "" {
+		description += "\nLink da chamada: " + interview.VideoURL
+		if location == "" {
+			location = interview.VideoURL
+		}
+	}
+	if interview.Notes != "" {
+		description += "\n\n" + interview.Notes
+	}
+
+	event := calendar.Event{
+		UID:         interview.UID(),
+		Sequence:    interview.Sequence,
+		Start:       *interview.StartsAt,
+		End:         *interview.EndsAt,
+		Summary:     interview.Title,
+		Description: description,
+		Location:    location,
+		URL:         interview.VideoURL,
+		Organizer:   calendar.Person{Name: interview.CompanyName, Email: h.mailer.FromAddress()},
+	}
+	if candidate.Email != "" {
+		event.Attendees = []calendar.Person{{Name: candidate.Name, Email: candidate.Email}}
+	}
+
+	return models.EmailAttachment{
+		Filename:    calendar.Filename,
+		ContentType: calendar.ContentType(method),
+		Content:     calendar.Invite(method, event),
+	}
+}
+
+// formatInterviewTime formata o horário definido da entrevista no fuso dela
+func formatInterviewTime(interview *models.Interview) string {
+	if interview.StartsAt == nil {
+		return ""
+	}
+	return formatInterviewSlot(*interview.StartsAt, *interview.EndsAt, interview.Timezone)
+}
+
+// formatInterviewSlot formata um horário como "qui, 28/11/2024 das 14:00 às 15:00 (America/Sao_Paulo)"
+func formatInterviewSlot(start, end time.Time, timezone string) string {
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		loc, timezone = time.UTC, "UTC"
+	}
+	start, end = start.In(loc), end.In(loc)
+
+	return fmt.Sprintf("%s, %s das %s às %s (%s)",
+		weekdayLabels[start.Weekday()], start.Format("02/01/2006"), start.Format("15:04"), end.Format("15:04"), timezone)
+}
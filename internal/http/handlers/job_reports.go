@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"context"
+	"empregabemapi/internal/middleware"
+	"empregabemapi/internal/models"
+	"empregabemapi/internal/repository"
+	"empregabemapi/jobs"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Denúncias abertas de candidatos distintos que tiram a vaga do ar até a revisão de um admin
+const jobReportHideThreshold = 3
+
+const maxReportCommentLength = 1000
+
+type JobReportsHandler struct {
+	reportRepo *repository.JobReportsRepository
+	jobRepo    *jobs.MongoRepository
+	auditRepo  *repository.AuditRepository
+}
+
+func NewJobReportsHandler(reportRepo *repository.JobReportsRepository, jobRepo *jobs.MongoRepository, auditRepo *repository.AuditRepository) *JobReportsHandler {
+	return &JobReportsHandler{
+		reportRepo: reportRepo,
+		jobRepo:    jobRepo,
+		auditRepo:  auditRepo,
+	}
+}
+
+type ReportJobRequest struct {
+	Category string `json:"category"`
+	Comment  string `json:"comment"`
+}
+
+// Report registra a denúncia de uma vaga pelo candidato (POST /jobs/{id}/report).
+// Cada candidato denuncia uma vaga uma única vez.
+func (h *JobReportsHandler) Report(w http.ResponseWriter, r *http.Request) {
+	candidateID := r.Context().Value(middleware.UserIDKey).(string)
+
+	jobID := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/jobs/"), "/report")
+
+	var req ReportJobRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Dados inválidos",
+		})
+		return
+	}
+
+	req.Comment = strings.TrimSpace(req.Comment)
+	if !models.ValidReportCategories[req.Category] {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Categoria inválida. Use: fraud, fee_charged, discrimination, misleading, spam, offensive ou other",
+		})
+		return
+	}
+	if req.Category == models.ReportCategoryOther && req.Comment == "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Descreva o problema no campo comment",
+		})
+		return
+	}
+	if utf8.RuneCountInString(req.Comment) > maxReportCommentLength {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": fmt.Sprintf("Comentário deve ter no máximo %d caracteres", maxReportCommentLength),
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	job, err := h.jobRepo.GetByID(ctx, jobID)
+	if err != nil || !job.IsPublic() {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Vaga não encontrada",
+		})
+		return
+	}
+
+	candidateObjID, _ := bson.ObjectIDFromHex(candidateID)
+	report := &models.JobReport{
+		JobID:       job.ID,
+		CompanyID:   job.CompanyID,
+		CandidateID: candidateObjID,
+		Category:    req.Category,
+		Comment:     req.Comment,
+	}
+
+	if err := h.reportRepo.Create(ctx, report); err != nil {
+		if err == repository.ErrAlreadyReported {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "Você já denunciou esta vaga",
+			})
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao registrar denúncia",
+		})
+		return
+	}
+
+	h.hideIfReportedTooOften(ctx, job)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]string{
+		"mensagem": "Denúncia registrada. Obrigado por ajudar a manter o EmpregaBem seguro.",
+	})
+}
+
+// hideIfReportedTooOften retira a vaga do ar (fila de revisão) ao atingir o limite de denúncias abertas
+func (h *JobReportsHandler) hideIfReportedTooOften(ctx context.Context, job *jobs.Job) {
+	count, err := h.reportRepo.CountOpenByJob(ctx, job.ID)
+	if err != nil {
+		log.Printf("Erro ao contar denúncias da vaga %s: %v", job.ID.Hex(), err)
+		return
+	}
+	if count < jobReportHideThreshold {
+		return
+	}
+
+	flag := jobs.ModerationFlag{
+		Rule:   jobs.RuleReported,
+		Detail: fmt.Sprintf("%d denúncias de candidatos", count),
+	}
+	held, err := h.jobRepo.HoldForReview(ctx, job.ID, flag)
+	if err != nil {
+		log.Printf("Erro ao retirar do ar a vaga denunciada %s: %v", job.ID.Hex(), err)
+		return
+	}
+	if !held {
+		return
+	}
+
+	err = h.auditRepo.Log(ctx, &models.AuditLog{
+		Action:     "job.auto_hidden",
+		ActorType:  "system",
+		TargetType: "job",
+		TargetID:   job.ID.Hex(),
+		Details: map[string]interface{}{
+			"reports":    count,
+			"company_id": job.CompanyID.Hex(),
+		},
+	})
+	if err != nil {
+		log.Printf("Erro ao registrar auditoria da vaga %s: %v", job.ID.Hex(), err)
+	}
+}
@@ -2,29 +2,44 @@ package handlers
 
 import (
 	"context"
+	"empregabemapi/candidates"
+	"empregabemapi/internal/matching"
+	"empregabemapi/internal/middleware"
 	"empregabemapi/jobs"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 )
 
 type JobsHandler struct {
-	repo *jobs.MongoRepository
+	repo          *jobs.MongoRepository
+	candidateRepo *candidates.MongoRepository
 }
 
-func NewJobsHandler(repo *jobs.MongoRepository) *JobsHandler {
+func NewJobsHandler(repo *jobs.MongoRepository, candidateRepo *candidates.MongoRepository) *JobsHandler {
 	return &JobsHandler{
-		repo: repo,
+		repo:          repo,
+		candidateRepo: candidateRepo,
 	}
 }
 
+// JobDetail é a vaga exibida em GET /jobs/{id}; candidatos logados recebem também a aderência
+// das suas habilidades aos requisitos
+type JobDetail struct {
+	*jobs.Job
+	Match *matching.Result `json:"match,omitempty"`
+}
+
 func (h *JobsHandler) List(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
 	// Verifica se há filtros na query
 	query := r.URL.Query()
+	text := strings.TrimSpace(query.Get("q"))
 	location := query.Get("location")
 	jobType := query.Get("jobType")
 	level := query.Get("level")
@@ -35,16 +50,20 @@ func (h *JobsHandler) List(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	verifiedOnly := query.Get("verified") == "true"
+
 	// Se houver qualquer filtro, usa Search, senão usa List
 	var jobsList []*jobs.Job
 	var err error
 
-	if location != "" || jobType != "" || level != "" || minSalary > 0 {
+	if text != "" || location != "" || jobType != "" || level != "" || minSalary > 0 || verifiedOnly {
 		filters := jobs.SearchFilters{
-			Location:  location,
-			JobType:   jobType,
-			Level:     level,
-			MinSalary: minSalary,
+			Query:        text,
+			Location:     location,
+			JobType:      jobType,
+			Level:        level,
+			MinSalary:    minSalary,
+			VerifiedOnly: verifiedOnly,
 		}
 		jobsList, err = h.repo.Search(ctx, filters)
 	} else {
@@ -154,7 +173,7 @@ func (h *JobsHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	defer cancel()
 
 	job, err := h.repo.GetByID(ctx, id)
-	if err != nil {
+	if err != nil || !job.IsPublic() {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusNotFound)
 		json.NewEncoder(w).Encode(map[string]string{
@@ -163,9 +182,55 @@ func (h *JobsHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	detail := JobDetail{Job: job}
+	if userType, _ := r.Context().Value(middleware.UserTypeKey).(string); userType == "candidate" {
+		candidateID := r.Context().Value(middleware.UserIDKey).(string)
+		if candidate, err := h.candidateRepo.GetByID(ctx, candidateID); err == nil {
+			detail.Match = matching.Score(job.Requirements, candidate.Skills)
+		}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(job)
+	json.NewEncoder(w).Encode(detail)
+}
+
+// Similar retorna as vagas no ar mais parecidas com a vaga (GET /jobs/{id}/similar?limit=6)
+func (h *JobsHandler) Similar(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/jobs/"), "/similar")
+
+	limit := 6
+	if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 && l <= 20 {
+		limit = l
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	job, err := h.repo.GetByID(ctx, id)
+	if err != nil || !job.IsPublic() {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Vaga não encontrada",
+		})
+		return
+	}
+
+	similar, err := h.repo.ListSimilar(ctx, job, limit)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao buscar vagas semelhantes",
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"vagas": similar,
+	})
 }
 
 func (h *JobsHandler) Update(w http.ResponseWriter, r *http.Request) {
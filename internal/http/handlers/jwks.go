@@ -0,0 +1,22 @@
+package handlers
+
+import (
+	"empregabemapi/internal/auth"
+	"encoding/json"
+	"net/http"
+)
+
+type JWKSHandler struct{}
+
+func NewJWKSHandler() *JWKSHandler {
+	return &JWKSHandler{}
+}
+
+// Keys publica as chaves públicas usadas para assinar os tokens de sessão
+func (h *JWKSHandler) Keys(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	// Cache curto: chaves novas são publicadas com antecedência antes de assinar
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(auth.JWKS())
+}
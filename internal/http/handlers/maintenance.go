@@ -2,6 +2,8 @@ package handlers
 
 import (
 	"context"
+	"empregabemapi/internal/middleware"
+	"empregabemapi/internal/repository"
 	"empregabemapi/jobs"
 	"encoding/json"
 	"net/http"
@@ -9,22 +11,26 @@ import (
 )
 
 type MaintenanceHandler struct {
-	jobRepo *jobs.MongoRepository
+	jobRepo   *jobs.MongoRepository
+	auditRepo *repository.AuditRepository
 }
 
-func NewMaintenanceHandler(jobRepo *jobs.MongoRepository) *MaintenanceHandler {
+func NewMaintenanceHandler(jobRepo *jobs.MongoRepository, auditRepo *repository.AuditRepository) *MaintenanceHandler {
 	return &MaintenanceHandler{
-		jobRepo: jobRepo,
+		jobRepo:   jobRepo,
+		auditRepo: auditRepo,
 	}
 }
 
 // FixJobCounters corrige os contadores de vagas que não foram inicializados
 func (h *MaintenanceHandler) FixJobCounters(w http.ResponseWriter, r *http.Request) {
+	adminID := r.Context().Value(middleware.UserIDKey).(string)
+
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
 	// Busca todas as vagas
-	allJobs, err := h.jobRepo.List(ctx)
+	allJobs, err := h.jobRepo.ListAll(ctx)
 	if err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusInternalServerError)
@@ -63,6 +69,11 @@ func (h *MaintenanceHandler) FixJobCounters(w http.ResponseWriter, r *http.Reque
 		}
 	}
 
+	logAdminAction(ctx, h.auditRepo, r, adminID, "admin.maintenance_fix_counters", "job", "", map[string]interface{}{
+		"fixed": fixed,
+		"total": len(allJobs),
+	})
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(map[string]interface{}{
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"context"
+	"empregabemapi/internal/media"
+	"empregabemapi/internal/storage"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type MediaHandler struct {
+	library *media.Library
+}
+
+func NewMediaHandler(library *media.Library) *MediaHandler {
+	return &MediaHandler{
+		library: library,
+	}
+}
+
+// Serve entrega um arquivo do storage (GET /media/{chave}).
+// As chaves mudam a cada upload, então o conteúdo de uma URL nunca muda e o cache pode ser permanente.
+func (h *MediaHandler) Serve(w http.ResponseWriter, r *http.Request) {
+	key := strings.TrimPrefix(r.URL.Path, "/media/")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	obj, err := h.library.Open(ctx, key)
+	if err != nil {
+		if err == storage.ErrNotFound || err == storage.ErrInvalidKey {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "Arquivo não encontrado",
+			})
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao abrir arquivo",
+		})
+		return
+	}
+	defer obj.Close()
+
+	w.Header().Set("Content-Type", obj.ContentType)
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	w.Header().Set("ETag", fmt.Sprintf(`"%x-%x"`, obj.ModTime.UnixNano(), obj.Size))
+	// Imagens são exibidas pelo frontend em outra origem; nada além de imagem deve ser interpretado
+	w.Header().Set("Cross-Origin-Resource-Policy", "cross-origin")
+	w.Header().Set("Content-Security-Policy", "default-src 'none'")
+
+	// ServeContent responde 304 para If-None-Match/If-Modified-Since e trata Range
+	http.ServeContent(w, r, "", obj.ModTime, obj)
+}
@@ -0,0 +1,731 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"empregabemapi/applications"
+	"empregabemapi/candidates"
+	"empregabemapi/companies"
+	"empregabemapi/internal/middleware"
+	"empregabemapi/internal/models"
+	"empregabemapi/internal/notifications"
+	"empregabemapi/internal/repository"
+	"empregabemapi/internal/storage"
+	"empregabemapi/internal/webhooks"
+	"empregabemapi/jobs"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+const (
+	maxMessageLength = 5000
+	// Anexos por mensagem e tamanho somado (o limite global de upload é 6MB)
+	maxMessageAttachments     = 3
+	maxMessageAttachmentsSize = 5 << 20
+	maxAttachmentFilename     = 120
+)
+
+// Tipos aceitos nos anexos, detectados pelo conteúdo (não pelo Content-Type enviado), e a
+// extensão usada na chave do storage. DOCX é um zip: aceito apenas com a extensão .docx.
+var messageAttachmentTypes = map[string]string{
+	"application/pdf": ".pdf",
+	"image/png":       ".png",
+	"image/jpeg":      ".jpg",
+	"text/plain":      ".txt",
+}
+
+const docxContentType = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+
+// MessagesHandler cuida das conversas entre empresa e candidato sobre uma candidatura.
+// As mesmas rotas existem para os dois lados (/company/... e /candidate/...); side indica quem chama.
+type MessagesHandler struct {
+	repo          *repository.MessagesRepository
+	appRepo       *applications.MongoRepository
+	jobRepo       *jobs.MongoRepository
+	candidateRepo *candidates.MongoRepository
+	memberRepo    *companies.MemberRepository
+	attachments   storage.Store
+	notifier      *notifications.Service
+	webhooks      *webhooks.Dispatcher
+}
+
+func NewMessagesHandler(
+	repo *repository.MessagesRepository,
+	appRepo *applications.MongoRepository,
+	jobRepo *jobs.MongoRepository,
+	candidateRepo *candidates.MongoRepository,
+	memberRepo *companies.MemberRepository,
+	attachments storage.Store,
+	notifier *notifications.Service,
+	webhookDispatcher *webhooks.Dispatcher,
+) *MessagesHandler {
+	return &MessagesHandler{
+		repo:          repo,
+		appRepo:       appRepo,
+		jobRepo:       jobRepo,
+		candidateRepo: candidateRepo,
+		memberRepo:    memberRepo,
+		attachments:   attachments,
+		notifier:      notifier,
+		webhooks:      webhookDispatcher,
+	}
+}
+
+// messageView é a mensagem vista por um dos lados; read_at indica quando o outro lado leu
+// (apenas nas mensagens enviadas por quem está vendo)
+type messageView struct {
+	*models.Message
+	ReadAt *time.Time `json:"read_at,omitempty"`
+}
+
+// threadView é a conversa com as não lidas de quem está vendo
+type threadView struct {
+	*models.MessageThread
+	Unread int `json:"unread"`
+}
+
+// messageUpload é um anexo recebido e validado, ainda não gravado no storage
+type messageUpload struct {
+	filename    string
+	contentType string
+	ext         string
+	data        []byte
+}
+
+func otherMessageSide(side string) string {
+	if side == models.MessageSideCandidate {
+		return models.MessageSideCompany
+	}
+	return models.MessageSideCandidate
+}
+
+// messagePathParts retorna os segmentos após /{company|candidate}/applications/
+// (ex: {id}/messages, {id}/messages/read, {id}/messages/{messageID}/attachments/{n})
+func messagePathParts(r *http.Request, side string) []string {
+	prefix := "/" + side + "/applications/"
+	return strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, prefix), "/"), "/")
+}
+
+// loadApplication busca a candidatura do path e a vaga dela (ver loadOwnApplication)
+func (h *MessagesHandler) loadApplication(ctx context.Context, w http.ResponseWriter, r *http.Request, side string) (*applications.Application, *jobs.Job, bool) {
+	return loadOwnApplication(ctx, w, r, h.appRepo, h.jobRepo, side, messagePathParts(r, side)[0])
+}
+
+// loadOwnApplication busca a candidatura e a vaga dela, garantindo que o usuário é o candidato
+// ou a empresa dona (e, para recrutadores, que está na equipe de contratação da vaga)
+func loadOwnApplication(ctx context.Context, w http.ResponseWriter, r *http.Request, appRepo *applications.MongoRepository, jobRepo *jobs.MongoRepository, side, applicationID string) (*applications.Application, *jobs.Job, bool) {
+	userID := r.Context().Value(middleware.UserIDKey).(string)
+
+	app, err := appRepo.GetByID(ctx, applicationID)
+	if err == nil {
+		owner := app.CandidateID.Hex()
+		if side == models.MessageSideCompany {
+			owner = app.CompanyID.Hex()
+		}
+		if owner != userID {
+			err = mongo.ErrNoDocuments
+		}
+	}
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Candidatura não encontrada",
+		})
+		return nil, nil, false
+	}
+
+	job, err := jobRepo.GetByID(ctx, app.JobID.Hex())
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Vaga não encontrada",
+		})
+		return nil, nil, false
+	}
+
+	if side == models.MessageSideCompany && !canAccessJobApplicants(r, job) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Você não está na equipe de contratação desta vaga",
+		})
+		return nil, nil, false
+	}
+
+	return app, job, true
+}
+
+// loadThread busca a conversa da candidatura; 404 se a empresa ainda não abriu uma
+func (h *MessagesHandler) loadThread(ctx context.Context, w http.ResponseWriter, app *applications.Application) (*models.MessageThread, bool) {
+	thread, err := h.repo.GetThreadByApplication(ctx, app.ID)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "Ainda não há conversa sobre esta candidatura",
+			})
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "Erro ao buscar conversa",
+			})
+		}
+		return nil, false
+	}
+	return thread, true
+}
+
+// companyJobFilter retorna as vagas cujas conversas e entrevistas o usuário da empresa pode ver
+// (nil = todas; recrutadores e leitores só veem as vagas em que estão na equipe)
+func companyJobFilter(ctx context.Context, r *http.Request, jobRepo *jobs.MongoRepository, companyID bson.ObjectID) ([]bson.ObjectID, error) {
+	if middleware.CompanyRole(r).Can(companies.PermAccessAllJobs) {
+		return nil, nil
+	}
+
+	memberID, _ := bson.ObjectIDFromHex(middleware.CompanyMemberID(r))
+	teamJobs, err := jobRepo.GetByHiringTeamMember(ctx, companyID, memberID)
+	if err != nil {
+		return nil, err
+	}
+
+	jobIDs := make([]bson.ObjectID, 0, len(teamJobs))
+	for _, job := range teamJobs {
+		jobIDs = append(jobIDs, job.ID)
+	}
+	return jobIDs, nil
+}
+
+// ListThreads retorna as conversas do usuário, mais recentes primeiro, com o total de não lidas
+// (GET /company/messages e /candidate/messages)
+func (h *MessagesHandler) ListThreads(side string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, _ := bson.ObjectIDFromHex(r.Context().Value(middleware.UserIDKey).(string))
+
+		limit := int64(50)
+		if l, err := strconv.ParseInt(r.URL.Query().Get("limit"), 10, 64); err == nil && l > 0 && l <= 100 {
+			limit = l
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		var threads []*models.MessageThread
+		var unread int64
+		var err error
+		if side == models.MessageSideCompany {
+			var jobIDs []bson.ObjectID
+			jobIDs, err = companyJobFilter(ctx, r, h.jobRepo, userID)
+			if err == nil {
+				threads, err = h.repo.ListCompanyThreads(ctx, userID, jobIDs, limit)
+			}
+			if err == nil {
+				unread, err = h.repo.CountCompanyUnread(ctx, userID, jobIDs)
+			}
+		} else {
+			threads, err = h.repo.ListCandidateThreads(ctx, userID, limit)
+			if err == nil {
+				unread, err = h.repo.CountCandidateUnread(ctx, userID)
+			}
+		}
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "Erro ao listar conversas",
+			})
+			return
+		}
+
+		views := make([]threadView, 0, len(threads))
+		for _, thread := range threads {
+			views = append(views, threadView{MessageThread: thread, Unread: thread.Unread(side)})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"conversas": views,
+			"nao_lidas": unread,
+		})
+	}
+}
+
+// GetThread retorna a conversa da candidatura e uma página de mensagens em ordem cronológica
+// (GET /{company|candidate}/applications/{id}/messages?before={messageID}&limit=30)
+func (h *MessagesHandler) GetThread(side string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		limit := int64(30)
+		if l, err := strconv.ParseInt(query.Get("limit"), 10, 64); err == nil && l > 0 && l <= 100 {
+			limit = l
+		}
+
+		var before bson.ObjectID
+		if b := query.Get("before"); b != "" {
+			var err error
+			if before, err = bson.ObjectIDFromHex(b); err != nil {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusBadRequest)
+				json.NewEncoder(w).Encode(map[string]string{
+					"erro": "Parâmetro before inválido",
+				})
+				return
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		app, _, ok := h.loadApplication(ctx, w, r, side)
+		if !ok {
+			return
+		}
+		thread, ok := h.loadThread(ctx, w, app)
+		if !ok {
+			return
+		}
+
+		messages, err := h.repo.ListMessages(ctx, thread.ID, before, limit)
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "Erro ao buscar mensagens",
+			})
+			return
+		}
+
+		// A página vem das mais recentes para as mais antigas; o cliente exibe em ordem cronológica
+		otherReadAt := thread.ReadAt(otherMessageSide(side))
+		views := make([]messageView, len(messages))
+		for i, message := range messages {
+			view := messageView{Message: message}
+			if message.SenderSide == side && otherReadAt != nil && !otherReadAt.Before(message.CreatedAt) {
+				view.ReadAt = otherReadAt
+			}
+			views[len(messages)-1-i] = view
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"conversa":  threadView{MessageThread: thread, Unread: thread.Unread(side)},
+			"mensagens": views,
+			"tem_mais":  int64(len(messages)) == limit,
+		})
+	}
+}
+
+// MarkRead marca a conversa como lida pelo usuário e avisa o outro lado em tempo real
+// (POST /{company|candidate}/applications/{id}/messages/read)
+func (h *MessagesHandler) MarkRead(side string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		app, job, ok := h.loadApplication(ctx, w, r, side)
+		if !ok {
+			return
+		}
+		thread, ok := h.loadThread(ctx, w, app)
+		if !ok {
+			return
+		}
+
+		readAt, err := h.repo.MarkRead(ctx, thread.ID, side)
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "Erro ao marcar conversa como lida",
+			})
+			return
+		}
+
+		// Sem mensagens novas não há confirmação de leitura para enviar
+		if thread.Unread(side) > 0 {
+			h.notifier.MessagesRead(ctx, job, thread, side, readAt)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"mensagem": "Conversa marcada como lida",
+			"read_at":  readAt,
+		})
+	}
+}
+
+// Send envia uma mensagem na conversa da candidatura. A empresa abre a conversa com a primeira
+// mensagem; o candidato só responde a uma conversa já aberta (evita spam para as empresas).
+// Aceita JSON {"body": "..."} ou multipart com o campo body e até 3 anexos no campo file.
+// (POST /{company|candidate}/applications/{id}/messages)
+func (h *MessagesHandler) Send(side string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := r.Context().Value(middleware.UserIDKey).(string)
+		senderID, _ := bson.ObjectIDFromHex(userID)
+		memberID := middleware.CompanyMemberID(r)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		app, job, ok := h.loadApplication(ctx, w, r, side)
+		if !ok {
+			return
+		}
+
+		thread, err := h.repo.GetThreadByApplication(ctx, app.ID)
+		if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "Erro ao buscar conversa",
+			})
+			return
+		}
+		if thread == nil && side == models.MessageSideCandidate {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusForbidden)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "A empresa ainda não iniciou uma conversa sobre esta candidatura",
+			})
+			return
+		}
+
+		body, uploads, status, msg := readMessageInput(w, r)
+		if status != 0 {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": msg,
+			})
+			return
+		}
+
+		if thread == nil {
+			if thread, ok = h.openThread(ctx, w, app, job, memberID); !ok {
+				return
+			}
+		}
+
+		// Na empresa, a mensagem leva o nome de quem da equipe escreveu (o dono assina como a empresa)
+		senderName := thread.CandidateName
+		if side == models.MessageSideCompany {
+			senderName = job.Company
+			if memberID != "" {
+				if member, err := h.memberRepo.GetByID(ctx, memberID); err == nil {
+					senderName = member.Name
+				}
+			}
+		}
+
+		message := &models.Message{
+			ID:             bson.NewObjectID(),
+			SenderSide:     side,
+			SenderID:       senderID,
+			SenderMemberID: memberID,
+			SenderName:     senderName,
+			Body:           body,
+		}
+
+		// Anexos vão para o storage privado antes da mensagem; se algo falhar, são removidos
+		for i, upload := range uploads {
+			key := fmt.Sprintf("threads/%s/%s-%d%s", thread.ID.Hex(), message.ID.Hex(), i, upload.ext)
+			if err := h.attachments.Put(ctx, key, bytes.NewReader(upload.data), upload.contentType); err != nil {
+				log.Printf("Erro ao gravar anexo %s: %v", key, err)
+				h.removeAttachments(ctx, message.Attachments)
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusInternalServerError)
+				json.NewEncoder(w).Encode(map[string]string{
+					"erro": "Erro ao salvar anexo",
+				})
+				return
+			}
+			message.Attachments = append(message.Attachments, models.MessageAttachment{
+				Key:         key,
+				Filename:    upload.filename,
+				ContentType: upload.contentType,
+				Size:        int64(len(upload.data)),
+			})
+		}
+
+		if err := h.repo.AddMessage(ctx, thread, message); err != nil {
+			h.removeAttachments(ctx, message.Attachments)
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "Erro ao enviar mensagem",
+			})
+			return
+		}
+
+		h.notifier.NewMessage(ctx, job, thread, message)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(messageView{Message: message})
+	}
+}
+
+// openThread abre a conversa da candidatura (primeira mensagem da empresa). Abrir a conversa conta
+// como visualização: a candidatura sai de "pending" e o candidato não pode mais cancelá-la,
+// o que deixaria a conversa sem candidatura.
+func (h *MessagesHandler) openThread(ctx context.Context, w http.ResponseWriter, app *applications.Application, job *jobs.Job, memberID string) (*models.MessageThread, bool) {
+	candidate, err := h.candidateRepo.GetByID(ctx, app.CandidateID.Hex())
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Candidato não encontrado",
+		})
+		return nil, false
+	}
+
+	thread, err := h.repo.GetOrCreateThread(ctx, &models.MessageThread{
+		ApplicationID: app.ID,
+		JobID:         app.JobID,
+		CompanyID:     app.CompanyID,
+		CandidateID:   app.CandidateID,
+		JobTitle:      job.Title,
+		CompanyName:   job.Company,
+		CandidateName: candidate.Name,
+		StartedBy:     memberID,
+	})
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao abrir conversa",
+		})
+		return nil, false
+	}
+
+	if app.Status == "pending" {
+		now := time.Now()
+		app.Status = "viewed"
+		app.ViewedAt = &now
+		app.UpdatedAt = now
+		if err := h.appRepo.Update(ctx, app); err != nil {
+			log.Printf("Erro ao marcar candidatura %s como visualizada: %v", app.ID.Hex(), err)
+		} else {
+			h.webhooks.ApplicationStatusChanged(ctx, job, app, "pending")
+		}
+	}
+
+	return thread, true
+}
+
+func (h *MessagesHandler) removeAttachments(ctx context.Context, attachments []models.MessageAttachment) {
+	for _, attachment := range attachments {
+		if err := h.attachments.Delete(ctx, attachment.Key); err != nil {
+			log.Printf("Erro ao remover anexo %s do storage: %v", attachment.Key, err)
+		}
+	}
+}
+
+// Attachment baixa um anexo da conversa. Os anexos não são públicos: só os dois lados da
+// conversa passam por aqui, e o arquivo é sempre entregue como download.
+// (GET /{company|candidate}/applications/{id}/messages/{messageID}/attachments/{n})
+func (h *MessagesHandler) Attachment(side string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		parts := messagePathParts(r, side)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		app, _, ok := h.loadApplication(ctx, w, r, side)
+		if !ok {
+			return
+		}
+		thread, ok := h.loadThread(ctx, w, app)
+		if !ok {
+			return
+		}
+
+		var attachment *models.MessageAttachment
+		messageID, err := bson.ObjectIDFromHex(parts[2])
+		if err == nil {
+			var message *models.Message
+			if message, err = h.repo.GetMessage(ctx, messageID, thread.ID); err == nil {
+				n, convErr := strconv.Atoi(parts[4])
+				if convErr == nil && n >= 0 && n < len(message.Attachments) {
+					attachment = &message.Attachments[n]
+				}
+			}
+		}
+		if attachment == nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "Anexo não encontrado",
+			})
+			return
+		}
+
+		obj, err := h.attachments.Open(ctx, attachment.Key)
+		if err != nil {
+			log.Printf("Erro ao abrir anexo %s: %v", attachment.Key, err)
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "Anexo não encontrado",
+			})
+			return
+		}
+		defer obj.Close()
+
+		w.Header().Set("Content-Type", attachment.ContentType)
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename}))
+		w.Header().Set("Cache-Control", "private, no-store")
+		w.Header().Set("Content-Security-Policy", "default-src 'none'")
+		http.ServeContent(w, r, "", obj.ModTime, obj)
+	}
+}
+
+// readMessageInput lê o texto e os anexos da mensagem (JSON ou multipart), já validados.
+// Em caso de erro retorna o status HTTP e a mensagem para o cliente.
+func readMessageInput(w http.ResponseWriter, r *http.Request) (string, []messageUpload, int, string) {
+	var body string
+	var uploads []messageUpload
+
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "multipart/form-data" {
+		// Folga para os cabeçalhos do multipart e o texto
+		r.Body = http.MaxBytesReader(w, r.Body, maxMessageAttachmentsSize+64<<10)
+		tooLarge := fmt.Sprintf("Os anexos devem somar no máximo %dMB", maxMessageAttachmentsSize>>20)
+
+		reader, err := r.MultipartReader()
+		if err != nil {
+			return "", nil, http.StatusBadRequest, "Dados inválidos"
+		}
+
+		var total int
+		for {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				var maxBytesErr *http.MaxBytesError
+				if errors.As(err, &maxBytesErr) {
+					return "", nil, http.StatusRequestEntityTooLarge, tooLarge
+				}
+				return "", nil, http.StatusBadRequest, "Dados inválidos"
+			}
+
+			switch part.FormName() {
+			case "body":
+				data, err := io.ReadAll(io.LimitReader(part, maxMessageLength*4+1))
+				part.Close()
+				if err != nil {
+					return "", nil, http.StatusBadRequest, "Dados inválidos"
+				}
+				body = string(data)
+			case "file":
+				if len(uploads) == maxMessageAttachments {
+					part.Close()
+					return "", nil, http.StatusBadRequest, fmt.Sprintf("Envie no máximo %d anexos por mensagem", maxMessageAttachments)
+				}
+				data, err := io.ReadAll(io.LimitReader(part, int64(maxMessageAttachmentsSize-total+1)))
+				filename := part.FileName()
+				part.Close()
+				if err != nil {
+					var maxBytesErr *http.MaxBytesError
+					if errors.As(err, &maxBytesErr) {
+						return "", nil, http.StatusRequestEntityTooLarge, tooLarge
+					}
+					return "", nil, http.StatusBadRequest, "Dados inválidos"
+				}
+				total += len(data)
+				if total > maxMessageAttachmentsSize {
+					return "", nil, http.StatusRequestEntityTooLarge, tooLarge
+				}
+				if len(data) == 0 {
+					return "", nil, http.StatusBadRequest, "Anexo vazio"
+				}
+
+				contentType, ext, ok := detectAttachmentType(filename, data)
+				if !ok {
+					return "", nil, http.StatusBadRequest, "Tipo de anexo não permitido. Use PDF, DOCX, PNG, JPEG ou TXT"
+				}
+				uploads = append(uploads, messageUpload{
+					filename:    sanitizeAttachmentFilename(filename, ext),
+					contentType: contentType,
+					ext:         ext,
+					data:        data,
+				})
+			default:
+				part.Close()
+			}
+		}
+	} else {
+		var req struct {
+			Body string `json:"body"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			return "", nil, http.StatusBadRequest, "Dados inválidos"
+		}
+		body = req.Body
+	}
+
+	body = strings.TrimSpace(body)
+	if !utf8.ValidString(body) {
+		return "", nil, http.StatusBadRequest, "Dados inválidos"
+	}
+	if utf8.RuneCountInString(body) > maxMessageLength {
+		return "", nil, http.StatusBadRequest, fmt.Sprintf("A mensagem deve ter no máximo %d caracteres", maxMessageLength)
+	}
+	if body == "" && len(uploads) == 0 {
+		return "", nil, http.StatusBadRequest, "Escreva uma mensagem ou envie um anexo"
+	}
+
+	return body, uploads, 0, ""
+}
+
+// detectAttachmentType identifica o tipo do anexo pelo conteúdo e retorna o Content-Type e a extensão
+func detectAttachmentType(filename string, data []byte) (string, string, bool) {
+	detected, _, _ := mime.ParseMediaType(http.DetectContentType(data))
+	if detected == "application/zip" && strings.EqualFold(filepath.Ext(filename), ".docx") {
+		return docxContentType, ".docx", true
+	}
+	ext, ok := messageAttachmentTypes[detected]
+	return detected, ext, ok
+}
+
+// sanitizeAttachmentFilename mantém só o nome do arquivo (sem caminho nem caracteres de controle),
+// limitado a maxAttachmentFilename caracteres
+func sanitizeAttachmentFilename(filename, ext string) string {
+	if i := strings.LastIndexAny(filename, `/\`); i >= 0 {
+		filename = filename[i+1:]
+	}
+	filename = strings.TrimSpace(strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == '"' {
+			return -1
+		}
+		return r
+	}, filename))
+
+	if runes := []rune(filename); len(runes) > maxAttachmentFilename {
+		filename = string(runes[len(runes)-maxAttachmentFilename:])
+	}
+	if filename == "" || filename == "." || filename == ".." {
+		filename = "anexo" + ext
+	}
+	return filename
+}
@@ -0,0 +1,244 @@
+package handlers
+
+import (
+	"context"
+	"empregabemapi/internal/middleware"
+	"empregabemapi/internal/models"
+	"empregabemapi/internal/notifications"
+	"empregabemapi/internal/repository"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type NotificationsHandler struct {
+	repo     *repository.NotificationsRepository
+	notifier *notifications.Service
+}
+
+func NewNotificationsHandler(repo *repository.NotificationsRepository, notifier *notifications.Service) *NotificationsHandler {
+	return &NotificationsHandler{
+		repo:     repo,
+		notifier: notifier,
+	}
+}
+
+// notificationRecipient identifica o dono da central de notificações a partir do token:
+// candidato, membro da equipe da empresa ou o dono da empresa. Administradores não têm central.
+func notificationRecipient(r *http.Request) (string, bson.ObjectID, bool) {
+	userType, _ := r.Context().Value(middleware.UserTypeKey).(string)
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var recipientType, recipientID string
+	switch userType {
+	case "candidate":
+		recipientType, recipientID = models.RecipientCandidate, userID
+	case "company":
+		recipientType, recipientID = models.RecipientCompany, userID
+		if memberID := middleware.CompanyMemberID(r); memberID != "" {
+			recipientType, recipientID = models.RecipientMember, memberID
+		}
+	default:
+		return "", bson.ObjectID{}, false
+	}
+
+	objID, err := bson.ObjectIDFromHex(recipientID)
+	if err != nil {
+		return "", bson.ObjectID{}, false
+	}
+	return recipientType, objID, true
+}
+
+// List retorna as notificações do usuário, mais recentes primeiro (GET /notifications).
+// Filtros: ?unread=true, ?limit= e ?before={id} para paginar.
+func (h *NotificationsHandler) List(w http.ResponseWriter, r *http.Request) {
+	recipientType, recipientID, ok := notificationRecipient(r)
+	if !ok {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Notificações disponíveis apenas para candidatos e empresas",
+		})
+		return
+	}
+
+	query := r.URL.Query()
+	limit := int64(20)
+	if l, err := strconv.ParseInt(query.Get("limit"), 10, 64); err == nil && l > 0 && l <= 100 {
+		limit = l
+	}
+
+	var before bson.ObjectID
+	if b := query.Get("before"); b != "" {
+		var err error
+		if before, err = bson.ObjectIDFromHex(b); err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "Parâmetro before inválido",
+			})
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	list, err := h.repo.List(ctx, recipientType, recipientID, query.Get("unread") == "true", before, limit)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao buscar notificações",
+		})
+		return
+	}
+	if list == nil {
+		list = []*models.Notification{}
+	}
+
+	unread, err := h.repo.CountUnread(ctx, recipientType, recipientID)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao contar notificações não lidas",
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"notificacoes": list,
+		"nao_lidas":    unread,
+	})
+}
+
+// UnreadCount retorna apenas o número de notificações não lidas, para o badge do frontend
+// (GET /notifications/unread-count)
+func (h *NotificationsHandler) UnreadCount(w http.ResponseWriter, r *http.Request) {
+	recipientType, recipientID, ok := notificationRecipient(r)
+	if !ok {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Notificações disponíveis apenas para candidatos e empresas",
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	unread, err := h.repo.CountUnread(ctx, recipientType, recipientID)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao contar notificações não lidas",
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"nao_lidas": unread,
+	})
+}
+
+// MarkRead marca uma notificação como lida (POST /notifications/{id}/read)
+func (h *NotificationsHandler) MarkRead(w http.ResponseWriter, r *http.Request) {
+	recipientType, recipientID, ok := notificationRecipient(r)
+	if !ok {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Notificações disponíveis apenas para candidatos e empresas",
+		})
+		return
+	}
+
+	notificationID := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/notifications/"), "/read")
+	objID, err := bson.ObjectIDFromHex(notificationID)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "ID da notificação inválido",
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	found, err := h.repo.MarkRead(ctx, objID, recipientType, recipientID)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao marcar notificação como lida",
+		})
+		return
+	}
+	if !found {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Notificação não encontrada",
+		})
+		return
+	}
+
+	// Outras abas e dispositivos do usuário atualizam o contador
+	h.notifier.UnreadCountChanged(ctx, recipientType, recipientID)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{
+		"mensagem": "Notificação marcada como lida",
+	})
+}
+
+// MarkAllRead marca todas as notificações do usuário como lidas (POST /notifications/read-all)
+func (h *NotificationsHandler) MarkAllRead(w http.ResponseWriter, r *http.Request) {
+	recipientType, recipientID, ok := notificationRecipient(r)
+	if !ok {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Notificações disponíveis apenas para candidatos e empresas",
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	marked, err := h.repo.MarkAllRead(ctx, recipientType, recipientID)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao marcar notificações como lidas",
+		})
+		return
+	}
+
+	if marked > 0 {
+		h.notifier.UnreadCountChanged(ctx, recipientType, recipientID)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"mensagem": "Notificações marcadas como lidas",
+		"marcadas": marked,
+	})
+}
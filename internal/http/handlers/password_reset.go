@@ -5,10 +5,15 @@ import (
 	"empregabemapi/candidates"
 	"empregabemapi/companies"
 	"empregabemapi/internal/auth"
+	"empregabemapi/internal/lockout"
+	"empregabemapi/internal/mail"
+	"empregabemapi/internal/middleware"
 	"empregabemapi/internal/models"
 	"empregabemapi/internal/repository"
 	"encoding/json"
+	"log"
 	"net/http"
+	"net/url"
 	"time"
 
 	"go.mongodb.org/mongo-driver/v2/mongo"
@@ -17,18 +22,27 @@ import (
 type PasswordResetHandler struct {
 	resetRepo     *repository.PasswordResetRepository
 	companyRepo   *companies.MongoRepository
+	memberRepo    *companies.MemberRepository
 	candidateRepo *candidates.MongoRepository
+	mailer        *mail.Mailer
+	lockout       *lockout.Service
 }
 
 func NewPasswordResetHandler(
 	resetRepo *repository.PasswordResetRepository,
 	companyRepo *companies.MongoRepository,
+	memberRepo *companies.MemberRepository,
 	candidateRepo *candidates.MongoRepository,
+	mailer *mail.Mailer,
+	lockoutService *lockout.Service,
 ) *PasswordResetHandler {
 	return &PasswordResetHandler{
 		resetRepo:     resetRepo,
 		companyRepo:   companyRepo,
+		memberRepo:    memberRepo,
 		candidateRepo: candidateRepo,
+		mailer:        mailer,
+		lockout:       lockoutService,
 	}
 }
 
@@ -42,7 +56,7 @@ type ResetPasswordRequest struct {
 	NewPassword string `json:"new_password"`
 }
 
-// RequestReset solicita reset de senha (envia "email" com token)
+// RequestReset solicita reset de senha (envia email com link contendo o token)
 func (h *PasswordResetHandler) RequestReset(w http.ResponseWriter, r *http.Request) {
 	var req RequestResetRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -81,21 +95,30 @@ func (h *PasswordResetHandler) RequestReset(w http.ResponseWriter, r *http.Reque
 
 	// Verificar se usuário existe (sem revelar se existe ou não por segurança)
 	var userExists bool
+	var userName string
 	if req.UserType == "company" {
-		_, err := h.companyRepo.GetByEmail(ctx, req.Email)
-		userExists = err == nil
+		company, err := h.companyRepo.GetByEmail(ctx, req.Email)
+		if userExists = err == nil; userExists {
+			userName = company.Name
+		} else if member, err := h.memberRepo.GetByEmail(ctx, req.Email); err == nil {
+			// Membros de equipe também entram por /company/login
+			userExists = true
+			userName = member.Name
+		}
 	} else {
-		_, err := h.candidateRepo.GetByEmail(ctx, req.Email)
-		userExists = err == nil
+		candidate, err := h.candidateRepo.GetByEmail(ctx, req.Email)
+		if userExists = err == nil; userExists {
+			userName = candidate.Name
+		}
 	}
 
 	// IMPORTANTE: Sempre retornar sucesso mesmo se usuário não existir
 	// Isso previne enumeration attacks (descobrir emails cadastrados)
 	if userExists {
-		// Gerar token de reset
-		jwtToken, uniqueToken, err := auth.GeneratePasswordResetToken(req.Email, req.UserType)
+		// Gerar token opaco de reset (só o hash é salvo no banco)
+		token, tokenHash, err := auth.GenerateOpaqueToken()
 		if err != nil {
-			// Log do erro internamente, mas não revelar ao cliente
+			log.Printf("Erro ao gerar token de reset de senha: %v", err)
 			w.Header().Set("Content-Type", "application/json")
 			w.WriteHeader(http.StatusOK)
 			json.NewEncoder(w).Encode(map[string]string{
@@ -104,38 +127,32 @@ func (h *PasswordResetHandler) RequestReset(w http.ResponseWriter, r *http.Reque
 			return
 		}
 
-		// Hash do token único
-		tokenHash, err := auth.GenerateSecureToken(32)
-		if err != nil {
-			tokenHash = uniqueToken // fallback
+		// Apenas o link mais recente deve funcionar
+		if err := h.resetRepo.InvalidateAllForUser(ctx, req.Email, req.UserType); err != nil {
+			log.Printf("Erro ao invalidar tokens anteriores de reset: %v", err)
 		}
 
-		// Salvar token no banco
+		// Salvar hash do token no banco
 		reset := &models.PasswordReset{
 			Email:     req.Email,
 			UserType:  req.UserType,
-			Token:     jwtToken,
 			TokenHash: tokenHash,
-			ExpiresAt: time.Now().Add(15 * time.Minute),
+			ExpiresAt: time.Now().Add(auth.PasswordResetTTL),
 		}
 
 		if err := h.resetRepo.Create(ctx, reset); err != nil {
-			// Log erro mas não revelar
+			log.Printf("Erro ao salvar token de reset de senha: %v", err)
+		} else {
+			// Enviar email com link de reset (o envio real é feito pelo worker do outbox)
+			err = h.mailer.Enqueue(ctx, req.Email, mail.TemplatePasswordReset, map[string]interface{}{
+				"Name":             userName,
+				"ResetURL":         h.mailer.URL("/reset-password?token=" + url.QueryEscape(token)),
+				"ExpiresInMinutes": int(auth.PasswordResetTTL.Minutes()),
+			})
+			if err != nil {
+				log.Printf("Erro ao enfileirar email de reset de senha: %v", err)
+			}
 		}
-
-		// AQUI: Em produção, enviar email com link de reset
-		// Link seria: https://seusite.com/reset-password?token={jwtToken}
-
-		// Para desenvolvimento, retornar o token (REMOVER EM PRODUÇÃO!)
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"mensagem": "Se o email existir, você receberá instruções para redefinir sua senha.",
-			// APENAS PARA DESENVOLVIMENTO - REMOVER EM PRODUÇÃO:
-			"dev_token": jwtToken,
-			"dev_note":  "Em produção, o token seria enviado por email",
-		})
-		return
 	}
 
 	// Resposta genérica (não revela se email existe)
@@ -181,9 +198,8 @@ func (h *PasswordResetHandler) ResetPassword(w http.ResponseWriter, r *http.Requ
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	// Validar token JWT
-	claims, err := auth.ValidatePasswordResetToken(req.Token)
-	if err != nil {
+	// Validar formato do token antes de consultar o banco
+	if err := auth.ValidateOpaqueToken(req.Token); err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusUnauthorized)
 		json.NewEncoder(w).Encode(map[string]string{
@@ -192,8 +208,8 @@ func (h *PasswordResetHandler) ResetPassword(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	// Verificar se token não foi usado
-	reset, err := h.resetRepo.GetByToken(ctx, claims.TokenHash)
+	// Buscar token pelo hash (só tokens não usados e não expirados)
+	reset, err := h.resetRepo.GetByToken(ctx, auth.HashToken(req.Token))
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			w.Header().Set("Content-Type", "application/json")
@@ -211,6 +227,25 @@ func (h *PasswordResetHandler) ResetPassword(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	// Marcar token como usado ANTES de trocar a senha: garante uso único
+	// mesmo com requisições concorrentes usando o mesmo link
+	if err := h.resetRepo.MarkAsUsed(ctx, reset.ID); err != nil {
+		if err == repository.ErrResetTokenUsed {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "Token inválido, expirado ou já utilizado",
+			})
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao verificar token",
+		})
+		return
+	}
+
 	// Hash da nova senha
 	hashedPassword, err := auth.HashPassword(req.NewPassword)
 	if err != nil {
@@ -223,22 +258,16 @@ func (h *PasswordResetHandler) ResetPassword(w http.ResponseWriter, r *http.Requ
 	}
 
 	// Atualizar senha no banco correto
-	if claims.UserType == "company" {
-		company, err := h.companyRepo.GetByEmail(ctx, claims.Email)
-		if err != nil {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusNotFound)
-			json.NewEncoder(w).Encode(map[string]string{
-				"erro": "Usuário não encontrado",
-			})
-			return
-		}
-
-		company.Password = hashedPassword
-		company.UpdatedAt = time.Now()
-
-		if err := h.companyRepo.Update(ctx, company); err != nil {
+	if reset.UserType == "company" {
+		if err := h.resetCompanyPassword(ctx, reset.Email, hashedPassword); err != nil {
 			w.Header().Set("Content-Type", "application/json")
+			if err == mongo.ErrNoDocuments {
+				w.WriteHeader(http.StatusNotFound)
+				json.NewEncoder(w).Encode(map[string]string{
+					"erro": "Usuário não encontrado",
+				})
+				return
+			}
 			w.WriteHeader(http.StatusInternalServerError)
 			json.NewEncoder(w).Encode(map[string]string{
 				"erro": "Erro ao atualizar senha",
@@ -246,7 +275,7 @@ func (h *PasswordResetHandler) ResetPassword(w http.ResponseWriter, r *http.Requ
 			return
 		}
 	} else {
-		candidate, err := h.candidateRepo.GetByEmail(ctx, claims.Email)
+		candidate, err := h.candidateRepo.GetByEmail(ctx, reset.Email)
 		if err != nil {
 			w.Header().Set("Content-Type", "application/json")
 			w.WriteHeader(http.StatusNotFound)
@@ -256,8 +285,10 @@ func (h *PasswordResetHandler) ResetPassword(w http.ResponseWriter, r *http.Requ
 			return
 		}
 
+		// Redefinir a senha encerra todas as sessões existentes
+		now := time.Now()
 		candidate.Password = hashedPassword
-		candidate.UpdatedAt = time.Now()
+		candidate.TokensValidAfter = &now
 
 		if err := h.candidateRepo.Update(ctx, candidate); err != nil {
 			w.Header().Set("Content-Type", "application/json")
@@ -269,13 +300,13 @@ func (h *PasswordResetHandler) ResetPassword(w http.ResponseWriter, r *http.Requ
 		}
 	}
 
-	// Marcar token como usado
-	if err := h.resetRepo.MarkAsUsed(ctx, reset.ID); err != nil {
-		// Log erro mas continuar (senha já foi alterada)
+	// Invalidar todos os outros tokens deste usuário
+	if err := h.resetRepo.InvalidateAllForUser(ctx, reset.Email, reset.UserType); err != nil {
+		log.Printf("Erro ao invalidar tokens de reset após troca de senha: %v", err)
 	}
 
-	// Invalidar todos os outros tokens deste usuário
-	h.resetRepo.InvalidateAllForUser(ctx, claims.Email, claims.UserType)
+	// Quem provou acesso ao email pode voltar a fazer login imediatamente
+	h.lockout.Unlock(ctx, reset.UserType, reset.Email, middleware.ClientIP(r))
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
@@ -283,3 +314,27 @@ func (h *PasswordResetHandler) ResetPassword(w http.ResponseWriter, r *http.Requ
 		"mensagem": "Senha alterada com sucesso! Faça login com a nova senha.",
 	})
 }
+
+// resetCompanyPassword redefine a senha do dono da empresa ou, se o email não for de uma
+// empresa, do membro de equipe. Redefinir a senha encerra todas as sessões existentes.
+func (h *PasswordResetHandler) resetCompanyPassword(ctx context.Context, email, hashedPassword string) error {
+	now := time.Now()
+
+	company, err := h.companyRepo.GetByEmail(ctx, email)
+	if err == nil {
+		company.Password = hashedPassword
+		company.TokensValidAfter = &now
+		return h.companyRepo.Update(ctx, company)
+	}
+	if err != mongo.ErrNoDocuments {
+		return err
+	}
+
+	member, err := h.memberRepo.GetByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+	member.Password = hashedPassword
+	member.TokensValidAfter = &now
+	return h.memberRepo.Update(ctx, member)
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"context"
+	"empregabemapi/candidates"
+	"empregabemapi/internal/middleware"
+	"empregabemapi/internal/recommendations"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type RecommendationsHandler struct {
+	service       *recommendations.Service
+	candidateRepo *candidates.MongoRepository
+}
+
+func NewRecommendationsHandler(service *recommendations.Service, candidateRepo *candidates.MongoRepository) *RecommendationsHandler {
+	return &RecommendationsHandler{
+		service:       service,
+		candidateRepo: candidateRepo,
+	}
+}
+
+// List retorna uma página das vagas recomendadas ao candidato
+// (GET /candidate/recommendations?limit=20&offset=0)
+func (h *RecommendationsHandler) List(w http.ResponseWriter, r *http.Request) {
+	candidateID := r.Context().Value(middleware.UserIDKey).(string)
+
+	query := r.URL.Query()
+	limit := 20
+	if l, err := strconv.Atoi(query.Get("limit")); err == nil && l > 0 && l <= 50 {
+		limit = l
+	}
+	offset := 0
+	if value := query.Get("offset"); value != "" {
+		o, err := strconv.Atoi(value)
+		if err != nil || o < 0 {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "offset inválido",
+			})
+			return
+		}
+		offset = o
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	candidate, err := h.candidateRepo.GetByID(ctx, candidateID)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Candidato não encontrado",
+		})
+		return
+	}
+
+	ranked, err := h.service.ForCandidate(ctx, candidate)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao calcular recomendações",
+		})
+		return
+	}
+
+	page := []recommendations.Recommendation{}
+	if offset < len(ranked) {
+		page = ranked[offset:min(offset+limit, len(ranked))]
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"recomendacoes": page,
+		"total":         len(ranked),
+		"tem_mais":      offset+len(page) < len(ranked),
+	})
+}
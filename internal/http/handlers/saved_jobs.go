@@ -3,6 +3,7 @@ package handlers
 import (
 	"context"
 	"empregabemapi/internal/middleware"
+	"empregabemapi/internal/recommendations"
 	"empregabemapi/internal/repository"
 	"empregabemapi/jobs"
 	"encoding/json"
@@ -16,12 +17,14 @@ import (
 type SavedJobsHandler struct {
 	savedJobsRepo *repository.SavedJobsRepository
 	jobsRepo      *jobs.MongoRepository
+	recommender   *recommendations.Service
 }
 
-func NewSavedJobsHandler(savedJobsRepo *repository.SavedJobsRepository, jobsRepo *jobs.MongoRepository) *SavedJobsHandler {
+func NewSavedJobsHandler(savedJobsRepo *repository.SavedJobsRepository, jobsRepo *jobs.MongoRepository, recommender *recommendations.Service) *SavedJobsHandler {
 	return &SavedJobsHandler{
 		savedJobsRepo: savedJobsRepo,
 		jobsRepo:      jobsRepo,
+		recommender:   recommender,
 	}
 }
 
@@ -89,6 +92,9 @@ func (h *SavedJobsHandler) SaveJob(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// As vagas salvas entram no cálculo das recomendações
+	h.recommender.Invalidate(candidateID)
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(map[string]string{"message": "Vaga salva nos favoritos"})
@@ -136,6 +142,8 @@ func (h *SavedJobsHandler) UnsaveJob(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.recommender.Invalidate(candidateID)
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]string{"message": "Vaga removida dos favoritos"})
 }
@@ -0,0 +1,426 @@
+package handlers
+
+import (
+	"context"
+	"empregabemapi/internal/auth"
+	"empregabemapi/internal/middleware"
+	"empregabemapi/internal/models"
+	"empregabemapi/internal/repository"
+	"empregabemapi/jobs"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// SavedSearchesHandler gerencia as buscas salvas dos candidatos e o descadastro dos alertas
+type SavedSearchesHandler struct {
+	repo    *repository.SavedSearchesRepository
+	jobRepo *jobs.MongoRepository
+}
+
+func NewSavedSearchesHandler(repo *repository.SavedSearchesRepository, jobRepo *jobs.MongoRepository) *SavedSearchesHandler {
+	return &SavedSearchesHandler{
+		repo:    repo,
+		jobRepo: jobRepo,
+	}
+}
+
+type SavedSearchRequest struct {
+	Name      string             `json:"name"`
+	Filters   jobs.SearchFilters `json:"filters"`
+	Frequency string             `json:"frequency"` // padrão: daily
+	Channels  []string           `json:"channels"`  // padrão: email e in_app
+	// Liga/desliga os alertas (padrão: ligados na criação, mantido na alteração)
+	AlertsEnabled *bool `json:"alerts_enabled"`
+}
+
+// validate normaliza a requisição e retorna a mensagem de erro (vazia se válida)
+func (req *SavedSearchRequest) validate() string {
+	req.Name = strings.TrimSpace(req.Name)
+	req.Filters.Query = strings.TrimSpace(req.Filters.Query)
+	req.Filters.Location = strings.TrimSpace(req.Filters.Location)
+	req.Filters.JobType = strings.TrimSpace(req.Filters.JobType)
+	req.Filters.Level = strings.TrimSpace(req.Filters.Level)
+	req.Frequency = strings.TrimSpace(req.Frequency)
+
+	if req.Name == "" || utf8.RuneCountInString(req.Name) > 100 {
+		return "Informe um nome para a busca (até 100 caracteres)"
+	}
+	if req.Filters.IsEmpty() {
+		return "Informe ao menos um filtro ou texto de busca em filters"
+	}
+	if req.Filters.MinSalary < 0 {
+		return "min_salary não pode ser negativo"
+	}
+	for _, value := range []string{req.Filters.Query, req.Filters.Location, req.Filters.JobType, req.Filters.Level} {
+		if utf8.RuneCountInString(value) > 100 {
+			return "Cada filtro deve ter no máximo 100 caracteres"
+		}
+	}
+
+	if req.Frequency == "" {
+		req.Frequency = models.AlertFrequencyDaily
+	}
+	if !models.ValidAlertFrequency(req.Frequency) {
+		return "Frequência inválida. Use: instant, daily, weekly"
+	}
+
+	if len(req.Channels) == 0 {
+		req.Channels = []string{models.AlertChannelEmail, models.AlertChannelInApp}
+	}
+	channels := make([]string, 0, len(req.Channels))
+	for _, channel := range req.Channels {
+		channel = strings.TrimSpace(channel)
+		if !models.ValidAlertChannel(channel) {
+			return "Canal inválido: " + channel + ". Use: email, in_app"
+		}
+		if !containsString(channels, channel) {
+			channels = append(channels, channel)
+		}
+	}
+	req.Channels = channels
+
+	return ""
+}
+
+// apply copia a requisição para a busca. Mudando os filtros ou a frequência, as vagas guardadas
+// para o resumo são descartadas e o próximo resumo é reagendado.
+func (req *SavedSearchRequest) apply(search *models.SavedSearch) {
+	enabled := search.AlertsEnabled
+	if req.AlertsEnabled != nil {
+		enabled = *req.AlertsEnabled
+	}
+	reschedule := search.Filters != req.Filters || search.Frequency != req.Frequency || (enabled && !search.AlertsEnabled)
+
+	search.Name = req.Name
+	search.Filters = req.Filters
+	search.Frequency = req.Frequency
+	search.Channels = req.Channels
+	search.AlertsEnabled = enabled
+
+	if !enabled {
+		search.PendingJobIDs = nil
+		search.NextDigestAt = nil
+	} else if reschedule {
+		search.PendingJobIDs = nil
+		search.ScheduleDigest(time.Now())
+	}
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// loadSearch busca a busca salva do path garantindo que pertence ao candidato
+func (h *SavedSearchesHandler) loadSearch(ctx context.Context, w http.ResponseWriter, r *http.Request) (*models.SavedSearch, bool) {
+	candidateID := r.Context().Value(middleware.UserIDKey).(string)
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/candidate/saved-searches/"), "/"), "/")
+
+	var search *models.SavedSearch
+	id, err := bson.ObjectIDFromHex(parts[0])
+	if err == nil {
+		search, err = h.repo.GetByID(ctx, id)
+	}
+	if err == nil && search.CandidateID.Hex() != candidateID {
+		err = mongo.ErrNoDocuments
+	}
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		if errors.Is(err, mongo.ErrNoDocuments) || errors.Is(err, bson.ErrInvalidHex) {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "Busca salva não encontrada",
+			})
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "Erro ao buscar busca salva",
+			})
+		}
+		return nil, false
+	}
+
+	return search, true
+}
+
+// List retorna as buscas salvas do candidato (GET /candidate/saved-searches)
+func (h *SavedSearchesHandler) List(w http.ResponseWriter, r *http.Request) {
+	candidateID := r.Context().Value(middleware.UserIDKey).(string)
+	candidateObjID, _ := bson.ObjectIDFromHex(candidateID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	searches, err := h.repo.ListByCandidate(ctx, candidateObjID)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao buscar buscas salvas",
+		})
+		return
+	}
+	if searches == nil {
+		searches = []*models.SavedSearch{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"buscas": searches,
+	})
+}
+
+// Create salva uma busca com alertas de vagas novas (POST /candidate/saved-searches)
+func (h *SavedSearchesHandler) Create(w http.ResponseWriter, r *http.Request) {
+	candidateID := r.Context().Value(middleware.UserIDKey).(string)
+	candidateObjID, _ := bson.ObjectIDFromHex(candidateID)
+
+	var req SavedSearchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Dados inválidos",
+		})
+		return
+	}
+	if msg := req.validate(); msg != "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": msg,
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	count, err := h.repo.CountByCandidate(ctx, candidateObjID)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao verificar buscas salvas",
+		})
+		return
+	}
+	if count >= models.MaxSavedSearches {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Limite de " + strconv.Itoa(models.MaxSavedSearches) + " buscas salvas atingido",
+		})
+		return
+	}
+
+	search := &models.SavedSearch{CandidateID: candidateObjID, AlertsEnabled: true}
+	req.apply(search)
+
+	if err := h.repo.Create(ctx, search); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao salvar busca",
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(search)
+}
+
+// Get retorna uma busca salva (GET /candidate/saved-searches/{id})
+func (h *SavedSearchesHandler) Get(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	search, ok := h.loadSearch(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(search)
+}
+
+// Update substitui nome, filtros, frequência e canais da busca (PUT /candidate/saved-searches/{id})
+func (h *SavedSearchesHandler) Update(w http.ResponseWriter, r *http.Request) {
+	var req SavedSearchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Dados inválidos",
+		})
+		return
+	}
+	if msg := req.validate(); msg != "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": msg,
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	search, ok := h.loadSearch(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	req.apply(search)
+	if err := h.repo.Update(ctx, search); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao atualizar busca salva",
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(search)
+}
+
+// Delete remove a busca salva e seus alertas (DELETE /candidate/saved-searches/{id})
+func (h *SavedSearchesHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	search, ok := h.loadSearch(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	if _, err := h.repo.Delete(ctx, search.ID, search.CandidateID); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao remover busca salva",
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{
+		"mensagem": "Busca salva removida",
+	})
+}
+
+// Jobs executa a busca salva agora, com os mesmos resultados de GET /jobs
+// (GET /candidate/saved-searches/{id}/jobs)
+func (h *SavedSearchesHandler) Jobs(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	search, ok := h.loadSearch(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	jobsList, err := h.jobRepo.Search(ctx, search.Filters)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao buscar vagas",
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"busca": search,
+		"vagas": jobsList,
+	})
+}
+
+type UnsubscribeAlertRequest struct {
+	Token string `json:"token"`
+}
+
+// Unsubscribe desliga os alertas de uma busca pelo link assinado enviado nos emails, sem login
+// (POST /alerts/unsubscribe)
+func (h *SavedSearchesHandler) Unsubscribe(w http.ResponseWriter, r *http.Request) {
+	var req UnsubscribeAlertRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Dados inválidos",
+		})
+		return
+	}
+
+	var id bson.ObjectID
+	value, err := auth.VerifyLink(auth.LinkPurposeAlertUnsubscribe, strings.TrimSpace(req.Token))
+	if err == nil {
+		id, err = bson.ObjectIDFromHex(value)
+	}
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Link de descadastro inválido",
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	search, err := h.repo.GetByID(ctx, id)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "Busca salva não encontrada (ela pode ter sido removida)",
+			})
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "Erro ao buscar busca salva",
+			})
+		}
+		return
+	}
+
+	if err := h.repo.DisableAlerts(ctx, search.ID); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao desligar alertas",
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{
+		"mensagem": "Você não receberá mais alertas desta busca",
+		"busca":    search.Name,
+	})
+}
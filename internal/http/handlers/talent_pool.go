@@ -0,0 +1,627 @@
+package handlers
+
+import (
+	"context"
+	"empregabemapi/candidates"
+	"empregabemapi/companies"
+	"empregabemapi/internal/middleware"
+	"empregabemapi/internal/models"
+	"empregabemapi/internal/notifications"
+	"empregabemapi/internal/repository"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// TalentPoolHandler cuida do banco de talentos: o candidato escolhe se o perfil aparece para
+// empresas verificadas, as empresas buscam e pedem contato, e os contatos só são liberados
+// quando o candidato aceita
+type TalentPoolHandler struct {
+	repo          *repository.TalentPoolRepository
+	candidateRepo *candidates.MongoRepository
+	companyRepo   *companies.MongoRepository
+	notifier      *notifications.Service
+}
+
+func NewTalentPoolHandler(repo *repository.TalentPoolRepository, candidateRepo *candidates.MongoRepository, companyRepo *companies.MongoRepository, notifier *notifications.Service) *TalentPoolHandler {
+	return &TalentPoolHandler{
+		repo:          repo,
+		candidateRepo: candidateRepo,
+		companyRepo:   companyRepo,
+		notifier:      notifier,
+	}
+}
+
+// TalentPoolSettings são as preferências do candidato no banco de talentos
+type TalentPoolSettings struct {
+	Visibility   string   `json:"visibility"`
+	Availability string   `json:"availability"`
+	Languages    []string `json:"languages"`
+	Level        string   `json:"level,omitempty"` // calculado pelas experiências
+}
+
+func (req *TalentPoolSettings) validate() string {
+	if !candidates.ValidTalentVisibility(req.Visibility) {
+		return "visibility deve ser private, verified_companies ou public"
+	}
+	if !candidates.ValidAvailability(req.Availability) {
+		return "availability deve ser imediata, 15_dias, 30_dias ou aberto_a_propostas"
+	}
+	return ""
+}
+
+func talentPoolSettings(candidate *candidates.Candidate) TalentPoolSettings {
+	settings := TalentPoolSettings{
+		Visibility:   candidate.TalentVisibility,
+		Availability: candidate.Availability,
+		Languages:    candidate.Languages,
+		Level:        candidate.Level,
+	}
+	if settings.Visibility == "" {
+		settings.Visibility = candidates.TalentVisibilityPrivate
+	}
+	if settings.Languages == nil {
+		settings.Languages = []string{}
+	}
+	return settings
+}
+
+// TalentResult é o candidato exibido à empresa: perfil sem contatos e, se o candidato aceitou o
+// pedido da empresa, os contatos
+type TalentResult struct {
+	candidates.TalentProfile
+	Contact        *candidates.Contact    `json:"contact,omitempty"`
+	ContactRequest *models.ContactRequest `json:"contact_request,omitempty"` // último pedido da empresa (no detalhe)
+}
+
+// GetSettings retorna as preferências do candidato no banco de talentos (GET /candidate/talent-pool)
+func (h *TalentPoolHandler) GetSettings(w http.ResponseWriter, r *http.Request) {
+	candidateID := r.Context().Value(middleware.UserIDKey).(string)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	candidate, err := h.candidateRepo.GetByID(ctx, candidateID)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Candidato não encontrado",
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(talentPoolSettings(candidate))
+}
+
+// UpdateSettings altera a visibilidade, a disponibilidade e os idiomas (PUT /candidate/talent-pool)
+func (h *TalentPoolHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	candidateID := r.Context().Value(middleware.UserIDKey).(string)
+
+	var req TalentPoolSettings
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Dados inválidos",
+		})
+		return
+	}
+	if msg := req.validate(); msg != "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": msg,
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	candidate, err := h.candidateRepo.GetByID(ctx, candidateID)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Candidato não encontrado",
+		})
+		return
+	}
+
+	candidate.TalentVisibility = req.Visibility
+	candidate.Availability = req.Availability
+	candidate.Languages = candidates.NormalizeLanguages(req.Languages)
+
+	if err := h.candidateRepo.Update(ctx, candidate); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao atualizar banco de talentos",
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"mensagem":          "Preferências do banco de talentos atualizadas",
+		"banco_de_talentos": talentPoolSettings(candidate),
+	})
+}
+
+// Activity retorna as buscas em que o candidato apareceu e as empresas que abriram o perfil
+// (GET /candidate/talent-pool/activity?limit=50)
+func (h *TalentPoolHandler) Activity(w http.ResponseWriter, r *http.Request) {
+	candidateID, _ := bson.ObjectIDFromHex(r.Context().Value(middleware.UserIDKey).(string))
+
+	limit := int64(50)
+	if l, err := strconv.ParseInt(r.URL.Query().Get("limit"), 10, 64); err == nil && l > 0 && l <= 200 {
+		limit = l
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	events, err := h.repo.ListEvents(ctx, candidateID, limit)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao buscar histórico",
+		})
+		return
+	}
+	if events == nil {
+		events = []*models.TalentEvent{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"historico": events,
+	})
+}
+
+// ListContactRequests lista os pedidos de contato recebidos pelo candidato ou enviados pela
+// empresa (GET /{candidate|company}/contact-requests?status=pending)
+func (h *TalentPoolHandler) ListContactRequests(side string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, _ := bson.ObjectIDFromHex(r.Context().Value(middleware.UserIDKey).(string))
+
+		status := r.URL.Query().Get("status")
+		if status != "" && status != models.ContactRequestPending && status != models.ContactRequestAccepted && status != models.ContactRequestDeclined {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "status deve ser pending, accepted ou declined",
+			})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		var requests []*models.ContactRequest
+		var err error
+		if side == models.RecipientCompany {
+			requests, err = h.repo.ListContactRequestsByCompany(ctx, userID, status)
+		} else {
+			requests, err = h.repo.ListContactRequestsByCandidate(ctx, userID, status)
+		}
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "Erro ao listar pedidos de contato",
+			})
+			return
+		}
+		if requests == nil {
+			requests = []*models.ContactRequest{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"pedidos": requests,
+		})
+	}
+}
+
+// RespondContactRequest aceita ou recusa um pedido de contato
+// (POST /candidate/contact-requests/{id}/accept|decline)
+func (h *TalentPoolHandler) RespondContactRequest(status string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		candidateID, _ := bson.ObjectIDFromHex(r.Context().Value(middleware.UserIDKey).(string))
+		parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/candidate/contact-requests/"), "/"), "/")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		var request *models.ContactRequest
+		id, err := bson.ObjectIDFromHex(parts[0])
+		if err == nil {
+			request, err = h.repo.GetContactRequest(ctx, id)
+		}
+		if err == nil && request.CandidateID != candidateID {
+			err = mongo.ErrNoDocuments
+		}
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			if errors.Is(err, mongo.ErrNoDocuments) || errors.Is(err, bson.ErrInvalidHex) {
+				w.WriteHeader(http.StatusNotFound)
+				json.NewEncoder(w).Encode(map[string]string{
+					"erro": "Pedido de contato não encontrado",
+				})
+			} else {
+				w.WriteHeader(http.StatusInternalServerError)
+				json.NewEncoder(w).Encode(map[string]string{
+					"erro": "Erro ao buscar pedido de contato",
+				})
+			}
+			return
+		}
+
+		// A atualização condicional impede responder duas vezes (inclusive em requisições simultâneas)
+		responded, err := h.repo.RespondContactRequest(ctx, request.ID, candidateID, status)
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "Erro ao responder pedido de contato",
+			})
+			return
+		}
+		if !responded {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "Este pedido de contato já foi respondido",
+			})
+			return
+		}
+
+		now := time.Now()
+		request.Status = status
+		request.RespondedAt = &now
+
+		message := "Pedido de contato recusado"
+		if status == models.ContactRequestAccepted {
+			message = "Contato aceito. A empresa já pode ver seus dados de contato"
+			h.notifier.ContactAccepted(ctx, request)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"mensagem": message,
+			"pedido":   request,
+		})
+	}
+}
+
+// verifiedCompany carrega a empresa autenticada e exige o cadastro verificado
+func (h *TalentPoolHandler) verifiedCompany(ctx context.Context, w http.ResponseWriter, r *http.Request) (*companies.Company, bool) {
+	companyID := r.Context().Value(middleware.UserIDKey).(string)
+
+	company, err := h.companyRepo.GetByID(ctx, companyID)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Empresa não encontrada",
+		})
+		return nil, false
+	}
+	if company.VerificationStatus != "verified" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "O banco de talentos é exclusivo para empresas verificadas",
+		})
+		return nil, false
+	}
+
+	return company, true
+}
+
+// loadTalent busca o candidato do path. A empresa vê candidatos do banco de talentos e, mesmo que
+// o candidato tenha saído dele, os que já aceitaram seu contato. latest é o último pedido da empresa.
+func (h *TalentPoolHandler) loadTalent(ctx context.Context, w http.ResponseWriter, r *http.Request, company *companies.Company) (candidate *candidates.Candidate, latest *models.ContactRequest, ok bool) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/company/talent/"), "/"), "/")
+
+	candidate, err := h.candidateRepo.GetByID(ctx, parts[0])
+	if err == nil {
+		latest, err = h.repo.LatestContactRequest(ctx, company.ID, candidate.ID)
+	}
+	if err == nil && !candidate.InTalentPool() && (candidate.SuspendedAt != nil || latest == nil || latest.Status != models.ContactRequestAccepted) {
+		err = mongo.ErrNoDocuments
+	}
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		if errors.Is(err, mongo.ErrNoDocuments) || errors.Is(err, bson.ErrInvalidHex) {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "Candidato não encontrado no banco de talentos",
+			})
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "Erro ao buscar candidato",
+			})
+		}
+		return nil, nil, false
+	}
+
+	return candidate, latest, true
+}
+
+// Search busca candidatos no banco de talentos
+// (GET /company/talent?skills=go,docker&languages=ingles&location=&level=&availability=&limit=20&offset=0)
+func (h *TalentPoolHandler) Search(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filters := candidates.TalentFilters{
+		Skills:       splitList(query.Get("skills")),
+		Languages:    splitList(query.Get("languages")),
+		Location:     strings.TrimSpace(query.Get("location")),
+		Level:        strings.TrimSpace(query.Get("level")),
+		Availability: splitList(query.Get("availability")),
+	}
+	if filters.Level != "" && filters.Level != candidates.LevelJunior && filters.Level != candidates.LevelPleno && filters.Level != candidates.LevelSenior {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "level deve ser junior, pleno ou senior",
+		})
+		return
+	}
+	for _, availability := range filters.Availability {
+		if !candidates.ValidAvailability(availability) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "availability deve ser imediata, 15_dias, 30_dias ou aberto_a_propostas",
+			})
+			return
+		}
+	}
+
+	limit := int64(20)
+	if l, err := strconv.ParseInt(query.Get("limit"), 10, 64); err == nil && l > 0 && l <= 50 {
+		limit = l
+	}
+	offset := int64(0)
+	if value := query.Get("offset"); value != "" {
+		o, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || o < 0 {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "offset inválido",
+			})
+			return
+		}
+		offset = o
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	company, ok := h.verifiedCompany(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	visibilities := []string{candidates.TalentVisibilityVerified, candidates.TalentVisibilityPublic}
+	found, total, err := h.candidateRepo.SearchTalent(ctx, filters, visibilities, limit, offset)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao buscar candidatos",
+		})
+		return
+	}
+
+	ids := make([]bson.ObjectID, 0, len(found))
+	for _, candidate := range found {
+		ids = append(ids, candidate.ID)
+	}
+	accepted, err := h.repo.AcceptedCandidates(ctx, company.ID, ids)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao buscar pedidos de contato",
+		})
+		return
+	}
+
+	results := make([]TalentResult, 0, len(found))
+	events := make([]*models.TalentEvent, 0, len(found))
+	summary := filters.Summary()
+	for _, candidate := range found {
+		result := TalentResult{TalentProfile: candidate.TalentProfile()}
+		if accepted[candidate.ID] {
+			contact := candidate.Contact()
+			result.Contact = &contact
+		}
+		results = append(results, result)
+		events = append(events, &models.TalentEvent{
+			CandidateID: candidate.ID,
+			CompanyID:   company.ID,
+			CompanyName: company.Name,
+			Type:        models.TalentEventSearch,
+			Query:       summary,
+		})
+	}
+
+	// O candidato vê no histórico as buscas em que apareceu
+	if err := h.repo.LogEvents(ctx, events); err != nil {
+		log.Printf("Erro ao registrar busca da empresa %s no banco de talentos: %v", company.ID.Hex(), err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"candidatos": results,
+		"total":      total,
+		"tem_mais":   offset+int64(len(results)) < total,
+	})
+}
+
+// View retorna o perfil do candidato para a empresa e registra a visualização (GET /company/talent/{id})
+func (h *TalentPoolHandler) View(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	company, ok := h.verifiedCompany(ctx, w, r)
+	if !ok {
+		return
+	}
+	candidate, latest, ok := h.loadTalent(ctx, w, r, company)
+	if !ok {
+		return
+	}
+
+	result := TalentResult{TalentProfile: candidate.TalentProfile(), ContactRequest: latest}
+	if latest != nil && latest.Status == models.ContactRequestAccepted {
+		contact := candidate.Contact()
+		result.Contact = &contact
+	}
+
+	err := h.repo.LogEvents(ctx, []*models.TalentEvent{{
+		CandidateID: candidate.ID,
+		CompanyID:   company.ID,
+		CompanyName: company.Name,
+		Type:        models.TalentEventView,
+	}})
+	if err != nil {
+		log.Printf("Erro ao registrar visualização do candidato %s pela empresa %s: %v", candidate.ID.Hex(), company.ID.Hex(), err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// RequestContact pede ao candidato a liberação dos contatos (POST /company/talent/{id}/contact)
+func (h *TalentPoolHandler) RequestContact(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Message string `json:"message"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Dados inválidos",
+		})
+		return
+	}
+	req.Message = strings.TrimSpace(req.Message)
+	if req.Message == "" || utf8.RuneCountInString(req.Message) > models.MaxContactMessageLength {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "message é obrigatória e deve ter até 1000 caracteres",
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	company, ok := h.verifiedCompany(ctx, w, r)
+	if !ok {
+		return
+	}
+	candidate, latest, ok := h.loadTalent(ctx, w, r, company)
+	if !ok {
+		return
+	}
+
+	if latest != nil {
+		conflict := ""
+		switch {
+		case latest.Status == models.ContactRequestPending:
+			conflict = "Já existe um pedido de contato aguardando resposta do candidato"
+		case latest.Status == models.ContactRequestAccepted:
+			conflict = "O candidato já aceitou o contato da empresa"
+		case latest.RespondedAt != nil && time.Since(*latest.RespondedAt) < models.ContactRequestCooldown:
+			conflict = "O candidato recusou um pedido recente. Tente novamente após " + latest.RespondedAt.Add(models.ContactRequestCooldown).Format("02/01/2006")
+		}
+		if conflict != "" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": conflict,
+			})
+			return
+		}
+	}
+
+	request := &models.ContactRequest{
+		CompanyID:     company.ID,
+		CandidateID:   candidate.ID,
+		CompanyName:   company.Name,
+		CandidateName: candidate.Name,
+		Message:       req.Message,
+	}
+	if memberID, err := bson.ObjectIDFromHex(middleware.CompanyMemberID(r)); err == nil {
+		request.MemberID = &memberID
+	}
+
+	if err := h.repo.CreateContactRequest(ctx, request); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Erro ao criar pedido de contato",
+		})
+		return
+	}
+
+	h.notifier.ContactRequested(ctx, request)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"mensagem": "Pedido de contato enviado",
+		"pedido":   request,
+	})
+}
+
+// PublicProfile retorna o perfil de quem escolheu a visibilidade pública (GET /talent/{id})
+func (h *TalentPoolHandler) PublicProfile(w http.ResponseWriter, r *http.Request) {
+	id := strings.Trim(strings.TrimPrefix(r.URL.Path, "/talent/"), "/")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	candidate, err := h.candidateRepo.GetByID(ctx, id)
+	if err != nil || candidate.SuspendedAt != nil || candidate.TalentVisibility != candidates.TalentVisibilityPublic {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": "Perfil não encontrado",
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(candidate.TalentProfile())
+}
+
+// splitList separa um parâmetro de lista separado por vírgulas, ignorando itens vazios
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -1,18 +1,43 @@
 package http
 
 import (
+	"context"
+	"empregabemapi/admins"
 	"empregabemapi/applications"
 	"empregabemapi/candidates"
 	"empregabemapi/companies"
+	"empregabemapi/internal/events"
 	"empregabemapi/internal/http/handlers"
+	"empregabemapi/internal/lockout"
+	"empregabemapi/internal/mail"
+	"empregabemapi/internal/media"
 	"empregabemapi/internal/middleware"
+	"empregabemapi/internal/models"
+	"empregabemapi/internal/notifications"
+	"empregabemapi/internal/ratelimit"
+	"empregabemapi/internal/recommendations"
 	"empregabemapi/internal/repository"
+	"empregabemapi/internal/storage"
+	"empregabemapi/internal/webhooks"
 	"empregabemapi/jobs"
+	"log"
 	"net/http"
+	"strings"
+	"time"
 
 	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
+// Limite por usuário (além do limite por IP) para envio de mensagens nas conversas das candidaturas
+var messagesRateLimit = ratelimit.Rule{Name: "messages", Limit: 30, Window: 10 * time.Minute}
+
+const messagesRateLimitMessage = "Você enviou muitas mensagens em pouco tempo. Aguarde alguns minutos."
+
+// Limite por usuário de pedidos de contato no banco de talentos (evita disparos em massa)
+var contactRequestsRateLimit = ratelimit.Rule{Name: "contact_requests", Limit: 50, Window: 24 * time.Hour}
+
+const contactRequestsRateLimitMessage = "Você enviou muitos pedidos de contato hoje. Tente novamente amanhã."
+
 func SetupRoutes(
 	companyRepo *companies.MongoRepository,
 	candidateRepo *candidates.MongoRepository,
@@ -20,6 +45,18 @@ func SetupRoutes(
 	appsRepo *applications.MongoRepository,
 	savedJobsRepo *repository.SavedJobsRepository,
 	db *mongo.Database,
+	mailer *mail.Mailer,
+	cnpjLookup companies.CNPJLookup,
+	mediaLibrary *media.Library,
+	notificationsRepo *repository.NotificationsRepository,
+	notifier *notifications.Service,
+	eventsHub *events.Hub,
+	webhooksRepo *repository.WebhooksRepository,
+	webhookDispatcher *webhooks.Dispatcher,
+	messagesRepo *repository.MessagesRepository,
+	attachmentStore storage.Store,
+	rateLimiter *ratelimit.Limiter,
+	savedSearchesRepo *repository.SavedSearchesRepository,
 ) *http.ServeMux {
 	mux := http.NewServeMux()
 
@@ -28,23 +65,83 @@ func SetupRoutes(
 	mux.HandleFunc("/health", healthHandler.Ping)
 	mux.HandleFunc("/api", healthHandler.Ping)
 
-	// Maintenance endpoint (temporary - remove in production)
-	maintenanceHandler := handlers.NewMaintenanceHandler(jobsRepo)
-	mux.HandleFunc("/maintenance/fix-counters", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method == http.MethodPost {
-			maintenanceHandler.FixJobCounters(w, r)
+	// Chaves públicas para outros serviços validarem os tokens (RS256/EdDSA)
+	jwksHandler := handlers.NewJWKSHandler()
+	mux.HandleFunc("/.well-known/jwks.json", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			jwksHandler.Keys(w, r)
 		} else {
 			http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
 		}
 	})
 
+	// Auditoria e bloqueio de login por conta/IP (persistidos no MongoDB)
+	auditRepo := repository.NewAuditRepository(db)
+	if err := auditRepo.EnsureIndexes(context.Background()); err != nil {
+		log.Println("Aviso: erro ao criar índices de audit_logs:", err)
+	}
+	loginAttemptsRepo := repository.NewLoginAttemptsRepository(db)
+	if err := loginAttemptsRepo.EnsureIndexes(context.Background()); err != nil {
+		log.Println("Aviso: erro ao criar índices de login_attempts:", err)
+	}
+	lockoutService := lockout.NewService(loginAttemptsRepo, auditRepo)
+
+	// Membros da equipe das empresas (recrutadores, gestores...) e convites
+	memberRepo := companies.NewMemberRepository(db)
+	if err := memberRepo.EnsureIndexes(context.Background()); err != nil {
+		log.Println("Aviso: erro ao criar índices de company_members:", err)
+	}
+
+	// Administradores da plataforma (back-office)
+	adminRepo := admins.NewMongoRepository(db)
+	if err := adminRepo.EnsureIndexes(context.Background()); err != nil {
+		log.Println("Aviso: erro ao criar índices de admins:", err)
+	}
+
 	// Authentication handlers (com verificação cruzada de emails)
-	companyAuthHandler := handlers.NewCompanyAuthHandler(companyRepo, candidateRepo)
-	candidateAuthHandler := handlers.NewCandidateAuthHandler(candidateRepo, companyRepo)
+	companyAuthHandler := handlers.NewCompanyAuthHandler(companyRepo, memberRepo, candidateRepo, lockoutService, cnpjLookup)
+	candidateAuthHandler := handlers.NewCandidateAuthHandler(candidateRepo, companyRepo, memberRepo, lockoutService)
 
 	// Password reset handler
 	resetRepo := repository.NewPasswordResetRepository(db)
-	passwordResetHandler := handlers.NewPasswordResetHandler(resetRepo, companyRepo, candidateRepo)
+	if err := resetRepo.EnsureIndexes(context.Background()); err != nil {
+		log.Println("Aviso: erro ao criar índices de password_resets:", err)
+	}
+	passwordResetHandler := handlers.NewPasswordResetHandler(resetRepo, companyRepo, memberRepo, candidateRepo, mailer, lockoutService)
+
+	// Sessões revogadas (troca/redefinição de senha) são rejeitadas pelo AuthMiddleware
+	middleware.SetSessionValidator(newSessionValidator(companyRepo, memberRepo, candidateRepo, adminRepo))
+
+	// Account handlers (empresas e candidatos)
+	emailChangeRepo := repository.NewEmailChangeRepository(db)
+	if err := emailChangeRepo.EnsureIndexes(context.Background()); err != nil {
+		log.Println("Aviso: erro ao criar índices de email_changes:", err)
+	}
+	accountHandler := handlers.NewAccountHandler(companyRepo, memberRepo, candidateRepo, emailChangeRepo, mailer)
+
+	mux.HandleFunc("/me/password", middleware.AuthMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			accountHandler.ChangePassword(w, r)
+		} else {
+			http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+		}
+	}))
+
+	mux.HandleFunc("/me/email", middleware.AuthMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			accountHandler.ChangeEmail(w, r)
+		} else {
+			http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+		}
+	}))
+
+	mux.HandleFunc("/auth/confirm-email", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			accountHandler.ConfirmEmailChange(w, r)
+		} else {
+			http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+		}
+	})
 
 	// Company authentication (public)
 	mux.HandleFunc("/company/register", func(w http.ResponseWriter, r *http.Request) {
@@ -105,8 +202,15 @@ func SetupRoutes(
 		}
 	})
 
+	// Denúncias de vagas feitas por candidatos
+	jobReportsRepo := repository.NewJobReportsRepository(db)
+	if err := jobReportsRepo.EnsureIndexes(context.Background()); err != nil {
+		log.Println("Aviso: erro ao criar índices de job_reports:", err)
+	}
+	jobReportsHandler := handlers.NewJobReportsHandler(jobReportsRepo, jobsRepo, auditRepo)
+
 	// Public jobs list (only active jobs)
-	jobsHandler := handlers.NewJobsHandler(jobsRepo)
+	jobsHandler := handlers.NewJobsHandler(jobsRepo, candidateRepo)
 	mux.HandleFunc("/jobs", func(w http.ResponseWriter, r *http.Request) {
 		if r.Method == http.MethodGet {
 			jobsHandler.List(w, r)
@@ -122,41 +226,197 @@ func SetupRoutes(
 			return
 		}
 
+		// POST /jobs/{id}/report (apenas candidatos)
+		if r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/report") {
+			middleware.CandidateOnly(jobReportsHandler.Report)(w, r)
+			return
+		}
+
+		// GET /jobs/{id}/similar
+		if r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/similar") {
+			jobsHandler.Similar(w, r)
+			return
+		}
+
+		// Candidatos logados recebem também a aderência à vaga
+		if r.Method == http.MethodGet {
+			middleware.OptionalAuth(jobsHandler.GetByID)(w, r)
+		} else {
+			http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+		}
+	})
+
+	// Avaliações de empresas por candidatos (moderadas e anonimizadas)
+	companyReviewsRepo := repository.NewCompanyReviewsRepository(db)
+	if err := companyReviewsRepo.EnsureIndexes(context.Background()); err != nil {
+		log.Println("Aviso: erro ao criar índices de company_reviews:", err)
+	}
+	companyReviewsHandler := handlers.NewCompanyReviewsHandler(companyReviewsRepo, companyRepo, appsRepo)
+
+	// Página pública da empresa (GET /companies/{id-ou-slug}) e avaliações
+	publicCompanyHandler := handlers.NewPublicCompanyHandler(companyRepo, jobsRepo, companyReviewsRepo)
+	mux.HandleFunc("/companies/", func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/reviews") {
+			if r.Method == http.MethodGet {
+				companyReviewsHandler.List(w, r)
+			} else if r.Method == http.MethodPost {
+				middleware.CandidateOnly(companyReviewsHandler.Create)(w, r)
+			} else {
+				http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+			}
+			return
+		}
+
 		if r.Method == http.MethodGet {
-			jobsHandler.GetByID(w, r)
+			publicCompanyHandler.GetProfile(w, r)
 		} else {
 			http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
 		}
 	})
 
-	// Company profile handlers
-	companyHandler := handlers.NewCompanyHandler(companyRepo)
-	mux.HandleFunc("/company/me", middleware.AuthMiddleware(middleware.CompanyOnly(func(w http.ResponseWriter, r *http.Request) {
+	// Company profile handlers (cada ação exige uma permissão do papel do usuário na empresa)
+	companyHandler := handlers.NewCompanyHandler(companyRepo, jobsRepo)
+	mux.HandleFunc("/company/me", middleware.AuthMiddleware(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method == http.MethodGet {
-			companyHandler.GetProfile(w, r)
+			middleware.CompanyPermission(companies.PermViewCompany, companyHandler.GetProfile)(w, r)
 		} else if r.Method == http.MethodPut {
-			companyHandler.UpdateProfile(w, r)
+			middleware.CompanyPermission(companies.PermEditCompany, companyHandler.UpdateProfile)(w, r)
 		} else {
 			http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
 		}
-	})))
+	}))
+
+	// Logo e capa enviados por upload (imagens processadas em variantes e servidas em /media)
+	companyMediaHandler := handlers.NewCompanyMediaHandler(companyRepo, mediaLibrary)
+	mux.HandleFunc("/company/me/logo", middleware.AuthMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			middleware.CompanyPermission(companies.PermEditCompany, companyMediaHandler.Upload(media.LogoSpec))(w, r)
+		} else if r.Method == http.MethodDelete {
+			middleware.CompanyPermission(companies.PermEditCompany, companyMediaHandler.Remove(media.LogoSpec))(w, r)
+		} else {
+			http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+		}
+	}))
+	mux.HandleFunc("/company/me/cover", middleware.AuthMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			middleware.CompanyPermission(companies.PermEditCompany, companyMediaHandler.Upload(media.CoverSpec))(w, r)
+		} else if r.Method == http.MethodDelete {
+			middleware.CompanyPermission(companies.PermEditCompany, companyMediaHandler.Remove(media.CoverSpec))(w, r)
+		} else {
+			http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+		}
+	}))
+
+	mediaHandler := handlers.NewMediaHandler(mediaLibrary)
+	mux.HandleFunc("/media/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			mediaHandler.Serve(w, r)
+		} else {
+			http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+		}
+	})
+
+	// Company team (membros e convites)
+	companyMembersHandler := handlers.NewCompanyMembersHandler(companyRepo, memberRepo, candidateRepo, jobsRepo, mailer)
+	mux.HandleFunc("/company/members", middleware.AuthMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			middleware.CompanyPermission(companies.PermViewCompany, companyMembersHandler.List)(w, r)
+		} else {
+			http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+		}
+	}))
+
+	mux.HandleFunc("/company/members/invitations", middleware.AuthMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			middleware.CompanyPermission(companies.PermManageMembers, companyMembersHandler.Invite)(w, r)
+		} else {
+			http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+		}
+	}))
+
+	mux.HandleFunc("/company/members/invitations/", middleware.AuthMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			middleware.CompanyPermission(companies.PermManageMembers, companyMembersHandler.RevokeInvitation)(w, r)
+		} else {
+			http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+		}
+	}))
+
+	mux.HandleFunc("/company/members/", middleware.AuthMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPatch {
+			middleware.CompanyPermission(companies.PermManageMembers, companyMembersHandler.UpdateRole)(w, r)
+		} else if r.Method == http.MethodDelete {
+			middleware.CompanyPermission(companies.PermManageMembers, companyMembersHandler.Remove)(w, r)
+		} else {
+			http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+		}
+	}))
+
+	// Aceite de convite (público: o convidado ainda não tem conta)
+	mux.HandleFunc("/company/invitations/accept", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			companyMembersHandler.AcceptInvitation(w, r)
+		} else {
+			http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+		}
+	})
+
+	// Webhooks da empresa (integração com ATS): endpoints, segredos e histórico de entregas
+	companyWebhooksHandler := handlers.NewCompanyWebhooksHandler(webhooksRepo, webhookDispatcher)
+	mux.HandleFunc("/company/webhooks", middleware.AuthMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			middleware.CompanyPermission(companies.PermManageWebhooks, companyWebhooksHandler.List)(w, r)
+		} else if r.Method == http.MethodPost {
+			middleware.CompanyPermission(companies.PermManageWebhooks, companyWebhooksHandler.Create)(w, r)
+		} else {
+			http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+		}
+	}))
+
+	mux.HandleFunc("/company/webhooks/", middleware.AuthMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/company/webhooks/"), "/"), "/")
+
+		switch {
+		// /company/webhooks/{id}
+		case len(parts) == 1 && r.Method == http.MethodGet:
+			middleware.CompanyPermission(companies.PermManageWebhooks, companyWebhooksHandler.Get)(w, r)
+		case len(parts) == 1 && r.Method == http.MethodPut:
+			middleware.CompanyPermission(companies.PermManageWebhooks, companyWebhooksHandler.Update)(w, r)
+		case len(parts) == 1 && r.Method == http.MethodDelete:
+			middleware.CompanyPermission(companies.PermManageWebhooks, companyWebhooksHandler.Delete)(w, r)
+		// POST /company/webhooks/{id}/rotate-secret
+		case len(parts) == 2 && parts[1] == "rotate-secret" && r.Method == http.MethodPost:
+			middleware.CompanyPermission(companies.PermManageWebhooks, companyWebhooksHandler.RotateSecret)(w, r)
+		// POST /company/webhooks/{id}/test
+		case len(parts) == 2 && parts[1] == "test" && r.Method == http.MethodPost:
+			middleware.CompanyPermission(companies.PermManageWebhooks, companyWebhooksHandler.Test)(w, r)
+		// GET /company/webhooks/{id}/deliveries
+		case len(parts) == 2 && parts[1] == "deliveries" && r.Method == http.MethodGet:
+			middleware.CompanyPermission(companies.PermManageWebhooks, companyWebhooksHandler.ListDeliveries)(w, r)
+		// POST /company/webhooks/{id}/deliveries/{deliveryID}/redeliver
+		case len(parts) == 4 && parts[1] == "deliveries" && parts[3] == "redeliver" && r.Method == http.MethodPost:
+			middleware.CompanyPermission(companies.PermManageWebhooks, companyWebhooksHandler.Redeliver)(w, r)
+		default:
+			http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+		}
+	}))
 
 	// Application handlers (needed for company jobs applicants endpoint)
-	applicationsHandler := handlers.NewApplicationsHandler(appsRepo, jobsRepo, candidateRepo)
+	applicationsHandler := handlers.NewApplicationsHandler(appsRepo, jobsRepo, candidateRepo, mailer, notifier, webhookDispatcher)
 
 	// Company jobs handlers
-	companyJobsHandler := handlers.NewCompanyJobsHandler(jobsRepo, companyRepo)
-	mux.HandleFunc("/company/jobs", middleware.AuthMiddleware(middleware.CompanyOnly(func(w http.ResponseWriter, r *http.Request) {
+	companyJobsHandler := handlers.NewCompanyJobsHandler(jobsRepo, companyRepo, memberRepo, webhookDispatcher)
+	mux.HandleFunc("/company/jobs", middleware.AuthMiddleware(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method == http.MethodPost {
-			companyJobsHandler.Create(w, r)
+			middleware.CompanyPermission(companies.PermManageJobs, companyJobsHandler.Create)(w, r)
 		} else if r.Method == http.MethodGet {
-			companyJobsHandler.ListMine(w, r)
+			middleware.CompanyPermission(companies.PermViewJobs, companyJobsHandler.ListMine)(w, r)
 		} else {
 			http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
 		}
-	})))
+	}))
 
-	mux.HandleFunc("/company/jobs/", middleware.AuthMiddleware(middleware.CompanyOnly(func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("/company/jobs/", middleware.AuthMiddleware(func(w http.ResponseWriter, r *http.Request) {
 		path := r.URL.Path
 
 		// Check for specific actions
@@ -165,41 +425,111 @@ func SetupRoutes(
 			if r.Method == http.MethodPatch {
 				// Could be activate or deactivate
 				if len(path) > 10 && path[len(path)-10:] == "/activate" {
-					companyJobsHandler.Activate(w, r)
+					middleware.CompanyPermission(companies.PermManageJobs, companyJobsHandler.Activate)(w, r)
 					return
 				} else if len(path) > 12 && path[len(path)-12:] == "/deactivate" {
-					companyJobsHandler.Deactivate(w, r)
+					middleware.CompanyPermission(companies.PermManageJobs, companyJobsHandler.Deactivate)(w, r)
 					return
 				}
 			} else if r.Method == http.MethodGet && len(path) > 11 && path[len(path)-11:] == "/applicants" {
-				applicationsHandler.ListJobApplicants(w, r)
+				middleware.CompanyPermission(companies.PermViewApplications, applicationsHandler.ListJobApplicants)(w, r)
 				return
 			}
 		}
 
+		// PUT /company/jobs/{id}/team
+		if r.Method == http.MethodPut && len(path) > 5 && path[len(path)-5:] == "/team" {
+			middleware.CompanyPermission(companies.PermAssignHiringTeam, companyJobsHandler.UpdateHiringTeam)(w, r)
+			return
+		}
+
 		// Default actions on /company/jobs/{id}
 		if r.Method == http.MethodPut {
-			companyJobsHandler.Update(w, r)
+			middleware.CompanyPermission(companies.PermManageJobs, companyJobsHandler.Update)(w, r)
 		} else if r.Method == http.MethodDelete {
-			companyJobsHandler.Delete(w, r)
+			middleware.CompanyPermission(companies.PermManageJobs, companyJobsHandler.Delete)(w, r)
+		} else {
+			http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+		}
+	}))
+
+	// Conversas entre empresa e candidato por candidatura (só a empresa abre a conversa)
+	messagesHandler := handlers.NewMessagesHandler(messagesRepo, appsRepo, jobsRepo, candidateRepo, memberRepo, attachmentStore, notifier, webhookDispatcher)
+	mux.HandleFunc("/company/messages", middleware.AuthMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			middleware.CompanyPermission(companies.PermViewApplications, messagesHandler.ListThreads(models.MessageSideCompany))(w, r)
 		} else {
 			http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
 		}
-	})))
+	}))
 
-	// Company applications status update
-	mux.HandleFunc("/company/applications/", middleware.AuthMiddleware(middleware.CompanyOnly(func(w http.ResponseWriter, r *http.Request) {
-		// Check for /company/applications/{id}/status
-		if r.Method == http.MethodPatch && len(r.URL.Path) > 7 && r.URL.Path[len(r.URL.Path)-7:] == "/status" {
-			applicationsHandler.UpdateApplicationStatus(w, r)
+	// Entrevistas: a empresa agenda a partir da candidatura, o candidato escolhe o horário
+	interviewsRepo := repository.NewInterviewsRepository(db)
+	if err := interviewsRepo.EnsureIndexes(context.Background()); err != nil {
+		log.Println("Aviso: erro ao criar índices de interviews:", err)
+	}
+	interviewsHandler := handlers.NewInterviewsHandler(interviewsRepo, appsRepo, jobsRepo, candidateRepo, mailer, notifier, webhookDispatcher)
+	mux.HandleFunc("/company/interviews", middleware.AuthMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			middleware.CompanyPermission(companies.PermViewApplications, interviewsHandler.List(models.RecipientCompany))(w, r)
 		} else {
 			http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
 		}
-	})))
+	}))
+
+	mux.HandleFunc("/company/interviews/", middleware.AuthMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/company/interviews/"), "/"), "/")
+
+		switch {
+		// /company/interviews/{id}
+		case len(parts) == 1 && r.Method == http.MethodGet:
+			middleware.CompanyPermission(companies.PermViewApplications, interviewsHandler.Get(models.RecipientCompany))(w, r)
+		case len(parts) == 1 && r.Method == http.MethodPut:
+			middleware.CompanyPermission(companies.PermManageApplications, interviewsHandler.Update)(w, r)
+		// POST /company/interviews/{id}/cancel
+		case len(parts) == 2 && parts[1] == "cancel" && r.Method == http.MethodPost:
+			middleware.CompanyPermission(companies.PermManageApplications, interviewsHandler.Cancel)(w, r)
+		// GET /company/interviews/{id}/invite.ics
+		case len(parts) == 2 && parts[1] == "invite.ics" && r.Method == http.MethodGet:
+			middleware.CompanyPermission(companies.PermViewApplications, interviewsHandler.Invite(models.RecipientCompany))(w, r)
+		default:
+			http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+		}
+	}))
+
+	// Company applications status update, messages and interviews
+	mux.HandleFunc("/company/applications/", middleware.AuthMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/company/applications/"), "/"), "/")
+
+		switch {
+		// PATCH /company/applications/{id}/status
+		case len(parts) == 2 && parts[1] == "status" && r.Method == http.MethodPatch:
+			middleware.CompanyPermission(companies.PermManageApplications, applicationsHandler.UpdateApplicationStatus)(w, r)
+		// /company/applications/{id}/messages
+		case len(parts) == 2 && parts[1] == "messages" && r.Method == http.MethodGet:
+			middleware.CompanyPermission(companies.PermViewApplications, messagesHandler.GetThread(models.MessageSideCompany))(w, r)
+		case len(parts) == 2 && parts[1] == "messages" && r.Method == http.MethodPost:
+			middleware.CompanyPermission(companies.PermManageApplications,
+				middleware.UserRateLimit(rateLimiter, messagesRateLimit, messagesRateLimitMessage, messagesHandler.Send(models.MessageSideCompany)))(w, r)
+		// /company/applications/{id}/interviews
+		case len(parts) == 2 && parts[1] == "interviews" && r.Method == http.MethodGet:
+			middleware.CompanyPermission(companies.PermViewApplications, interviewsHandler.ListForApplication)(w, r)
+		case len(parts) == 2 && parts[1] == "interviews" && r.Method == http.MethodPost:
+			middleware.CompanyPermission(companies.PermManageApplications, interviewsHandler.Create)(w, r)
+		// POST /company/applications/{id}/messages/read
+		case len(parts) == 3 && parts[1] == "messages" && parts[2] == "read" && r.Method == http.MethodPost:
+			middleware.CompanyPermission(companies.PermViewApplications, messagesHandler.MarkRead(models.MessageSideCompany))(w, r)
+		// GET /company/applications/{id}/messages/{messageID}/attachments/{n}
+		case len(parts) == 5 && parts[1] == "messages" && parts[3] == "attachments" && r.Method == http.MethodGet:
+			middleware.CompanyPermission(companies.PermViewApplications, messagesHandler.Attachment(models.MessageSideCompany))(w, r)
+		default:
+			http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+		}
+	}))
 
 	// Candidate profile handlers
 	candidateHandler := handlers.NewCandidateHandler(candidateRepo)
-	mux.HandleFunc("/candidate/me", middleware.AuthMiddleware(middleware.CandidateOnly(func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("/candidate/me", middleware.CandidateOnly(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method == http.MethodGet {
 			candidateHandler.GetProfile(w, r)
 		} else if r.Method == http.MethodPut {
@@ -207,8 +537,8 @@ func SetupRoutes(
 		} else {
 			http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
 		}
-	})))
-	mux.HandleFunc("/candidate/applications", middleware.AuthMiddleware(middleware.CandidateOnly(func(w http.ResponseWriter, r *http.Request) {
+	}))
+	mux.HandleFunc("/candidate/applications", middleware.CandidateOnly(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method == http.MethodPost {
 			applicationsHandler.Apply(w, r)
 		} else if r.Method == http.MethodGet {
@@ -216,19 +546,79 @@ func SetupRoutes(
 		} else {
 			http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
 		}
-	})))
+	}))
 
-	mux.HandleFunc("/candidate/applications/", middleware.AuthMiddleware(middleware.CandidateOnly(func(w http.ResponseWriter, r *http.Request) {
-		if r.Method == http.MethodDelete {
+	mux.HandleFunc("/candidate/applications/", middleware.CandidateOnly(func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/candidate/applications/"), "/"), "/")
+
+		switch {
+		// DELETE /candidate/applications/{id}
+		case len(parts) == 1 && r.Method == http.MethodDelete:
 			applicationsHandler.Cancel(w, r)
+		// /candidate/applications/{id}/messages
+		case len(parts) == 2 && parts[1] == "messages" && r.Method == http.MethodGet:
+			messagesHandler.GetThread(models.MessageSideCandidate)(w, r)
+		case len(parts) == 2 && parts[1] == "messages" && r.Method == http.MethodPost:
+			middleware.UserRateLimit(rateLimiter, messagesRateLimit, messagesRateLimitMessage, messagesHandler.Send(models.MessageSideCandidate))(w, r)
+		// POST /candidate/applications/{id}/messages/read
+		case len(parts) == 3 && parts[1] == "messages" && parts[2] == "read" && r.Method == http.MethodPost:
+			messagesHandler.MarkRead(models.MessageSideCandidate)(w, r)
+		// GET /candidate/applications/{id}/messages/{messageID}/attachments/{n}
+		case len(parts) == 5 && parts[1] == "messages" && parts[3] == "attachments" && r.Method == http.MethodGet:
+			messagesHandler.Attachment(models.MessageSideCandidate)(w, r)
+		default:
+			http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+		}
+	}))
+
+	mux.HandleFunc("/candidate/interviews", middleware.CandidateOnly(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			interviewsHandler.List(models.RecipientCandidate)(w, r)
+		} else {
+			http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+		}
+	}))
+
+	mux.HandleFunc("/candidate/interviews/", middleware.CandidateOnly(func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/candidate/interviews/"), "/"), "/")
+
+		switch {
+		// GET /candidate/interviews/{id}
+		case len(parts) == 1 && r.Method == http.MethodGet:
+			interviewsHandler.Get(models.RecipientCandidate)(w, r)
+		// POST /candidate/interviews/{id}/choose
+		case len(parts) == 2 && parts[1] == "choose" && r.Method == http.MethodPost:
+			interviewsHandler.Choose(w, r)
+		// GET /candidate/interviews/{id}/invite.ics
+		case len(parts) == 2 && parts[1] == "invite.ics" && r.Method == http.MethodGet:
+			interviewsHandler.Invite(models.RecipientCandidate)(w, r)
+		default:
+			http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+		}
+	}))
+
+	mux.HandleFunc("/candidate/messages", middleware.CandidateOnly(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			messagesHandler.ListThreads(models.MessageSideCandidate)(w, r)
+		} else {
+			http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+		}
+	}))
+
+	// Recomendações de vagas (o ranking fica em cache por candidato)
+	recommender := recommendations.NewService(jobsRepo, appsRepo, savedJobsRepo)
+	recommendationsHandler := handlers.NewRecommendationsHandler(recommender, candidateRepo)
+	mux.HandleFunc("/candidate/recommendations", middleware.CandidateOnly(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			recommendationsHandler.List(w, r)
 		} else {
 			http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
 		}
-	})))
+	}))
 
 	// Saved jobs handlers for candidates
-	savedJobsHandler := handlers.NewSavedJobsHandler(savedJobsRepo, jobsRepo)
-	mux.HandleFunc("/candidate/saved-jobs", middleware.AuthMiddleware(middleware.CandidateOnly(func(w http.ResponseWriter, r *http.Request) {
+	savedJobsHandler := handlers.NewSavedJobsHandler(savedJobsRepo, jobsRepo, recommender)
+	mux.HandleFunc("/candidate/saved-jobs", middleware.CandidateOnly(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method == http.MethodPost {
 			savedJobsHandler.SaveJob(w, r)
 		} else if r.Method == http.MethodGet {
@@ -236,15 +626,294 @@ func SetupRoutes(
 		} else {
 			http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
 		}
-	})))
+	}))
 
-	mux.HandleFunc("/candidate/saved-jobs/", middleware.AuthMiddleware(middleware.CandidateOnly(func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("/candidate/saved-jobs/", middleware.CandidateOnly(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method == http.MethodDelete {
 			savedJobsHandler.UnsaveJob(w, r)
 		} else {
 			http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
 		}
-	})))
+	}))
+
+	// Banco de talentos: o candidato escolhe a visibilidade, empresas verificadas buscam e pedem
+	// contato, e os contatos só são liberados quando o candidato aceita
+	talentPoolRepo := repository.NewTalentPoolRepository(db)
+	if err := talentPoolRepo.EnsureIndexes(context.Background()); err != nil {
+		log.Println("Aviso: erro ao criar índices do banco de talentos:", err)
+	}
+	talentPoolHandler := handlers.NewTalentPoolHandler(talentPoolRepo, candidateRepo, companyRepo, notifier)
+	mux.HandleFunc("/candidate/talent-pool", middleware.CandidateOnly(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			talentPoolHandler.GetSettings(w, r)
+		case http.MethodPut:
+			talentPoolHandler.UpdateSettings(w, r)
+		default:
+			http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+		}
+	}))
+
+	mux.HandleFunc("/candidate/talent-pool/activity", middleware.CandidateOnly(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			talentPoolHandler.Activity(w, r)
+		} else {
+			http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+		}
+	}))
+
+	mux.HandleFunc("/candidate/contact-requests", middleware.CandidateOnly(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			talentPoolHandler.ListContactRequests(models.RecipientCandidate)(w, r)
+		} else {
+			http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+		}
+	}))
+
+	mux.HandleFunc("/candidate/contact-requests/", middleware.CandidateOnly(func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/candidate/contact-requests/"), "/"), "/")
+		switch {
+		// POST /candidate/contact-requests/{id}/accept
+		case len(parts) == 2 && parts[1] == "accept" && r.Method == http.MethodPost:
+			talentPoolHandler.RespondContactRequest(models.ContactRequestAccepted)(w, r)
+		// POST /candidate/contact-requests/{id}/decline
+		case len(parts) == 2 && parts[1] == "decline" && r.Method == http.MethodPost:
+			talentPoolHandler.RespondContactRequest(models.ContactRequestDeclined)(w, r)
+		default:
+			http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+		}
+	}))
+
+	mux.HandleFunc("/company/talent", middleware.AuthMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			middleware.CompanyPermission(companies.PermSearchTalent, talentPoolHandler.Search)(w, r)
+		} else {
+			http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+		}
+	}))
+
+	mux.HandleFunc("/company/talent/", middleware.AuthMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/company/talent/"), "/"), "/")
+		switch {
+		// GET /company/talent/{candidateId}
+		case len(parts) == 1 && r.Method == http.MethodGet:
+			middleware.CompanyPermission(companies.PermSearchTalent, talentPoolHandler.View)(w, r)
+		// POST /company/talent/{candidateId}/contact
+		case len(parts) == 2 && parts[1] == "contact" && r.Method == http.MethodPost:
+			middleware.CompanyPermission(companies.PermContactTalent,
+				middleware.UserRateLimit(rateLimiter, contactRequestsRateLimit, contactRequestsRateLimitMessage, talentPoolHandler.RequestContact))(w, r)
+		default:
+			http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+		}
+	}))
+
+	mux.HandleFunc("/company/contact-requests", middleware.AuthMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			middleware.CompanyPermission(companies.PermSearchTalent, talentPoolHandler.ListContactRequests(models.RecipientCompany))(w, r)
+		} else {
+			http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+		}
+	}))
+
+	// Perfil público de quem escolheu a visibilidade "public" (sem contatos)
+	mux.HandleFunc("/talent/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			talentPoolHandler.PublicProfile(w, r)
+		} else {
+			http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+		}
+	})
+
+	// Buscas salvas com alertas de vagas novas (o descadastro pelo link do email dispensa login)
+	savedSearchesHandler := handlers.NewSavedSearchesHandler(savedSearchesRepo, jobsRepo)
+	mux.HandleFunc("/candidate/saved-searches", middleware.CandidateOnly(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			savedSearchesHandler.List(w, r)
+		case http.MethodPost:
+			savedSearchesHandler.Create(w, r)
+		default:
+			http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+		}
+	}))
+
+	mux.HandleFunc("/candidate/saved-searches/", middleware.CandidateOnly(func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/candidate/saved-searches/"), "/"), "/")
+		switch {
+		// /candidate/saved-searches/{id}
+		case len(parts) == 1 && r.Method == http.MethodGet:
+			savedSearchesHandler.Get(w, r)
+		case len(parts) == 1 && r.Method == http.MethodPut:
+			savedSearchesHandler.Update(w, r)
+		case len(parts) == 1 && r.Method == http.MethodDelete:
+			savedSearchesHandler.Delete(w, r)
+		// GET /candidate/saved-searches/{id}/jobs
+		case len(parts) == 2 && parts[1] == "jobs" && r.Method == http.MethodGet:
+			savedSearchesHandler.Jobs(w, r)
+		default:
+			http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+		}
+	}))
+
+	mux.HandleFunc("/alerts/unsubscribe", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			savedSearchesHandler.Unsubscribe(w, r)
+		} else {
+			http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+		}
+	})
+
+	// Central de notificações (candidatos, donos e membros das empresas)
+	notificationsHandler := handlers.NewNotificationsHandler(notificationsRepo, notifier)
+	mux.HandleFunc("/notifications", middleware.AuthMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			notificationsHandler.List(w, r)
+		} else {
+			http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+		}
+	}))
+
+	mux.HandleFunc("/notifications/", middleware.AuthMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/notifications/unread-count":
+			notificationsHandler.UnreadCount(w, r)
+		case r.Method == http.MethodPost && r.URL.Path == "/notifications/read-all":
+			notificationsHandler.MarkAllRead(w, r)
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/read"):
+			notificationsHandler.MarkRead(w, r)
+		default:
+			http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+		}
+	}))
+
+	// Atualizações em tempo real (Server-Sent Events)
+	eventsHandler := handlers.NewEventsHandler(eventsHub, notificationsRepo)
+	mux.HandleFunc("/events", middleware.EventStreamAuth(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			eventsHandler.Stream(w, r)
+		} else {
+			http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+		}
+	}))
+
+	// Back-office (apenas administradores; toda ação é registrada na auditoria)
+	adminAuthHandler := handlers.NewAdminAuthHandler(adminRepo, auditRepo, lockoutService)
+	mux.HandleFunc("/admin/login", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			adminAuthHandler.Login(w, r)
+		} else {
+			http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+		}
+	})
+
+	adminHandler := handlers.NewAdminHandler(companyRepo, candidateRepo, jobsRepo, jobReportsRepo, companyReviewsRepo, auditRepo, mailer, webhookDispatcher)
+	mux.HandleFunc("/admin/companies", middleware.AdminOnly(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			adminHandler.ListCompanies(w, r)
+		} else {
+			http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+		}
+	}))
+
+	mux.HandleFunc("/admin/companies/", middleware.AdminOnly(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+			return
+		}
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/verification"):
+			adminHandler.ReviewCompanyVerification(w, r)
+		case strings.HasSuffix(r.URL.Path, "/unsuspend"):
+			adminHandler.SetCompanySuspension(false)(w, r)
+		case strings.HasSuffix(r.URL.Path, "/suspend"):
+			adminHandler.SetCompanySuspension(true)(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+
+	mux.HandleFunc("/admin/candidates/", middleware.AdminOnly(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+			return
+		}
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/unsuspend"):
+			adminHandler.SetCandidateSuspension(false)(w, r)
+		case strings.HasSuffix(r.URL.Path, "/suspend"):
+			adminHandler.SetCandidateSuspension(true)(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+
+	mux.HandleFunc("/admin/jobs/", middleware.AdminOnly(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+			return
+		}
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/reports/resolve"):
+			adminHandler.ResolveReports(w, r)
+		case strings.HasSuffix(r.URL.Path, "/moderation"):
+			adminHandler.ReviewJobModeration(w, r)
+		case strings.HasSuffix(r.URL.Path, "/takedown"):
+			adminHandler.SetJobTakedown(true)(w, r)
+		case strings.HasSuffix(r.URL.Path, "/restore"):
+			adminHandler.SetJobTakedown(false)(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+
+	mux.HandleFunc("/admin/moderation/jobs", middleware.AdminOnly(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			adminHandler.ListModerationQueue(w, r)
+		} else {
+			http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+		}
+	}))
+
+	mux.HandleFunc("/admin/reports", middleware.AdminOnly(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			adminHandler.ListReports(w, r)
+		} else {
+			http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+		}
+	}))
+
+	mux.HandleFunc("/admin/reviews", middleware.AdminOnly(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			adminHandler.ListReviews(w, r)
+		} else {
+			http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+		}
+	}))
+
+	mux.HandleFunc("/admin/reviews/", middleware.AdminOnly(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/moderation") {
+			adminHandler.ModerateReview(w, r)
+		} else {
+			http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+		}
+	}))
+
+	mux.HandleFunc("/admin/audit", middleware.AdminOnly(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			adminHandler.ListAudit(w, r)
+		} else {
+			http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+		}
+	}))
+
+	maintenanceHandler := handlers.NewMaintenanceHandler(jobsRepo, auditRepo)
+	mux.HandleFunc("/admin/maintenance/fix-counters", middleware.AdminOnly(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			maintenanceHandler.FixJobCounters(w, r)
+		} else {
+			http.Error(w, "Método não permitido", http.StatusMethodNotAllowed)
+		}
+	}))
 
 	return mux
 }
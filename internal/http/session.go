@@ -0,0 +1,71 @@
+package http
+
+import (
+	"context"
+	"empregabemapi/admins"
+	"empregabemapi/candidates"
+	"empregabemapi/companies"
+	"empregabemapi/internal/auth"
+	"empregabemapi/internal/middleware"
+	"errors"
+	"time"
+)
+
+var errSessionRevoked = errors.New("sessão revogada")
+
+// newSessionValidator rejeita tokens emitidos antes de tokens_valid_after da conta
+// (definido ao trocar ou redefinir a senha) e tokens de contas removidas.
+// Para membros de empresa também rejeita tokens cujo papel não é mais o atual.
+// Contas suspensas (e membros de empresas suspensas) perdem todas as sessões.
+func newSessionValidator(companyRepo *companies.MongoRepository, memberRepo *companies.MemberRepository, candidateRepo *candidates.MongoRepository, adminRepo *admins.MongoRepository) middleware.SessionValidator {
+	return func(ctx context.Context, claims *auth.Claims) error {
+		ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+		defer cancel()
+
+		var validAfter *time.Time
+		switch claims.Type {
+		case "company":
+			if claims.MemberID != "" {
+				// Membro removido ou com papel alterado perde as sessões
+				member, err := memberRepo.GetByID(ctx, claims.MemberID)
+				if err != nil || member.CompanyID.Hex() != claims.ID || string(member.Role) != claims.Role {
+					return errSessionRevoked
+				}
+				company, err := companyRepo.GetByID(ctx, claims.ID)
+				if err != nil || company.SuspendedAt != nil {
+					return errSessionRevoked
+				}
+				validAfter = member.TokensValidAfter
+				break
+			}
+			company, err := companyRepo.GetByID(ctx, claims.ID)
+			if err != nil || company.SuspendedAt != nil {
+				return errSessionRevoked
+			}
+			validAfter = company.TokensValidAfter
+		case "candidate":
+			candidate, err := candidateRepo.GetByID(ctx, claims.ID)
+			if err != nil || candidate.SuspendedAt != nil {
+				return errSessionRevoked
+			}
+			validAfter = candidate.TokensValidAfter
+		case "admin":
+			admin, err := adminRepo.GetByID(ctx, claims.ID)
+			if err != nil {
+				return errSessionRevoked
+			}
+			validAfter = admin.TokensValidAfter
+		default:
+			return errSessionRevoked
+		}
+
+		if validAfter == nil || claims.IssuedAt == nil {
+			return nil
+		}
+		// iat tem precisão de segundos
+		if claims.IssuedAt.Time.Before(validAfter.Truncate(time.Second)) {
+			return errSessionRevoked
+		}
+		return nil
+	}
+}
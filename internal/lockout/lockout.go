@@ -0,0 +1,181 @@
+package lockout
+
+import (
+	"context"
+	"empregabemapi/internal/models"
+	"empregabemapi/internal/repository"
+	"errors"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// Policy define quando uma chave (conta ou IP) é bloqueada e por quanto tempo
+type Policy struct {
+	MaxFailures int           // falhas consecutivas até o bloqueio
+	Window      time.Duration // falhas mais antigas que isso não contam
+	BaseLockout time.Duration // duração do primeiro bloqueio (dobra a cada novo bloqueio)
+	MaxLockout  time.Duration // duração máxima de um bloqueio
+}
+
+var (
+	// DefaultAccountPolicy bloqueia a conta após 5 senhas erradas em 15 minutos
+	DefaultAccountPolicy = Policy{MaxFailures: 5, Window: 15 * time.Minute, BaseLockout: time.Minute, MaxLockout: time.Hour}
+	// DefaultIPPolicy bloqueia o IP após 20 falhas em 15 minutos (qualquer conta)
+	DefaultIPPolicy = Policy{MaxFailures: 20, Window: 15 * time.Minute, BaseLockout: 5 * time.Minute, MaxLockout: 24 * time.Hour}
+)
+
+// Por quanto tempo o histórico de bloqueios é mantido (afeta o backoff exponencial)
+const retention = 24 * time.Hour
+
+// Service controla o throttling de login por conta e por IP
+type Service struct {
+	attempts      *repository.LoginAttemptsRepository
+	audit         *repository.AuditRepository
+	accountPolicy Policy
+	ipPolicy      Policy
+}
+
+func NewService(attempts *repository.LoginAttemptsRepository, audit *repository.AuditRepository) *Service {
+	return &Service{
+		attempts:      attempts,
+		audit:         audit,
+		accountPolicy: DefaultAccountPolicy,
+		ipPolicy:      DefaultIPPolicy,
+	}
+}
+
+func accountKey(userType, email string) string {
+	return "account:" + userType + ":" + email
+}
+
+func ipKey(ip string) string {
+	return "ip:" + ip
+}
+
+// Check retorna quanto tempo falta para liberar o login (0 se liberado).
+// O bloqueio do IP tem precedência sobre o da conta.
+func (s *Service) Check(ctx context.Context, userType, email, ip string) (time.Duration, error) {
+	if wait, err := s.lockedFor(ctx, ipKey(ip)); err != nil || wait > 0 {
+		return wait, err
+	}
+	return s.lockedFor(ctx, accountKey(userType, email))
+}
+
+func (s *Service) lockedFor(ctx context.Context, key string) (time.Duration, error) {
+	attempt, err := s.attempts.Get(ctx, key)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	if attempt.LockedUntil == nil {
+		return 0, nil
+	}
+	if wait := time.Until(*attempt.LockedUntil); wait > 0 {
+		return wait, nil
+	}
+	return 0, nil
+}
+
+// RecordFailure registra uma senha incorreta (ou email inexistente) para a conta e o IP
+func (s *Service) RecordFailure(ctx context.Context, userType, email, ip string) {
+	s.recordFailure(ctx, accountKey(userType, email), "account", s.accountPolicy, ip, map[string]interface{}{
+		"email":     email,
+		"user_type": userType,
+	})
+	s.recordFailure(ctx, ipKey(ip), "ip", s.ipPolicy, ip, nil)
+}
+
+func (s *Service) recordFailure(ctx context.Context, key, kind string, policy Policy, ip string, details map[string]interface{}) {
+	attempt, err := s.attempts.RecordFailure(ctx, key, kind, time.Now().Add(-policy.Window), retention)
+	if err != nil {
+		log.Printf("[lockout] erro ao registrar falha de login (%s): %v", key, err)
+		return
+	}
+	if attempt.Failures < policy.MaxFailures {
+		return
+	}
+
+	duration := backoff(policy, attempt.LockCount)
+	until := time.Now().Add(duration)
+	if err := s.attempts.Lock(ctx, key, until, retention); err != nil {
+		log.Printf("[lockout] erro ao bloquear %s: %v", key, err)
+		return
+	}
+
+	if details == nil {
+		details = map[string]interface{}{}
+	}
+	details["failures"] = attempt.Failures
+	details["locked_until"] = until
+	details["lock_count"] = attempt.LockCount + 1
+
+	entry := &models.AuditLog{
+		Action:    "auth." + kind + "_locked",
+		ActorType: "system",
+		IP:        ip,
+		Details:   details,
+	}
+	if kind == "account" {
+		entry.TargetType = "account"
+		entry.TargetID = key
+	}
+	if err := s.audit.Log(ctx, entry); err != nil {
+		log.Printf("[lockout] erro ao registrar auditoria de bloqueio: %v", err)
+	}
+}
+
+// RecordSuccess limpa as falhas da conta após um login bem-sucedido.
+// As falhas do IP continuam contando (um IP pode testar várias contas).
+func (s *Service) RecordSuccess(ctx context.Context, userType, email string) {
+	if err := s.attempts.Reset(ctx, accountKey(userType, email)); err != nil {
+		log.Printf("[lockout] erro ao limpar falhas de login: %v", err)
+	}
+}
+
+// Unlock desbloqueia a conta (usado após redefinição de senha por email)
+func (s *Service) Unlock(ctx context.Context, userType, email, ip string) {
+	key := accountKey(userType, email)
+	wait, err := s.lockedFor(ctx, key)
+	if err != nil {
+		log.Printf("[lockout] erro ao verificar bloqueio: %v", err)
+	}
+
+	if err := s.attempts.Reset(ctx, key); err != nil {
+		log.Printf("[lockout] erro ao desbloquear conta: %v", err)
+		return
+	}
+
+	if wait > 0 {
+		err := s.audit.Log(ctx, &models.AuditLog{
+			Action:     "auth.account_unlocked",
+			ActorType:  "system",
+			TargetType: "account",
+			TargetID:   key,
+			IP:         ip,
+			Details: map[string]interface{}{
+				"email":     email,
+				"user_type": userType,
+				"reason":    "password_reset",
+			},
+		})
+		if err != nil {
+			log.Printf("[lockout] erro ao registrar auditoria de desbloqueio: %v", err)
+		}
+	}
+}
+
+// backoff calcula a duração do bloqueio: BaseLockout * 2^lockCount, limitado a MaxLockout
+func backoff(policy Policy, lockCount int) time.Duration {
+	duration := policy.BaseLockout
+	for i := 0; i < lockCount; i++ {
+		duration *= 2
+		if duration >= policy.MaxLockout {
+			return policy.MaxLockout
+		}
+	}
+	return duration
+}
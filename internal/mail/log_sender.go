@@ -0,0 +1,56 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// LogSender é usado em desenvolvimento: em vez de enviar, grava o email
+// como arquivo .eml em um diretório (ou apenas no log se o diretório for vazio)
+type LogSender struct {
+	dir  string
+	from string
+}
+
+func NewLogSender(dir, from string) *LogSender {
+	return &LogSender{dir: dir, from: from}
+}
+
+func (s *LogSender) Send(ctx context.Context, msg *Message) error {
+	if s.dir == "" {
+		log.Printf("📧 [mail] para=%s assunto=%q\n%s", msg.To, msg.Subject, msg.TextBody)
+		return nil
+	}
+
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return err
+	}
+
+	raw, err := buildMIME(s.from, msg)
+	if err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s_%s.eml", time.Now().Format("20060102-150405.000000"), sanitizeFilename(msg.To))
+	path := filepath.Join(s.dir, name)
+	if err := os.WriteFile(path, raw, 0o644); err != nil {
+		return err
+	}
+
+	log.Printf("📧 [mail] email para %s gravado em %s", msg.To, path)
+	return nil
+}
+
+func sanitizeFilename(s string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '.' || r == '-' {
+			return r
+		}
+		return '_'
+	}, s)
+}
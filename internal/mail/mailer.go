@@ -0,0 +1,56 @@
+package mail
+
+import (
+	"context"
+	"empregabemapi/internal/models"
+	"empregabemapi/internal/repository"
+	netmail "net/mail"
+	"strings"
+)
+
+// Mailer renderiza templates e grava os emails no outbox.
+// O envio real é feito pelo Worker, então os handlers nunca esperam pelo SMTP.
+type Mailer struct {
+	outbox  *repository.EmailOutboxRepository
+	baseURL string
+	from    string
+}
+
+func NewMailer(outbox *repository.EmailOutboxRepository, baseURL, from string) *Mailer {
+	return &Mailer{
+		outbox:  outbox,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		from:    from,
+	}
+}
+
+// FromAddress retorna só o endereço do remetente (MAIL_FROM), usado como organizador
+// nos convites de calendário
+func (m *Mailer) FromAddress() string {
+	if addr, err := netmail.ParseAddress(m.from); err == nil {
+		return addr.Address
+	}
+	return m.from
+}
+
+// URL monta um link absoluto para o frontend (ex: URL("/reset-password?token=..."))
+func (m *Mailer) URL(path string) string {
+	return m.baseURL + path
+}
+
+// Enqueue renderiza o template e adiciona o email ao outbox
+func (m *Mailer) Enqueue(ctx context.Context, to, template string, data any, attachments ...models.EmailAttachment) error {
+	subject, html, text, err := Render(template, data)
+	if err != nil {
+		return err
+	}
+
+	return m.outbox.Enqueue(ctx, &models.OutboxEmail{
+		To:          to,
+		Subject:     subject,
+		HTMLBody:    html,
+		TextBody:    text,
+		Attachments: attachments,
+		Template:    template,
+	})
+}
@@ -0,0 +1,35 @@
+package mail
+
+import (
+	"context"
+	"empregabemapi/internal/models"
+)
+
+// Message é um email pronto para envio
+type Message struct {
+	To          string
+	Subject     string
+	HTMLBody    string
+	TextBody    string
+	Attachments []models.EmailAttachment
+}
+
+// Sender envia um email de forma síncrona.
+// Implementações: SMTPSender (produção) e LogSender (desenvolvimento).
+type Sender interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// PermanentError indica que a mensagem é inválida (ex: destinatário malformado) e não adianta
+// tentar de novo: o worker marca o email como falho sem esperar as novas tentativas
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PermanentError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,222 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	netmail "net/mail"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// SMTPConfig contém os dados de conexão com o servidor SMTP
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string // ex: "EmpregaBem <nao-responda@empregabem.com.br>"
+}
+
+// SMTPSender envia emails via SMTP (STARTTLS quando disponível, TLS implícito na porta 465)
+type SMTPSender struct {
+	cfg SMTPConfig
+}
+
+func NewSMTPSender(cfg SMTPConfig) *SMTPSender {
+	return &SMTPSender{cfg: cfg}
+}
+
+func (s *SMTPSender) Send(ctx context.Context, msg *Message) error {
+	if s.cfg.Host == "" {
+		return errors.New("SMTP_HOST não configurado")
+	}
+
+	raw, err := buildMIME(s.cfg.From, msg)
+	if err != nil {
+		return err
+	}
+
+	addr := net.JoinHostPort(s.cfg.Host, s.cfg.Port)
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+
+	var conn net.Conn
+	if s.cfg.Port == "465" {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, &tls.Config{ServerName: s.cfg.Host})
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("erro ao conectar no SMTP: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, s.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("erro ao iniciar sessão SMTP: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: s.cfg.Host}); err != nil {
+			return fmt.Errorf("erro no STARTTLS: %w", err)
+		}
+	}
+
+	if s.cfg.Username != "" {
+		if ok, _ := client.Extension("AUTH"); ok {
+			auth := smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.cfg.Host)
+			if err := client.Auth(auth); err != nil {
+				return fmt.Errorf("erro de autenticação SMTP: %w", err)
+			}
+		}
+	}
+
+	if err := client.Mail(envelopeAddress(s.cfg.From)); err != nil {
+		return err
+	}
+	if err := client.Rcpt(msg.To); err != nil {
+		return err
+	}
+
+	wc, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := wc.Write(raw); err != nil {
+		wc.Close()
+		return err
+	}
+	if err := wc.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+// envelopeAddress extrai o endereço de "Nome <email>"
+func envelopeAddress(from string) string {
+	if start := strings.LastIndex(from, "<"); start != -1 {
+		if end := strings.LastIndex(from, ">"); end > start {
+			return from[start+1 : end]
+		}
+	}
+	return strings.TrimSpace(from)
+}
+
+// buildMIME monta a mensagem multipart/alternative (texto + HTML),
+// embrulhada em multipart/mixed quando há anexos
+func buildMIME(from string, msg *Message) ([]byte, error) {
+	var buf bytes.Buffer
+
+	// Destinatário, assunto e anexos vêm dos dados dos usuários: quebras de linha injetariam cabeçalhos
+	to, err := netmail.ParseAddress(msg.To)
+	if err != nil || hasLineBreak(msg.To) {
+		return nil, &PermanentError{Err: fmt.Errorf("destinatário inválido: %q", msg.To)}
+	}
+	if hasLineBreak(msg.Subject) {
+		return nil, &PermanentError{Err: errors.New("assunto inválido: contém quebra de linha")}
+	}
+
+	altBoundary, err := randomBoundary()
+	if err != nil {
+		return nil, err
+	}
+
+	writeHeader := func(key, value string) {
+		buf.WriteString(key + ": " + value + "\r\n")
+	}
+
+	writeHeader("From", from)
+	writeHeader("To", to.String())
+	writeHeader("Subject", mime.QEncoding.Encode("utf-8", msg.Subject))
+	writeHeader("Date", time.Now().Format(time.RFC1123Z))
+	writeHeader("MIME-Version", "1.0")
+
+	var mixedBoundary string
+	if len(msg.Attachments) > 0 {
+		mixedBoundary, err = randomBoundary()
+		if err != nil {
+			return nil, err
+		}
+		writeHeader("Content-Type", `multipart/mixed; boundary="`+mixedBoundary+`"`)
+		buf.WriteString("\r\n--" + mixedBoundary + "\r\n")
+	}
+
+	writeHeader("Content-Type", `multipart/alternative; boundary="`+altBoundary+`"`)
+	buf.WriteString("\r\n")
+
+	writePart := func(contentType, body string) error {
+		buf.WriteString("--" + altBoundary + "\r\n")
+		buf.WriteString("Content-Type: " + contentType + "; charset=utf-8\r\n")
+		buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+		qp := quotedprintable.NewWriter(&buf)
+		if _, err := qp.Write([]byte(body)); err != nil {
+			return err
+		}
+		if err := qp.Close(); err != nil {
+			return err
+		}
+		buf.WriteString("\r\n")
+		return nil
+	}
+
+	if err := writePart("text/plain", msg.TextBody); err != nil {
+		return nil, err
+	}
+	if msg.HTMLBody != "" {
+		if err := writePart("text/html", msg.HTMLBody); err != nil {
+			return nil, err
+		}
+	}
+	buf.WriteString("--" + altBoundary + "--\r\n")
+
+	if mixedBoundary != "" {
+		for _, att := range msg.Attachments {
+			disposition := mime.FormatMediaType("attachment", map[string]string{"filename": att.Filename})
+			if disposition == "" || hasLineBreak(att.Filename) || hasLineBreak(att.ContentType) {
+				return nil, &PermanentError{Err: fmt.Errorf("anexo inválido: %q", att.Filename)}
+			}
+
+			buf.WriteString("--" + mixedBoundary + "\r\n")
+			buf.WriteString("Content-Type: " + att.ContentType + "\r\n")
+			buf.WriteString("Content-Transfer-Encoding: base64\r\n")
+			buf.WriteString("Content-Disposition: " + disposition + "\r\n\r\n")
+
+			encoded := base64.StdEncoding.EncodeToString(att.Content)
+			for len(encoded) > 76 {
+				buf.WriteString(encoded[:76] + "\r\n")
+				encoded = encoded[76:]
+			}
+			buf.WriteString(encoded + "\r\n")
+		}
+		buf.WriteString("--" + mixedBoundary + "--\r\n")
+	}
+
+	return buf.Bytes(), nil
+}
+
+// hasLineBreak indica se o valor tem CR ou LF (não pode ir para um cabeçalho)
+func hasLineBreak(value string) bool {
+	return strings.ContainsAny(value, "\r\n")
+}
+
+func randomBoundary() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "eb-" + hex.EncodeToString(b), nil
+}
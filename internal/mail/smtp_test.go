@@ -0,0 +1,244 @@
+package mail
+
+import (
+	"context"
+	"empregabemapi/internal/models"
+	"encoding/base64"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	netmail "net/mail"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeSMTPServer aceita uma única sessão SMTP sem TLS nem autenticação e guarda o envelope e o DATA
+type fakeSMTPServer struct {
+	listener net.Listener
+	from     string
+	rcpt     []string
+	data     []byte
+	done     chan error
+}
+
+func newFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	s := &fakeSMTPServer{listener: listener, done: make(chan error, 1)}
+	go func() { s.done <- s.serve() }()
+	t.Cleanup(func() { listener.Close() })
+	return s
+}
+
+func (s *fakeSMTPServer) port() string {
+	return strings.TrimPrefix(s.listener.Addr().String(), "127.0.0.1:")
+}
+
+func (s *fakeSMTPServer) serve() error {
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	text := textproto.NewConn(conn)
+	if err := text.PrintfLine("220 localhost ESMTP fake"); err != nil {
+		return err
+	}
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return err
+		}
+		command := strings.ToUpper(line)
+		switch {
+		case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+			text.PrintfLine("250-localhost\r\n250 8BITMIME")
+		case strings.HasPrefix(command, "MAIL FROM:"):
+			s.from = envelopeAddress(line[len("MAIL FROM:"):]) // ignora parâmetros como BODY=8BITMIME
+			text.PrintfLine("250 OK")
+		case strings.HasPrefix(command, "RCPT TO:"):
+			s.rcpt = append(s.rcpt, envelopeAddress(line[len("RCPT TO:"):]))
+			text.PrintfLine("250 OK")
+		case command == "DATA":
+			text.PrintfLine("354 envie os dados")
+			if s.data, err = text.ReadDotBytes(); err != nil {
+				return err
+			}
+			text.PrintfLine("250 OK")
+		case command == "QUIT":
+			text.PrintfLine("221 tchau")
+			return nil
+		default:
+			text.PrintfLine("502 comando não implementado")
+		}
+	}
+}
+
+func TestSMTPSenderSend(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	sender := NewSMTPSender(SMTPConfig{
+		Host: "127.0.0.1",
+		Port: server.port(),
+		From: "EmpregaBem <nao-responda@empregabem.com.br>",
+	})
+
+	msg := &Message{
+		To:       "joao@example.com",
+		Subject:  "Entrevista agendada — Tech Solutions",
+		TextBody: "Olá, João!\n.linha que começa com ponto",
+		HTMLBody: "<p>Olá, <b>João</b>!</p>",
+		Attachments: []models.EmailAttachment{
+			{Filename: "convite entrevista.ics", ContentType: "text/calendar; method=REQUEST", Content: []byte("BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n")},
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := sender.Send(ctx, msg); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if err := <-server.done; err != nil {
+		t.Fatalf("servidor SMTP: %v", err)
+	}
+
+	if server.from != "nao-responda@empregabem.com.br" {
+		t.Errorf("MAIL FROM = %q", server.from)
+	}
+	if len(server.rcpt) != 1 || server.rcpt[0] != "joao@example.com" {
+		t.Errorf("RCPT TO = %v", server.rcpt)
+	}
+
+	parsed, err := netmail.ReadMessage(strings.NewReader(string(server.data)))
+	if err != nil {
+		t.Fatalf("mensagem inválida: %v", err)
+	}
+	if got := parsed.Header.Get("To"); got != "<joao@example.com>" {
+		t.Errorf("To = %q", got)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	if err != nil || subject != msg.Subject {
+		t.Errorf("Subject = %q (%v)", subject, err)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/mixed" {
+		t.Fatalf("Content-Type = %q (%v)", parsed.Header.Get("Content-Type"), err)
+	}
+
+	mixed := multipart.NewReader(parsed.Body, params["boundary"])
+	alternative, err := mixed.NextPart()
+	if err != nil {
+		t.Fatalf("parte alternative: %v", err)
+	}
+	_, altParams, _ := mime.ParseMediaType(alternative.Header.Get("Content-Type"))
+	bodies := map[string]string{}
+	parts := multipart.NewReader(alternative, altParams["boundary"])
+	for {
+		part, err := parts.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("parte do corpo: %v", err)
+		}
+		content, _ := io.ReadAll(part) // multipart decodifica o quoted-printable
+		contentType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		bodies[contentType] = string(content)
+	}
+	if bodies["text/plain"] != msg.TextBody {
+		t.Errorf("text/plain = %q", bodies["text/plain"])
+	}
+	if bodies["text/html"] != msg.HTMLBody {
+		t.Errorf("text/html = %q", bodies["text/html"])
+	}
+
+	attachment, err := mixed.NextPart()
+	if err != nil {
+		t.Fatalf("anexo: %v", err)
+	}
+	if attachment.FileName() != "convite entrevista.ics" {
+		t.Errorf("filename = %q", attachment.FileName())
+	}
+	if got := attachment.Header.Get("Content-Type"); got != "text/calendar; method=REQUEST" {
+		t.Errorf("Content-Type do anexo = %q", got)
+	}
+	encoded, _ := io.ReadAll(attachment)
+	decoded, err := decodeBase64Lines(string(encoded))
+	if err != nil || decoded != "BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n" {
+		t.Errorf("conteúdo do anexo = %q (%v)", decoded, err)
+	}
+}
+
+func TestBuildMIMEWithoutHTMLOrAttachments(t *testing.T) {
+	raw, err := buildMIME("nao-responda@empregabem.com.br", &Message{To: "Maria <maria@example.com>", Subject: "Oi", TextBody: "só texto"})
+	if err != nil {
+		t.Fatalf("buildMIME: %v", err)
+	}
+	parsed, err := netmail.ReadMessage(strings.NewReader(string(raw)))
+	if err != nil {
+		t.Fatalf("mensagem inválida: %v", err)
+	}
+	if got := parsed.Header.Get("To"); got != `"Maria" <maria@example.com>` {
+		t.Errorf("To = %q", got)
+	}
+	if mediaType, _, _ := mime.ParseMediaType(parsed.Header.Get("Content-Type")); mediaType != "multipart/alternative" {
+		t.Errorf("Content-Type = %q", mediaType)
+	}
+	if strings.Contains(string(raw), "text/html") {
+		t.Error("parte HTML gerada sem HTMLBody")
+	}
+}
+
+func TestBuildMIMERejectsHeaderInjection(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  Message
+	}{
+		{"destinatário com CRLF", Message{To: "a@example.com\r\nBcc: x@example.com", Subject: "Oi"}},
+		{"destinatário com LF", Message{To: "a@example.com\nBcc: x@example.com", Subject: "Oi"}},
+		{"destinatário inválido", Message{To: "não é email", Subject: "Oi"}},
+		{"assunto com CRLF", Message{To: "a@example.com", Subject: "Oi\r\nBcc: x@example.com"}},
+		{"nome do anexo com CRLF", Message{To: "a@example.com", Subject: "Oi", Attachments: []models.EmailAttachment{
+			{Filename: "convite.ics\r\nBcc: x@example.com", ContentType: "text/calendar"},
+		}}},
+		{"tipo do anexo com LF", Message{To: "a@example.com", Subject: "Oi", Attachments: []models.EmailAttachment{
+			{Filename: "convite.ics", ContentType: "text/calendar\nBcc: x@example.com"},
+		}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := buildMIME("nao-responda@empregabem.com.br", &tt.msg)
+			var permanent *PermanentError
+			if !errors.As(err, &permanent) {
+				t.Fatalf("esperado *PermanentError, veio %v", err)
+			}
+		})
+	}
+}
+
+func TestEnvelopeAddress(t *testing.T) {
+	tests := map[string]string{
+		"EmpregaBem <nao-responda@empregabem.com.br>": "nao-responda@empregabem.com.br",
+		"  nao-responda@empregabem.com.br ":           "nao-responda@empregabem.com.br",
+	}
+	for from, want := range tests {
+		if got := envelopeAddress(from); got != want {
+			t.Errorf("envelopeAddress(%q) = %q, esperado %q", from, got, want)
+		}
+	}
+}
+
+func decodeBase64Lines(encoded string) (string, error) {
+	decoded, err := base64.StdEncoding.DecodeString(strings.NewReplacer("\r", "", "\n", "").Replace(encoded))
+	return string(decoded), err
+}
@@ -0,0 +1,57 @@
+package mail
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+)
+
+//go:embed templates/*.html templates/*.txt
+var templateFS embed.FS
+
+// Nomes dos templates disponíveis (arquivos em templates/<nome>.html e templates/<nome>.txt)
+const (
+	TemplatePasswordReset       = "password_reset"
+	TemplateEmailVerification   = "email_verification"
+	TemplateApplicationStatus   = "application_status"
+	TemplateEmailChangeNotice   = "email_change_notice"
+	TemplateTeamInvitation      = "team_invitation"
+	TemplateCompanyVerification = "company_verification"
+	TemplateJobModeration       = "job_moderation"
+	TemplateInterviewProposed   = "interview_proposed"
+	TemplateInterviewScheduled  = "interview_scheduled"
+	TemplateInterviewCanceled   = "interview_canceled"
+	TemplateJobAlert            = "job_alert"
+)
+
+// Render gera assunto, corpo HTML e corpo texto de um template.
+// O assunto é o bloco {{define "subject"}} do arquivo .txt.
+func Render(name string, data any) (subject, html, text string, err error) {
+	textTmpl, err := texttemplate.ParseFS(templateFS, "templates/"+name+".txt")
+	if err != nil {
+		return "", "", "", fmt.Errorf("template de texto %q: %w", name, err)
+	}
+
+	var subjectBuf, textBuf bytes.Buffer
+	if err := textTmpl.ExecuteTemplate(&subjectBuf, "subject", data); err != nil {
+		return "", "", "", err
+	}
+	if err := textTmpl.ExecuteTemplate(&textBuf, name+".txt", data); err != nil {
+		return "", "", "", err
+	}
+
+	htmlTmpl, err := htmltemplate.ParseFS(templateFS, "templates/layout.html", "templates/"+name+".html")
+	if err != nil {
+		return "", "", "", fmt.Errorf("template HTML %q: %w", name, err)
+	}
+
+	var htmlBuf bytes.Buffer
+	if err := htmlTmpl.ExecuteTemplate(&htmlBuf, "layout", data); err != nil {
+		return "", "", "", err
+	}
+
+	return strings.TrimSpace(subjectBuf.String()), htmlBuf.String(), textBuf.String(), nil
+}
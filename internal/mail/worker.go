@@ -0,0 +1,109 @@
+package mail
+
+import (
+	"context"
+	"empregabemapi/internal/models"
+	"empregabemapi/internal/repository"
+	"errors"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+const (
+	// Número máximo de tentativas antes de marcar o email como falho
+	maxSendAttempts = 8
+	// Backoff inicial entre tentativas (dobra a cada falha)
+	baseRetryDelay = time.Minute
+	// Backoff máximo entre tentativas
+	maxRetryDelay = 6 * time.Hour
+)
+
+// Worker drena o outbox enviando os emails pendentes com retry exponencial
+type Worker struct {
+	outbox   *repository.EmailOutboxRepository
+	sender   Sender
+	interval time.Duration
+}
+
+func NewWorker(outbox *repository.EmailOutboxRepository, sender Sender, interval time.Duration) *Worker {
+	return &Worker{
+		outbox:   outbox,
+		sender:   sender,
+		interval: interval,
+	}
+}
+
+// Run processa o outbox até o contexto ser cancelado
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.drain(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// drain envia todos os emails prontos no momento
+func (w *Worker) drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		email, err := w.outbox.ClaimNext(ctx)
+		if err != nil {
+			if !errors.Is(err, mongo.ErrNoDocuments) {
+				log.Printf("[mail] erro ao buscar emails pendentes: %v", err)
+			}
+			return
+		}
+		w.deliver(ctx, email)
+	}
+}
+
+func (w *Worker) deliver(ctx context.Context, email *models.OutboxEmail) {
+	sendCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	err := w.sender.Send(sendCtx, &Message{
+		To:          email.To,
+		Subject:     email.Subject,
+		HTMLBody:    email.HTMLBody,
+		TextBody:    email.TextBody,
+		Attachments: email.Attachments,
+	})
+	if err == nil {
+		if err := w.outbox.MarkSent(ctx, email.ID); err != nil {
+			log.Printf("[mail] erro ao marcar email %s como enviado: %v", email.ID.Hex(), err)
+		}
+		return
+	}
+
+	var permanent *PermanentError
+	if errors.As(err, &permanent) || email.Attempts >= maxSendAttempts {
+		log.Printf("[mail] email %s para %s falhou definitivamente: %v", email.ID.Hex(), email.To, err)
+		w.outbox.MarkFailed(ctx, email.ID, err.Error())
+		return
+	}
+
+	next := time.Now().Add(retryDelay(email.Attempts))
+	log.Printf("[mail] falha ao enviar email %s (tentativa %d), nova tentativa em %s: %v",
+		email.ID.Hex(), email.Attempts, next.Format(time.RFC3339), err)
+	w.outbox.MarkRetry(ctx, email.ID, err.Error(), next)
+}
+
+// retryDelay calcula o backoff exponencial para a tentativa informada (1, 2, 3...)
+func retryDelay(attempt int) time.Duration {
+	delay := baseRetryDelay
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= maxRetryDelay {
+			return maxRetryDelay
+		}
+	}
+	return delay
+}
@@ -0,0 +1,198 @@
+// Package matching compara os requisitos das vagas com as habilidades dos candidatos.
+package matching
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// Result é a aderência do candidato à vaga: percentual dos requisitos atendidos e quais
+// requisitos foram ou não encontrados nas habilidades (como escritos na vaga)
+type Result struct {
+	Score   int      `json:"score"` // 0 a 100
+	Matched []string `json:"matched"`
+	Missing []string `json:"missing"`
+}
+
+// Apelidos e grafias alternativas → nome canônico. As chaves já estão normalizadas
+// (minúsculas, sem acentos, separadores viram espaço).
+var aliases = map[string]string{
+	"golang":                  "go",
+	"go lang":                 "go",
+	"js":                      "javascript",
+	"ecmascript":              "javascript",
+	"es6":                     "javascript",
+	"ts":                      "typescript",
+	"node":                    "nodejs",
+	"node.js":                 "nodejs",
+	"node js":                 "nodejs",
+	"react.js":                "react",
+	"reactjs":                 "react",
+	"react js":                "react",
+	"vue.js":                  "vue",
+	"vuejs":                   "vue",
+	"vue js":                  "vue",
+	"next.js":                 "nextjs",
+	"next js":                 "nextjs",
+	"angular.js":              "angularjs",
+	"postgres":                "postgresql",
+	"psql":                    "postgresql",
+	"mongo":                   "mongodb",
+	"k8s":                     "kubernetes",
+	"py":                      "python",
+	"python3":                 "python",
+	"csharp":                  "c#",
+	"c sharp":                 "c#",
+	"cpp":                     "c++",
+	"dotnet":                  ".net",
+	"dot net":                 ".net",
+	"net core":                ".net",
+	".net core":               ".net",
+	"amazon web services":     "aws",
+	"google cloud":            "gcp",
+	"google cloud platform":   "gcp",
+	"ms sql":                  "sql server",
+	"mssql":                   "sql server",
+	"ml":                      "machine learning",
+	"aprendizado de maquina":  "machine learning",
+	"ux ui":                   "ui ux",
+	"english":                 "ingles",
+	"spanish":                 "espanhol",
+	"excel avancado":          "excel",
+	"microsoft excel":         "excel",
+	"power bi":                "powerbi",
+	"ci cd":                   "ci/cd",
+	"integracao continua":     "ci/cd",
+	"rest api":                "rest",
+	"restful":                 "rest",
+	"api rest":                "rest",
+	"apis rest":               "rest",
+	"scrum master":            "scrum",
+	"metodologias ageis":      "agile",
+	"metodologia agil":        "agile",
+	"desenvolvimento agil":    "agile",
+	"atendimento ao cliente":  "atendimento",
+	"carteira de motorista":   "cnh",
+	"carteira de habilitacao": "cnh",
+}
+
+// Maior apelido em palavras (para a substituição dentro de frases)
+const maxAliasWords = 3
+
+var accentReplacer = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a",
+	"é", "e", "ê", "e", "è", "e",
+	"í", "i", "î", "i",
+	"ó", "o", "ô", "o", "õ", "o", "ö", "o",
+	"ú", "u", "ü", "u",
+	"ç", "c",
+)
+
+// tokens normaliza o texto (minúsculo, sem acentos, apelidos resolvidos) e o separa em palavras.
+// "+", "#" e "." fazem parte da palavra (c++, c#, node.js); ponto final é descartado.
+func tokens(s string) []string {
+	s = accentReplacer.Replace(strings.ToLower(s))
+	words := strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '+' && r != '#' && r != '.'
+	})
+
+	cleaned := words[:0]
+	for _, word := range words {
+		word = strings.TrimRight(word, ".")
+		if word != "" {
+			cleaned = append(cleaned, word)
+		}
+	}
+	return resolveAliases(cleaned)
+}
+
+// resolveAliases troca as sequências de palavras conhecidas pelo nome canônico (a mais longa primeiro)
+func resolveAliases(words []string) []string {
+	var out []string
+	for i := 0; i < len(words); {
+		replaced := false
+		for n := min(maxAliasWords, len(words)-i); n >= 1; n-- {
+			if canonical, ok := aliases[strings.Join(words[i:i+n], " ")]; ok {
+				out = append(out, strings.Fields(canonical)...)
+				i += n
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			out = append(out, words[i])
+			i++
+		}
+	}
+	return out
+}
+
+// Normalize retorna a forma canônica de uma habilidade ou requisito (ex: "Golang" → "go",
+// "React.js" → "react", "Inglês" → "ingles")
+func Normalize(skill string) string {
+	return strings.Join(tokens(skill), " ")
+}
+
+// Score calcula a aderência das habilidades do candidato aos requisitos da vaga. Um requisito é
+// atendido quando é igual a uma habilidade ou a contém como palavra(s) inteira(s)
+// (ex: "Experiência com Docker" é atendido por "docker"). Retorna nil se a vaga não tem requisitos.
+func Score(requirements, skills []string) *Result {
+	var skillTokens [][]string
+	for _, skill := range skills {
+		if t := tokens(skill); len(t) > 0 {
+			skillTokens = append(skillTokens, t)
+		}
+	}
+
+	result := &Result{Matched: []string{}, Missing: []string{}}
+	seen := map[string]bool{}
+	for _, requirement := range requirements {
+		requirementTokens := tokens(requirement)
+		key := strings.Join(requirementTokens, " ")
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		label := strings.TrimSpace(requirement)
+		if satisfied(requirementTokens, skillTokens) {
+			result.Matched = append(result.Matched, label)
+		} else {
+			result.Missing = append(result.Missing, label)
+		}
+	}
+
+	total := len(result.Matched) + len(result.Missing)
+	if total == 0 {
+		return nil
+	}
+	result.Score = int(math.Round(float64(len(result.Matched)) * 100 / float64(total)))
+	return result
+}
+
+// satisfied indica se alguma habilidade aparece no requisito como sequência de palavras inteiras
+func satisfied(requirement []string, skills [][]string) bool {
+	for _, skill := range skills {
+		if containsSequence(requirement, skill) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsSequence(words, sequence []string) bool {
+	for i := 0; i+len(sequence) <= len(words); i++ {
+		match := true
+		for j := range sequence {
+			if words[i+j] != sequence[j] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
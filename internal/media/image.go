@@ -0,0 +1,204 @@
+package media
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif" // registra o decoder de GIF (só o primeiro quadro é usado)
+	"image/jpeg"
+	"image/png"
+	"math"
+	"net/http"
+	"strconv"
+)
+
+// MaxUploadSize é o tamanho máximo do arquivo enviado
+const MaxUploadSize = 5 << 20 // 5MB
+
+// Imagens acima disso são recusadas antes de decodificar (proteção contra "decompression bombs")
+const maxPixels = 40_000_000
+
+const jpegQuality = 85
+
+// Formatos aceitos, detectados pelo conteúdo do arquivo (não pela extensão nem pelo Content-Type enviado)
+var acceptedTypes = map[string]string{
+	"image/png":  "png",
+	"image/jpeg": "jpeg",
+	"image/gif":  "gif",
+}
+
+// ValidationError é um problema na imagem enviada, com mensagem para o usuário
+type ValidationError struct {
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+func invalid(format string, args ...any) error {
+	return &ValidationError{Message: fmt.Sprintf(format, args...)}
+}
+
+// Spec define as regras e as variantes geradas para um tipo de imagem
+type Spec struct {
+	Kind      string // "logo" ou "cover"
+	MinWidth  int
+	MinHeight int
+	MaxWidth  int
+	MaxHeight int
+	Landscape bool   // exige largura maior que a altura
+	Sizes     []int  // variantes geradas, da menor para a maior
+	ByWidth   bool   // Sizes é a largura; senão é o lado maior (a imagem cabe num quadrado Size x Size)
+	Format    string // "png" ou "jpeg"
+}
+
+var (
+	// Logo: variantes 64/128/256px em PNG (preserva transparência)
+	LogoSpec = Spec{
+		Kind:     "logo",
+		MinWidth: 64, MinHeight: 64,
+		MaxWidth: 4096, MaxHeight: 4096,
+		Sizes:  []int{64, 128, 256},
+		Format: "png",
+	}
+	// Capa: variantes por largura em JPEG (fotos ficariam grandes demais em PNG)
+	CoverSpec = Spec{
+		Kind:     "cover",
+		MinWidth: 800, MinHeight: 200,
+		MaxWidth: 8000, MaxHeight: 8000,
+		Landscape: true,
+		Sizes:     []int{640, 1280, 1920},
+		ByWidth:   true,
+		Format:    "jpeg",
+	}
+)
+
+// LargestSize é a variante usada quando só cabe uma URL (ex: campo logo)
+func (s Spec) LargestSize() string {
+	return strconv.Itoa(s.Sizes[len(s.Sizes)-1])
+}
+
+// Variant é uma versão redimensionada e já codificada da imagem
+type Variant struct {
+	Size        string // chave da variante ("64", "1280"...)
+	Width       int
+	Height      int
+	Ext         string
+	ContentType string
+	Data        []byte
+}
+
+// Result é a imagem processada: dimensões originais (já na orientação correta) e variantes
+type Result struct {
+	Width    int
+	Height   int
+	Variants []Variant
+}
+
+// Process valida o arquivo enviado e gera as variantes da spec.
+// A imagem é decodificada e recodificada do zero, então EXIF, GPS e outros metadados
+// nunca chegam ao storage; a orientação do EXIF é aplicada antes de ser descartada.
+// Erros de validação são *ValidationError.
+func Process(data []byte, spec Spec) (*Result, error) {
+	if len(data) == 0 {
+		return nil, invalid("Arquivo vazio")
+	}
+	if len(data) > MaxUploadSize {
+		return nil, invalid("Arquivo maior que %dMB", MaxUploadSize>>20)
+	}
+
+	format, ok := acceptedTypes[http.DetectContentType(data)]
+	if !ok {
+		return nil, invalid("Formato não suportado. Envie uma imagem PNG, JPEG ou GIF")
+	}
+
+	cfg, decodedFormat, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || decodedFormat != format {
+		return nil, invalid("Imagem inválida ou corrompida")
+	}
+	// Confere as dimensões da spec antes de decodificar: só o cabeçalho foi lido até aqui.
+	// A orientação do EXIF pode trocar largura e altura, então cada lado é comparado ao maior limite.
+	limit := max(spec.MaxWidth, spec.MaxHeight)
+	if cfg.Width > limit || cfg.Height > limit || cfg.Width*cfg.Height > maxPixels {
+		return nil, invalid("Imagem grande demais (máximo %dx%d px)", spec.MaxWidth, spec.MaxHeight)
+	}
+
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, invalid("Imagem inválida ou corrompida")
+	}
+
+	img := toRGBA(decoded)
+	if format == "jpeg" {
+		img = orient(img, jpegOrientation(data))
+	}
+
+	width, height := img.Bounds().Dx(), img.Bounds().Dy()
+	if width < spec.MinWidth || height < spec.MinHeight {
+		return nil, invalid("Imagem pequena demais (mínimo %dx%d px)", spec.MinWidth, spec.MinHeight)
+	}
+	if width > spec.MaxWidth || height > spec.MaxHeight {
+		return nil, invalid("Imagem grande demais (máximo %dx%d px)", spec.MaxWidth, spec.MaxHeight)
+	}
+	if spec.Landscape && width <= height {
+		return nil, invalid("A imagem de capa deve estar na horizontal (largura maior que a altura)")
+	}
+
+	result := &Result{Width: width, Height: height}
+	for _, size := range spec.Sizes {
+		w, h := fit(width, height, size, spec.ByWidth)
+		variant, err := encode(resize(img, w, h), spec.Format)
+		if err != nil {
+			return nil, err
+		}
+		variant.Size = strconv.Itoa(size)
+		variant.Width, variant.Height = w, h
+		result.Variants = append(result.Variants, *variant)
+	}
+
+	return result, nil
+}
+
+// fit calcula as dimensões da variante mantendo a proporção (nunca amplia a imagem)
+func fit(width, height, size int, byWidth bool) (int, int) {
+	scale := float64(size) / float64(width)
+	if !byWidth {
+		scale = math.Min(scale, float64(size)/float64(height))
+	}
+	if scale >= 1 {
+		return width, height
+	}
+	w := max(1, int(math.Round(float64(width)*scale)))
+	h := max(1, int(math.Round(float64(height)*scale)))
+	return w, h
+}
+
+func toRGBA(src image.Image) *image.RGBA {
+	b := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(dst, dst.Bounds(), src, b.Min, draw.Src)
+	return dst
+}
+
+func encode(img *image.RGBA, format string) (*Variant, error) {
+	var buf bytes.Buffer
+	if format == "jpeg" {
+		// JPEG não tem transparência: compõe sobre fundo branco em vez de preto
+		flat := image.NewRGBA(img.Bounds())
+		draw.Draw(flat, flat.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+		draw.Draw(flat, flat.Bounds(), img, image.Point{}, draw.Over)
+		if err := jpeg.Encode(&buf, flat, &jpeg.Options{Quality: jpegQuality}); err != nil {
+			return nil, err
+		}
+		return &Variant{Ext: "jpg", ContentType: "image/jpeg", Data: buf.Bytes()}, nil
+	}
+
+	encoder := png.Encoder{CompressionLevel: png.BestCompression}
+	if err := encoder.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return &Variant{Ext: "png", ContentType: "image/png", Data: buf.Bytes()}, nil
+}
@@ -0,0 +1,61 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"empregabemapi/internal/storage"
+	"log"
+	"strings"
+)
+
+// Library grava as variantes no storage e monta as URLs públicas (servidas em GET /media/{chave})
+type Library struct {
+	store   storage.Store
+	baseURL string
+}
+
+func NewLibrary(store storage.Store, baseURL string) *Library {
+	return &Library{
+		store:   store,
+		baseURL: strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// URL monta a URL pública de um arquivo (ex: URL("companies/{id}/logo/{versao}/256.png"))
+func (l *Library) URL(key string) string {
+	return l.baseURL + "/" + key
+}
+
+// Open abre um arquivo do storage para ser servido
+func (l *Library) Open(ctx context.Context, key string) (*storage.Object, error) {
+	return l.store.Open(ctx, key)
+}
+
+// Save grava as variantes em prefix/{tamanho}.{ext} e retorna as chaves gravadas e as URLs por tamanho.
+// Cada upload usa um prefixo novo, então as URLs nunca mudam de conteúdo e podem ter cache longo.
+// Se uma variante falhar, as já gravadas são removidas.
+func (l *Library) Save(ctx context.Context, prefix string, result *Result) ([]string, map[string]string, error) {
+	keys := make([]string, 0, len(result.Variants))
+	urls := make(map[string]string, len(result.Variants))
+
+	for _, v := range result.Variants {
+		key := prefix + "/" + v.Size + "." + v.Ext
+		if err := l.store.Put(ctx, key, bytes.NewReader(v.Data), v.ContentType); err != nil {
+			l.Delete(ctx, keys)
+			return nil, nil, err
+		}
+		keys = append(keys, key)
+		urls[v.Size] = l.URL(key)
+	}
+
+	return keys, urls, nil
+}
+
+// Delete remove os arquivos de uma imagem substituída ou removida (falhas só são registradas)
+func (l *Library) Delete(ctx context.Context, keys []string) {
+	for _, key := range keys {
+		if err := l.store.Delete(ctx, key); err != nil {
+			log.Printf("Erro ao remover arquivo %s do storage: %v", key, err)
+		}
+	}
+}
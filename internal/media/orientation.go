@@ -0,0 +1,114 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+)
+
+const exifOrientationTag = 0x0112
+
+// jpegOrientation lê a tag Orientation (1-8) do EXIF de um JPEG; 1 (normal) se não houver.
+// Fotos de celular costumam vir "deitadas" com a rotação só indicada no EXIF, que é descartado
+// na recodificação, então a rotação precisa ser aplicada nos pixels.
+func jpegOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return 1
+		}
+		marker := data[i+1]
+		if marker == 0xDA || marker == 0xD9 { // início dos dados da imagem: não há mais metadados
+			return 1
+		}
+		length := int(binary.BigEndian.Uint16(data[i+2 : i+4]))
+		if length < 2 || i+2+length > len(data) {
+			return 1
+		}
+		segment := data[i+4 : i+2+length]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return tiffOrientation(segment[6:])
+		}
+		i += 2 + length
+	}
+	return 1
+}
+
+// tiffOrientation procura a orientação no IFD0 do bloco TIFF do EXIF
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	ifd := int(order.Uint32(tiff[4:8]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 1
+	}
+	entries := int(order.Uint16(tiff[ifd : ifd+2]))
+	for e := 0; e < entries; e++ {
+		off := ifd + 2 + e*12
+		if off+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[off:off+2]) == exifOrientationTag {
+			o := int(order.Uint16(tiff[off+8 : off+10]))
+			if o >= 1 && o <= 8 {
+				return o
+			}
+			return 1
+		}
+	}
+	return 1
+}
+
+// orient aplica a transformação da tag Orientation, deixando a imagem na posição de exibição
+func orient(src *image.RGBA, orientation int) *image.RGBA {
+	if orientation <= 1 || orientation > 8 {
+		return src
+	}
+
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	dw, dh := w, h
+	if orientation >= 5 { // 5-8 giram 90°: largura e altura trocam
+		dw, dh = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var tx, ty int
+			switch orientation {
+			case 2: // espelhada na horizontal
+				tx, ty = w-1-x, y
+			case 3: // girada 180°
+				tx, ty = w-1-x, h-1-y
+			case 4: // espelhada na vertical
+				tx, ty = x, h-1-y
+			case 5: // transposta
+				tx, ty = y, x
+			case 6: // girar 90° no sentido horário
+				tx, ty = h-1-y, x
+			case 7: // transversa
+				tx, ty = h-1-y, w-1-x
+			case 8: // girar 90° no sentido anti-horário
+				tx, ty = y, w-1-x
+			}
+			s := src.PixOffset(x, y)
+			d := dst.PixOffset(tx, ty)
+			copy(dst.Pix[d:d+4], src.Pix[s:s+4])
+		}
+	}
+	return dst
+}
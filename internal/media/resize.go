@@ -0,0 +1,52 @@
+package media
+
+import "image"
+
+// resize reduz a imagem pela média da área coberta por cada pixel de destino (box filter).
+// Só é usado para reduzir; com os mesmos tamanhos devolve a própria imagem.
+func resize(src *image.RGBA, width, height int) *image.RGBA {
+	srcW, srcH := src.Bounds().Dx(), src.Bounds().Dy()
+	if width == srcW && height == srcH {
+		return src
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for dy := 0; dy < height; dy++ {
+		sy0, sy1 := span(dy, height, srcH)
+		for dx := 0; dx < width; dx++ {
+			sx0, sx1 := span(dx, width, srcW)
+
+			// Os canais do RGBA são pré-multiplicados pelo alfa, então a média simples
+			// não escurece as bordas de áreas transparentes
+			var r, g, b, a, n uint64
+			for sy := sy0; sy < sy1; sy++ {
+				i := src.PixOffset(sx0, sy)
+				for sx := sx0; sx < sx1; sx++ {
+					r += uint64(src.Pix[i])
+					g += uint64(src.Pix[i+1])
+					b += uint64(src.Pix[i+2])
+					a += uint64(src.Pix[i+3])
+					n++
+					i += 4
+				}
+			}
+
+			j := dst.PixOffset(dx, dy)
+			dst.Pix[j] = uint8((r + n/2) / n)
+			dst.Pix[j+1] = uint8((g + n/2) / n)
+			dst.Pix[j+2] = uint8((b + n/2) / n)
+			dst.Pix[j+3] = uint8((a + n/2) / n)
+		}
+	}
+	return dst
+}
+
+// span retorna o intervalo [from, to) de pixels de origem coberto pelo pixel de destino d
+func span(d, dstSize, srcSize int) (int, int) {
+	from := d * srcSize / dstSize
+	to := (d + 1) * srcSize / dstSize
+	if to <= from {
+		to = from + 1
+	}
+	return from, to
+}
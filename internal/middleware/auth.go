@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"context"
+	"empregabemapi/companies"
 	"empregabemapi/internal/auth"
 	"encoding/json"
 	"net/http"
@@ -13,8 +14,22 @@ type contextKey string
 const (
 	UserIDKey   contextKey = "user_id"
 	UserTypeKey contextKey = "user_type"
+	// Apenas para empresas: membro da equipe (vazio para o dono) e papel
+	MemberIDKey    contextKey = "member_id"
+	CompanyRoleKey contextKey = "company_role"
 )
 
+// SessionValidator verifica se um token válido ainda pertence a uma sessão ativa
+// (ex: sessões revogadas após troca de senha). Retorna erro para rejeitar o token.
+type SessionValidator func(ctx context.Context, claims *auth.Claims) error
+
+var sessionValidator SessionValidator
+
+// SetSessionValidator configura a verificação de sessão usada pelo AuthMiddleware
+func SetSessionValidator(v SessionValidator) {
+	sessionValidator = v
+}
+
 // AuthMiddleware verifica se o usuário está autenticado
 func AuthMiddleware(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -76,18 +91,81 @@ func AuthMiddleware(next http.HandlerFunc) http.HandlerFunc {
 			return
 		}
 
-		// Adiciona informações do usuário no context
-		ctx := context.WithValue(r.Context(), UserIDKey, claims.ID)
-		ctx = context.WithValue(ctx, UserTypeKey, claims.Type)
+		// Verifica se a sessão não foi revogada
+		if sessionValidator != nil {
+			if err := sessionValidator(r.Context(), claims); err != nil {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusUnauthorized)
+				json.NewEncoder(w).Encode(map[string]string{
+					"erro": "Sessão encerrada. Faça login novamente.",
+				})
+				return
+			}
+		}
 
-		next(w, r.WithContext(ctx))
+		next(w, r.WithContext(withClaims(r.Context(), claims)))
 	}
 }
 
-// CompanyOnly permite apenas empresas
-func CompanyOnly(next http.HandlerFunc) http.HandlerFunc {
-	return AuthMiddleware(func(w http.ResponseWriter, r *http.Request) {
-		userType := r.Context().Value(UserTypeKey).(string)
+// withClaims adiciona as informações do usuário autenticado no context
+func withClaims(ctx context.Context, claims *auth.Claims) context.Context {
+	ctx = context.WithValue(ctx, UserIDKey, claims.ID)
+	ctx = context.WithValue(ctx, UserTypeKey, claims.Type)
+	if claims.Type == "company" {
+		role := companies.RoleOwner
+		if claims.MemberID != "" {
+			role = companies.Role(claims.Role)
+		}
+		ctx = context.WithValue(ctx, MemberIDKey, claims.MemberID)
+		ctx = context.WithValue(ctx, CompanyRoleKey, role)
+	}
+	return ctx
+}
+
+// OptionalAuth identifica o usuário em rotas públicas que mostram dados extras para quem está
+// logado (ex: aderência do candidato na vaga). Sem token, ou com token inválido, expirado ou de
+// sessão encerrada, a requisição segue como anônima.
+func OptionalAuth(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		authHeader := r.Header.Get("Authorization")
+		tokenStr, ok := strings.CutPrefix(authHeader, "Bearer ")
+		if !ok || len(authHeader) > 1024 {
+			next(w, r)
+			return
+		}
+
+		claims, err := auth.ValidateToken(strings.TrimSpace(tokenStr))
+		if err == nil && sessionValidator != nil {
+			err = sessionValidator(r.Context(), claims)
+		}
+		if err != nil {
+			next(w, r)
+			return
+		}
+
+		next(w, r.WithContext(withClaims(r.Context(), claims)))
+	}
+}
+
+// EventStreamAuth é o AuthMiddleware para conexões SSE: como o EventSource do navegador não envia
+// headers, o mesmo token JWT também é aceito em ?access_token=
+func EventStreamAuth(next http.HandlerFunc) http.HandlerFunc {
+	authenticated := AuthMiddleware(next)
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" {
+			if token := r.URL.Query().Get("access_token"); token != "" {
+				r.Header.Set("Authorization", "Bearer "+token)
+			}
+		}
+		authenticated(w, r)
+	}
+}
+
+// CompanyPermission permite apenas usuários de empresa cujo papel tem a permissão.
+// Deve ser usado dentro do AuthMiddleware.
+func CompanyPermission(perm companies.Permission, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userType, _ := r.Context().Value(UserTypeKey).(string)
 		if userType != "company" {
 			w.Header().Set("Content-Type", "application/json")
 			w.WriteHeader(http.StatusForbidden)
@@ -96,11 +174,32 @@ func CompanyOnly(next http.HandlerFunc) http.HandlerFunc {
 			})
 			return
 		}
+
+		if !CompanyRole(r).Can(perm) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusForbidden)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "Seu papel na empresa não permite esta ação",
+			})
+			return
+		}
 		next(w, r)
-	})
+	}
 }
 
-// CandidateOnly permite apenas candidatos
+// CompanyRole retorna o papel do usuário de empresa autenticado
+func CompanyRole(r *http.Request) companies.Role {
+	role, _ := r.Context().Value(CompanyRoleKey).(companies.Role)
+	return role
+}
+
+// CompanyMemberID retorna o ID do membro autenticado (vazio para o dono da empresa)
+func CompanyMemberID(r *http.Request) string {
+	memberID, _ := r.Context().Value(MemberIDKey).(string)
+	return memberID
+}
+
+// CandidateOnly permite apenas candidatos (já inclui AuthMiddleware)
 func CandidateOnly(next http.HandlerFunc) http.HandlerFunc {
 	return AuthMiddleware(func(w http.ResponseWriter, r *http.Request) {
 		userType := r.Context().Value(UserTypeKey).(string)
@@ -115,3 +214,19 @@ func CandidateOnly(next http.HandlerFunc) http.HandlerFunc {
 		next(w, r)
 	})
 }
+
+// AdminOnly permite apenas administradores da plataforma (já inclui AuthMiddleware)
+func AdminOnly(next http.HandlerFunc) http.HandlerFunc {
+	return AuthMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		userType := r.Context().Value(UserTypeKey).(string)
+		if userType != "admin" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusForbidden)
+			json.NewEncoder(w).Encode(map[string]string{
+				"erro": "Acesso restrito a administradores",
+			})
+			return
+		}
+		next(w, r)
+	})
+}
@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// trustedProxies são as redes dos proxies/load balancers cujos headers
+// X-Forwarded-For e X-Real-IP são confiáveis
+var trustedProxies []*net.IPNet
+
+// SetTrustedProxies configura a lista de proxies confiáveis (IPs ou CIDRs).
+// Entradas inválidas são ignoradas e devolvidas para log.
+func SetTrustedProxies(entries []string) (invalid []string) {
+	var nets []*net.IPNet
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if !strings.Contains(entry, "/") {
+			if ip := net.ParseIP(entry); ip != nil && ip.To4() != nil {
+				entry += "/32"
+			} else {
+				entry += "/128"
+			}
+		}
+		_, ipNet, err := net.ParseCIDR(entry)
+		if err != nil {
+			invalid = append(invalid, entry)
+			continue
+		}
+		nets = append(nets, ipNet)
+	}
+	trustedProxies = nets
+	return invalid
+}
+
+func isTrustedProxy(ip net.IP) bool {
+	for _, ipNet := range trustedProxies {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientIP extrai o IP real do cliente.
+// Headers de proxy só são considerados quando a conexão vem de um proxy confiável;
+// no X-Forwarded-For o IP do cliente é o primeiro, da direita para a esquerda, que não é um proxy confiável.
+func ClientIP(r *http.Request) string {
+	remote := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(remote); err == nil {
+		remote = host
+	}
+
+	remoteIP := net.ParseIP(remote)
+	if remoteIP == nil || !isTrustedProxy(remoteIP) {
+		return remote
+	}
+
+	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+		hops := strings.Split(forwarded, ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop := strings.TrimSpace(hops[i])
+			ip := net.ParseIP(hop)
+			if ip == nil {
+				break
+			}
+			if !isTrustedProxy(ip) || i == 0 {
+				return hop
+			}
+		}
+	}
+
+	if realIP := net.ParseIP(strings.TrimSpace(r.Header.Get("X-Real-IP"))); realIP != nil {
+		return realIP.String()
+	}
+
+	return remote
+}
@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"empregabemapi/internal/ratelimit"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+)
+
+// RateLimitMiddleware aplica o limite de taxa por IP de acordo com a regra da rota
+// e informa o estado do limite nos headers RateLimit-* (draft IETF)
+func RateLimitMiddleware(limiter *ratelimit.Limiter) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Preflight CORS não conta no limite
+			if r.Method == http.MethodOptions {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			rule := limiter.RuleFor(r)
+			result, err := limiter.Allow(r.Context(), ClientIP(r), rule)
+			if err != nil {
+				// Falha do store não deve derrubar a API
+				log.Printf("[ratelimit] erro no store: %v", err)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if !writeRateLimit(w, rule, result, "Muitas requisições. Tente novamente mais tarde.") {
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// UserRateLimit aplica um limite adicional por usuário autenticado (não por IP) a uma rota.
+// Usuários de empresa contam por membro: cada pessoa da equipe tem o próprio limite.
+func UserRateLimit(limiter *ratelimit.Limiter, rule ratelimit.Rule, message string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userType, _ := r.Context().Value(UserTypeKey).(string)
+		userID, _ := r.Context().Value(UserIDKey).(string)
+		key := userType + ":" + userID
+		if memberID := CompanyMemberID(r); memberID != "" {
+			key += ":" + memberID
+		}
+
+		result, err := limiter.Allow(r.Context(), key, rule)
+		if err != nil {
+			log.Printf("[ratelimit] erro no store: %v", err)
+			next(w, r)
+			return
+		}
+
+		if !writeRateLimit(w, rule, result, message) {
+			return
+		}
+		next(w, r)
+	}
+}
+
+// writeRateLimit informa o estado do limite nos headers RateLimit-* e responde 429 se ele
+// foi excedido. Retorna false quando a requisição foi barrada.
+func writeRateLimit(w http.ResponseWriter, rule ratelimit.Rule, result ratelimit.Result, message string) bool {
+	resetSeconds := int(math.Ceil(result.Reset.Seconds()))
+	w.Header().Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+	w.Header().Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	w.Header().Set("RateLimit-Reset", strconv.Itoa(resetSeconds))
+	w.Header().Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", rule.Limit, int(rule.Window.Seconds())))
+
+	if !result.Allowed {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Retry-After", strconv.Itoa(resetSeconds))
+		w.WriteHeader(http.StatusTooManyRequests)
+		json.NewEncoder(w).Encode(map[string]string{
+			"erro": message,
+		})
+		return false
+	}
+	return true
+}
@@ -5,6 +5,11 @@ import (
 	"strings"
 )
 
+const (
+	maxBodySize       = 1 << 20 // 1MB
+	maxUploadBodySize = 6 << 20 // uploads multipart (imagens até 5MB); cada handler aplica o próprio limite
+)
+
 // SecurityHeadersMiddleware adiciona headers de segurança em todas as respostas
 func SecurityHeadersMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -71,77 +76,15 @@ func CORSMiddleware(allowedOrigins []string) func(http.Handler) http.Handler {
 	}
 }
 
-// RateLimitMiddleware implementa rate limiting básico por IP
-type RateLimiter struct {
-	requests map[string]int
-	limit    int
-}
-
-func NewRateLimiter(limit int) *RateLimiter {
-	return &RateLimiter{
-		requests: make(map[string]int),
-		limit:    limit,
-	}
-}
-
-func (rl *RateLimiter) Middleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Extrair IP real considerando proxies
-		ip := getRealIP(r)
-
-		// Verificar limite
-		if rl.requests[ip] >= rl.limit {
-			w.Header().Set("Content-Type", "application/json")
-			w.Header().Set("Retry-After", "60")
-			w.WriteHeader(http.StatusTooManyRequests)
-			w.Write([]byte(`{"erro":"Muitas requisições. Tente novamente mais tarde."}`))
-			return
-		}
-
-		// Incrementar contador
-		rl.requests[ip]++
-
-		next.ServeHTTP(w, r)
-
-		// Limpar contador após requisição (simplificado)
-		// Em produção, use um sistema de janela deslizante com Redis
-		if rl.requests[ip] > 0 {
-			rl.requests[ip]--
-		}
-	})
-}
-
-// getRealIP extrai o IP real do cliente considerando proxies
-func getRealIP(r *http.Request) string {
-	// Tentar X-Forwarded-For primeiro (proxy/load balancer)
-	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
-		// Pegar primeiro IP da lista
-		ips := strings.Split(forwarded, ",")
-		if len(ips) > 0 {
-			return strings.TrimSpace(ips[0])
-		}
-	}
-
-	// Tentar X-Real-IP
-	if realIP := r.Header.Get("X-Real-IP"); realIP != "" {
-		return realIP
-	}
-
-	// Fallback para RemoteAddr
-	ip := r.RemoteAddr
-	// Remover porta se presente
-	if idx := strings.LastIndex(ip, ":"); idx != -1 {
-		ip = ip[:idx]
-	}
-
-	return ip
-}
-
 // SanitizeInputMiddleware limpa inputs de caracteres perigosos
 func SanitizeInputMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Limitar tamanho do body para evitar ataques de DoS
-		r.Body = http.MaxBytesReader(w, r.Body, 1048576) // 1MB
+		limit := int64(maxBodySize)
+		if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+			limit = maxUploadBodySize
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, limit)
 
 		next.ServeHTTP(w, r)
 	})
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// AuditLog registra ações sensíveis (bloqueios de login, ações administrativas etc.)
+type AuditLog struct {
+	ID         bson.ObjectID          `bson:"_id,omitempty" json:"id"`
+	Action     string                 `bson:"action" json:"action"`         // ex: "auth.account_locked"
+	ActorType  string                 `bson:"actor_type" json:"actor_type"` // "system", "company", "candidate"...
+	ActorID    string                 `bson:"actor_id,omitempty" json:"actor_id,omitempty"`
+	TargetType string                 `bson:"target_type,omitempty" json:"target_type,omitempty"`
+	TargetID   string                 `bson:"target_id,omitempty" json:"target_id,omitempty"`
+	IP         string                 `bson:"ip,omitempty" json:"ip,omitempty"`
+	Details    map[string]interface{} `bson:"details,omitempty" json:"details,omitempty"`
+	CreatedAt  time.Time              `bson:"created_at" json:"created_at"`
+}
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"math"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Status de moderação da avaliação. Só avaliações aprovadas aparecem no perfil público.
+const (
+	ReviewStatusPending  = "pending"
+	ReviewStatusApproved = "approved"
+	ReviewStatusRejected = "rejected"
+)
+
+// CompanyReview é a avaliação do processo seletivo de uma empresa feita por um candidato
+// que se candidatou a uma de suas vagas (uma por candidato e empresa).
+// As notas vão de 1 a 5; em InterviewDifficulty, 1 é muito fácil e 5 muito difícil.
+type CompanyReview struct {
+	ID                  bson.ObjectID `bson:"_id,omitempty" json:"id"`
+	CompanyID           bson.ObjectID `bson:"company_id" json:"company_id"`
+	CandidateID         bson.ObjectID `bson:"candidate_id" json:"candidate_id"`
+	ApplicationID       bson.ObjectID `bson:"application_id" json:"application_id"` // candidatura que deu direito à avaliação
+	Rating              int           `bson:"rating" json:"rating"`                 // nota geral do processo
+	Communication       int           `bson:"communication" json:"communication"`
+	ResponseTime        int           `bson:"response_time" json:"response_time"`
+	InterviewDifficulty int           `bson:"interview_difficulty" json:"interview_difficulty"`
+	Title               string        `bson:"title,omitempty" json:"title,omitempty"`
+	Comment             string        `bson:"comment" json:"comment"`
+	Status              string        `bson:"status" json:"status"`
+	ModerationReason    string        `bson:"moderation_reason,omitempty" json:"moderation_reason,omitempty"`
+	ModeratedBy         string        `bson:"moderated_by,omitempty" json:"moderated_by,omitempty"` // ID do admin
+	ModeratedAt         *time.Time    `bson:"moderated_at,omitempty" json:"moderated_at,omitempty"`
+	CreatedAt           time.Time     `bson:"created_at" json:"created_at"`
+}
+
+// PublicReview é a avaliação anonimizada: sem candidato, candidatura ou vaga e só com o mês
+type PublicReview struct {
+	ID                  bson.ObjectID `json:"id"`
+	Rating              int           `json:"rating"`
+	Communication       int           `json:"communication"`
+	ResponseTime        int           `json:"response_time"`
+	InterviewDifficulty int           `json:"interview_difficulty"`
+	Title               string        `json:"title,omitempty"`
+	Comment             string        `json:"comment"`
+	Month               string        `json:"month"` // "2026-10"
+}
+
+func (r *CompanyReview) Public() PublicReview {
+	return PublicReview{
+		ID:                  r.ID,
+		Rating:              r.Rating,
+		Communication:       r.Communication,
+		ResponseTime:        r.ResponseTime,
+		InterviewDifficulty: r.InterviewDifficulty,
+		Title:               r.Title,
+		Comment:             r.Comment,
+		Month:               r.CreatedAt.Format("2006-01"),
+	}
+}
+
+// CompanyReviewSummary são as médias das avaliações aprovadas de uma empresa
+type CompanyReviewSummary struct {
+	Total               int     `bson:"total" json:"total"`
+	Rating              float64 `bson:"rating" json:"rating"`
+	Communication       float64 `bson:"communication" json:"communication"`
+	ResponseTime        float64 `bson:"response_time" json:"response_time"`
+	InterviewDifficulty float64 `bson:"interview_difficulty" json:"interview_difficulty"`
+}
+
+// Round arredonda as médias para uma casa decimal (exibição "4.3")
+func (s *CompanyReviewSummary) Round() {
+	round := func(v float64) float64 { return math.Round(v*10) / 10 }
+	s.Rating = round(s.Rating)
+	s.Communication = round(s.Communication)
+	s.ResponseTime = round(s.ResponseTime)
+	s.InterviewDifficulty = round(s.InterviewDifficulty)
+}
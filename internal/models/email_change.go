@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// EmailChange é uma solicitação de troca de email aguardando confirmação do novo endereço
+type EmailChange struct {
+	ID        bson.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID    string        `bson:"user_id" json:"user_id"`
+	UserType  string        `bson:"user_type" json:"user_type"` // "company", "company_member" ou "candidate"
+	OldEmail  string        `bson:"old_email" json:"old_email"`
+	NewEmail  string        `bson:"new_email" json:"new_email"`
+	TokenHash string        `bson:"token_hash" json:"-"` // SHA-256 do token de confirmação
+	Used      bool          `bson:"used" json:"used"`
+	CreatedAt time.Time     `bson:"created_at" json:"created_at"`
+	ExpiresAt time.Time     `bson:"expires_at" json:"expires_at"`
+	UsedAt    *time.Time    `bson:"used_at,omitempty" json:"used_at,omitempty"`
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Status possíveis de um email no outbox
+const (
+	OutboxStatusPending = "pending"
+	OutboxStatusSending = "sending"
+	OutboxStatusSent    = "sent"
+	OutboxStatusFailed  = "failed"
+)
+
+// EmailAttachment representa um anexo de email (ex: convite .ics)
+type EmailAttachment struct {
+	Filename    string `bson:"filename" json:"filename"`
+	ContentType string `bson:"content_type" json:"content_type"`
+	Content     []byte `bson:"content" json:"-"`
+}
+
+// OutboxEmail é um email transacional aguardando envio pelo worker
+type OutboxEmail struct {
+	ID            bson.ObjectID     `bson:"_id,omitempty" json:"id"`
+	To            string            `bson:"to" json:"to"`
+	Subject       string            `bson:"subject" json:"subject"`
+	HTMLBody      string            `bson:"html_body" json:"-"`
+	TextBody      string            `bson:"text_body" json:"-"`
+	Attachments   []EmailAttachment `bson:"attachments,omitempty" json:"attachments,omitempty"`
+	Template      string            `bson:"template" json:"template"`
+	Status        string            `bson:"status" json:"status"` // "pending", "sending", "sent", "failed"
+	Attempts      int               `bson:"attempts" json:"attempts"`
+	LastError     string            `bson:"last_error,omitempty" json:"last_error,omitempty"`
+	NextAttemptAt time.Time         `bson:"next_attempt_at" json:"next_attempt_at"`
+	LockedUntil   *time.Time        `bson:"locked_until,omitempty" json:"-"`
+	CreatedAt     time.Time         `bson:"created_at" json:"created_at"`
+	SentAt        *time.Time        `bson:"sent_at,omitempty" json:"sent_at,omitempty"`
+}
@@ -0,0 +1,94 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Situação da entrevista
+const (
+	InterviewStatusProposed  = "proposed"  // horários propostos, aguardando a escolha do candidato
+	InterviewStatusScheduled = "scheduled" // horário definido
+	InterviewStatusCanceled  = "canceled"
+)
+
+// Formato da entrevista
+const (
+	InterviewModeVideo    = "video"     // video_url obrigatório
+	InterviewModeInPerson = "in_person" // location (endereço) obrigatório
+	InterviewModePhone    = "phone"
+)
+
+// InterviewSlot é um horário proposto pela empresa
+type InterviewSlot struct {
+	ID       bson.ObjectID `bson:"id" json:"id"`
+	StartsAt time.Time     `bson:"starts_at" json:"starts_at"`
+	EndsAt   time.Time     `bson:"ends_at" json:"ends_at"`
+}
+
+// Interview é uma entrevista marcada pela empresa para uma candidatura. Com um único horário
+// a entrevista já nasce agendada; com vários, o candidato escolhe um deles.
+type Interview struct {
+	ID            bson.ObjectID `bson:"_id,omitempty" json:"id"`
+	ApplicationID bson.ObjectID `bson:"application_id" json:"application_id"`
+	JobID         bson.ObjectID `bson:"job_id" json:"job_id"`
+	CompanyID     bson.ObjectID `bson:"company_id" json:"company_id"`
+	CandidateID   bson.ObjectID `bson:"candidate_id" json:"candidate_id"`
+	// Copiados na criação para listagens, emails e convites
+	JobTitle      string `bson:"job_title" json:"job_title"`
+	CompanyName   string `bson:"company_name" json:"company_name"`
+	CandidateName string `bson:"candidate_name" json:"candidate_name"`
+
+	Title    string          `bson:"title" json:"title"`
+	Mode     string          `bson:"mode" json:"mode"`
+	Location string          `bson:"location,omitempty" json:"location,omitempty"` // endereço ou instruções da ligação
+	VideoURL string          `bson:"video_url,omitempty" json:"video_url,omitempty"`
+	Notes    string          `bson:"notes,omitempty" json:"notes,omitempty"` // instruções para o candidato
+	Timezone string          `bson:"timezone" json:"timezone"`               // fuso (IANA) dos horários nos emails
+	Slots    []InterviewSlot `bson:"slots" json:"slots"`
+
+	// Horário definido (proposta única ou escolha do candidato)
+	SelectedSlotID *bson.ObjectID `bson:"selected_slot_id,omitempty" json:"selected_slot_id,omitempty"`
+	StartsAt       *time.Time     `bson:"starts_at,omitempty" json:"starts_at,omitempty"`
+	EndsAt         *time.Time     `bson:"ends_at,omitempty" json:"ends_at,omitempty"`
+
+	Status       string `bson:"status" json:"status"`
+	CancelReason string `bson:"cancel_reason,omitempty" json:"cancel_reason,omitempty"`
+	// SEQUENCE do convite iCalendar: aumenta a cada alteração (também serve de controle de concorrência)
+	Sequence int `bson:"sequence" json:"-"`
+	// Algum convite REQUEST já foi enviado (o cancelamento só manda CANCEL nesse caso)
+	InviteSent bool `bson:"invite_sent" json:"-"`
+	// Início do horário definido ou do primeiro proposto, para ordenar e filtrar por período
+	SortAt time.Time `bson:"sort_at" json:"-"`
+
+	CreatedBy  string     `bson:"created_by,omitempty" json:"-"` // ID do membro (vazio = dono)
+	CreatedAt  time.Time  `bson:"created_at" json:"created_at"`
+	UpdatedAt  time.Time  `bson:"updated_at" json:"updated_at"`
+	CanceledAt *time.Time `bson:"canceled_at,omitempty" json:"canceled_at,omitempty"`
+}
+
+// UID identifica a entrevista em todas as versões do convite
+func (i *Interview) UID() string {
+	return "interview-" + i.ID.Hex() + "@empregabem"
+}
+
+// Slot retorna o horário proposto com o ID informado (nil se não existir)
+func (i *Interview) Slot(id bson.ObjectID) *InterviewSlot {
+	for n := range i.Slots {
+		if i.Slots[n].ID == id {
+			return &i.Slots[n]
+		}
+	}
+	return nil
+}
+
+// Schedule define o horário da entrevista
+func (i *Interview) Schedule(slot *InterviewSlot) {
+	id, start, end := slot.ID, slot.StartsAt, slot.EndsAt
+	i.SelectedSlotID = &id
+	i.StartsAt = &start
+	i.EndsAt = &end
+	i.SortAt = start
+	i.Status = InterviewStatusScheduled
+}
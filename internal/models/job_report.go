@@ -0,0 +1,62 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Categorias de denúncia de vaga
+const (
+	ReportCategoryFraud          = "fraud"          // golpe, pirâmide, vaga inexistente
+	ReportCategoryFeeCharged     = "fee_charged"    // cobrança de taxa, kit ou curso do candidato
+	ReportCategoryDiscrimination = "discrimination" // exigências discriminatórias
+	ReportCategoryMisleading     = "misleading"     // salário ou informações falsas
+	ReportCategorySpam           = "spam"
+	ReportCategoryOffensive      = "offensive"
+	ReportCategoryOther          = "other"
+)
+
+// ValidReportCategories lista as categorias aceitas em POST /jobs/{id}/report
+var ValidReportCategories = map[string]bool{
+	ReportCategoryFraud:          true,
+	ReportCategoryFeeCharged:     true,
+	ReportCategoryDiscrimination: true,
+	ReportCategoryMisleading:     true,
+	ReportCategorySpam:           true,
+	ReportCategoryOffensive:      true,
+	ReportCategoryOther:          true,
+}
+
+// Status da denúncia na triagem dos admins
+const (
+	ReportStatusOpen      = "open"
+	ReportStatusDismissed = "dismissed" // denúncia improcedente
+	ReportStatusActioned  = "actioned"  // vaga removida
+)
+
+// JobReport é a denúncia de uma vaga feita por um candidato (uma por candidato e vaga)
+type JobReport struct {
+	ID             bson.ObjectID `bson:"_id,omitempty" json:"id"`
+	JobID          bson.ObjectID `bson:"job_id" json:"job_id"`
+	CompanyID      bson.ObjectID `bson:"company_id" json:"company_id"`
+	CandidateID    bson.ObjectID `bson:"candidate_id" json:"candidate_id"`
+	Category       string        `bson:"category" json:"category"`
+	Comment        string        `bson:"comment,omitempty" json:"comment,omitempty"`
+	Status         string        `bson:"status" json:"status"`
+	ResolvedBy     string        `bson:"resolved_by,omitempty" json:"resolved_by,omitempty"` // ID do admin
+	ResolutionNote string        `bson:"resolution_note,omitempty" json:"resolution_note,omitempty"`
+	ResolvedAt     *time.Time    `bson:"resolved_at,omitempty" json:"resolved_at,omitempty"`
+	CreatedAt      time.Time     `bson:"created_at" json:"created_at"`
+}
+
+// JobReportSummary agrupa as denúncias abertas de uma vaga para a triagem
+type JobReportSummary struct {
+	JobID         bson.ObjectID  `bson:"_id" json:"job_id"`
+	CompanyID     bson.ObjectID  `bson:"company_id" json:"company_id"`
+	Reports       int            `bson:"reports" json:"reports"`
+	Categories    map[string]int `bson:"-" json:"categories"`
+	CategoryList  []string       `bson:"categories" json:"-"`
+	FirstReportAt time.Time      `bson:"first_report_at" json:"first_report_at"`
+	LastReportAt  time.Time      `bson:"last_report_at" json:"last_report_at"`
+}
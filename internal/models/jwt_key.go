@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// JWTKey é uma chave assimétrica de assinatura de tokens (rotacionada periodicamente)
+type JWTKey struct {
+	KID         string    `bson:"_id" json:"kid"`
+	Algorithm   string    `bson:"algorithm" json:"algorithm"`       // "EdDSA" ou "RS256"
+	PrivateKey  string    `bson:"private_key" json:"-"`             // PKCS#8 cifrado com AES-GCM (base64)
+	CreatedAt   time.Time `bson:"created_at" json:"created_at"`     // publicada no JWKS a partir daqui
+	ActivatesAt time.Time `bson:"activates_at" json:"activates_at"` // passa a assinar tokens
+	RetiresAt   time.Time `bson:"retires_at" json:"retires_at"`     // deixa de validar tokens (índice TTL)
+}
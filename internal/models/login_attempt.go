@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// LoginAttempt acumula falhas de login de uma conta ou de um IP
+type LoginAttempt struct {
+	ID            bson.ObjectID `bson:"_id,omitempty" json:"id"`
+	Key           string        `bson:"key" json:"key"`   // "account:<tipo>:<email>" ou "ip:<ip>"
+	Kind          string        `bson:"kind" json:"kind"` // "account" ou "ip"
+	Failures      int           `bson:"failures" json:"failures"`
+	LockCount     int           `bson:"lock_count" json:"lock_count"` // quantas vezes foi bloqueado (aumenta o backoff)
+	LockedUntil   *time.Time    `bson:"locked_until,omitempty" json:"locked_until,omitempty"`
+	LastFailureAt time.Time     `bson:"last_failure_at" json:"last_failure_at"`
+	ExpiresAt     time.Time     `bson:"expires_at" json:"expires_at"` // índice TTL remove registros inativos
+}
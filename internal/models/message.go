@@ -0,0 +1,78 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Lados de uma conversa
+const (
+	MessageSideCandidate = "candidate"
+	MessageSideCompany   = "company"
+)
+
+// MessageThread é a conversa entre a empresa e o candidato sobre uma candidatura.
+// Só a empresa abre a conversa; o candidato responde depois disso.
+type MessageThread struct {
+	ID            bson.ObjectID `bson:"_id,omitempty" json:"id"`
+	ApplicationID bson.ObjectID `bson:"application_id" json:"application_id"`
+	JobID         bson.ObjectID `bson:"job_id" json:"job_id"`
+	CompanyID     bson.ObjectID `bson:"company_id" json:"company_id"`
+	CandidateID   bson.ObjectID `bson:"candidate_id" json:"candidate_id"`
+	// Copiados na abertura para a listagem não depender de outras collections
+	JobTitle      string `bson:"job_title" json:"job_title"`
+	CompanyName   string `bson:"company_name" json:"company_name"`
+	CandidateName string `bson:"candidate_name" json:"candidate_name"`
+	StartedBy     string `bson:"started_by,omitempty" json:"-"` // ID do membro que abriu (vazio = dono)
+
+	MessageCount       int       `bson:"message_count" json:"message_count"`
+	LastMessageAt      time.Time `bson:"last_message_at" json:"last_message_at"`
+	LastMessagePreview string    `bson:"last_message_preview" json:"last_message_preview"`
+	LastSenderSide     string    `bson:"last_sender_side" json:"last_sender_side"`
+
+	// Não lidas e confirmação de leitura de cada lado (na empresa, a equipe compartilha o estado)
+	UnreadCandidate int        `bson:"unread_candidate" json:"-"`
+	UnreadCompany   int        `bson:"unread_company" json:"-"`
+	CandidateReadAt *time.Time `bson:"candidate_read_at,omitempty" json:"candidate_read_at,omitempty"`
+	CompanyReadAt   *time.Time `bson:"company_read_at,omitempty" json:"company_read_at,omitempty"`
+
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+}
+
+// Unread retorna as mensagens não lidas pelo lado informado
+func (t *MessageThread) Unread(side string) int {
+	if side == MessageSideCandidate {
+		return t.UnreadCandidate
+	}
+	return t.UnreadCompany
+}
+
+// ReadAt retorna até quando o lado informado leu a conversa
+func (t *MessageThread) ReadAt(side string) *time.Time {
+	if side == MessageSideCandidate {
+		return t.CandidateReadAt
+	}
+	return t.CompanyReadAt
+}
+
+// MessageAttachment é um arquivo enviado na mensagem, guardado no storage privado de anexos
+type MessageAttachment struct {
+	Key         string `bson:"key" json:"-"`
+	Filename    string `bson:"filename" json:"filename"`
+	ContentType string `bson:"content_type" json:"content_type"`
+	Size        int64  `bson:"size" json:"size"`
+}
+
+// Message é uma mensagem de uma conversa
+type Message struct {
+	ID             bson.ObjectID       `bson:"_id,omitempty" json:"id"`
+	ThreadID       bson.ObjectID       `bson:"thread_id" json:"thread_id"`
+	SenderSide     string              `bson:"sender_side" json:"sender_side"` // "candidate" ou "company"
+	SenderID       bson.ObjectID       `bson:"sender_id" json:"-"`             // candidato ou empresa
+	SenderMemberID string              `bson:"sender_member_id,omitempty" json:"-"`
+	SenderName     string              `bson:"sender_name" json:"sender_name"`
+	Body           string              `bson:"body" json:"body"`
+	Attachments    []MessageAttachment `bson:"attachments,omitempty" json:"attachments,omitempty"`
+	CreatedAt      time.Time           `bson:"created_at" json:"created_at"`
+}
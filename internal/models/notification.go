@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Tipos de notificação
+const (
+	NotificationApplicationCreated       = "application_created"        // empresa: novo candidato em uma vaga
+	NotificationApplicationStatusChanged = "application_status_changed" // candidato: empresa movimentou a candidatura
+	NotificationJobExpiring              = "job_expiring"               // empresa: vaga expira em breve
+	NotificationJobExpired               = "job_expired"                // empresa: vaga foi desativada por expirar
+	NotificationNewMessage               = "new_message"                // candidato ou empresa: nova mensagem recebida
+	NotificationInterviewProposed        = "interview_proposed"         // candidato: empresa propôs horários de entrevista
+	NotificationInterviewScheduled       = "interview_scheduled"        // candidato ou empresa: entrevista com horário definido
+	NotificationInterviewCanceled        = "interview_canceled"         // candidato: empresa cancelou a entrevista
+	NotificationJobAlert                 = "job_alert"                  // candidato: novas vagas em uma busca salva
+	NotificationContactRequest           = "contact_request"            // candidato: empresa pediu contato pelo banco de talentos
+	NotificationContactAccepted          = "contact_accepted"           // empresa: candidato liberou os contatos
+)
+
+// Destinatários de notificação. O dono da empresa é o próprio documento Company;
+// membros da equipe recebem notificações individuais.
+const (
+	RecipientCandidate = "candidate"
+	RecipientCompany   = "company"
+	RecipientMember    = "member"
+)
+
+// Notification é um aviso exibido na central de notificações de um usuário
+type Notification struct {
+	ID            bson.ObjectID     `bson:"_id,omitempty" json:"id"`
+	RecipientType string            `bson:"recipient_type" json:"-"`
+	RecipientID   bson.ObjectID     `bson:"recipient_id" json:"-"`
+	Type          string            `bson:"type" json:"type"`
+	Title         string            `bson:"title" json:"title"`
+	Body          string            `bson:"body" json:"body"`
+	Link          string            `bson:"link,omitempty" json:"link,omitempty"` // rota da API relacionada ao evento
+	Data          map[string]string `bson:"data,omitempty" json:"data,omitempty"` // IDs do evento (job_id, application_id...)
+	ReadAt        *time.Time        `bson:"read_at" json:"read_at"`
+	CreatedAt     time.Time         `bson:"created_at" json:"created_at"`
+}
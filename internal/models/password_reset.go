@@ -6,15 +6,16 @@ import (
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
-// PasswordReset armazena tokens de reset de senha
+// PasswordReset armazena tokens de reset de senha.
+// Apenas o hash SHA-256 do token é persistido. O link com o token fica no outbox só até o email
+// ser enviado (ou falhar de vez): o worker apaga o corpo do email nesse momento.
 type PasswordReset struct {
 	ID        bson.ObjectID `bson:"_id,omitempty" json:"id"`
 	Email     string        `bson:"email" json:"email"`
 	UserType  string        `bson:"user_type" json:"user_type"` // "company" ou "candidate"
-	Token     string        `bson:"token" json:"token"`         // JWT token
-	TokenHash string        `bson:"token_hash" json:"-"`        // Hash do token único
+	TokenHash string        `bson:"token_hash" json:"-"`        // SHA-256 do token opaco
 	Used      bool          `bson:"used" json:"used"`
 	CreatedAt time.Time     `bson:"created_at" json:"created_at"`
-	ExpiresAt time.Time     `bson:"expires_at" json:"expires_at"`
+	ExpiresAt time.Time     `bson:"expires_at" json:"expires_at"` // índice TTL remove o documento após expirar
 	UsedAt    *time.Time    `bson:"used_at,omitempty" json:"used_at,omitempty"`
 }
@@ -0,0 +1,112 @@
+package models
+
+import (
+	"empregabemapi/jobs"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Frequência dos alertas de uma busca salva
+const (
+	AlertFrequencyInstant = "instant" // a cada verificação do agendador (poucos minutos)
+	AlertFrequencyDaily   = "daily"   // resumo diário às 8h (horário de Brasília)
+	AlertFrequencyWeekly  = "weekly"  // resumo semanal na segunda-feira às 8h
+)
+
+// Canais de entrega dos alertas
+const (
+	AlertChannelEmail = "email"
+	AlertChannelInApp = "in_app" // central de notificações
+)
+
+// Limites das buscas salvas
+const (
+	MaxSavedSearches   = 20  // por candidato
+	MaxAlertDigestJobs = 20  // vagas listadas em um alerta (as demais aparecem só na contagem)
+	MaxPendingAlerts   = 200 // vagas guardadas para o próximo resumo
+)
+
+// Horário dos resumos diários e semanais
+const alertDigestHour = 8
+
+var alertLocation = loadAlertLocation("America/Sao_Paulo")
+
+// SavedSearch é uma busca de vagas salva pelo candidato. Vagas publicadas depois que casam com
+// os filtros geram alertas na frequência e nos canais escolhidos.
+type SavedSearch struct {
+	ID          bson.ObjectID      `bson:"_id,omitempty" json:"id"`
+	CandidateID bson.ObjectID      `bson:"candidate_id" json:"-"`
+	Name        string             `bson:"name" json:"name"`
+	Filters     jobs.SearchFilters `bson:"filters" json:"filters"`
+	Frequency   string             `bson:"frequency" json:"frequency"`
+	Channels    []string           `bson:"channels" json:"channels"`
+	// Alertas ligados; o link de descadastro do email desliga sem apagar a busca
+	AlertsEnabled bool `bson:"alerts_enabled" json:"alerts_enabled"`
+
+	// Vagas encontradas aguardando o próximo resumo (diário/semanal)
+	PendingJobIDs []bson.ObjectID `bson:"pending_job_ids,omitempty" json:"-"`
+	NextDigestAt  *time.Time      `bson:"next_digest_at,omitempty" json:"next_digest_at,omitempty"`
+	LastAlertAt   *time.Time      `bson:"last_alert_at,omitempty" json:"last_alert_at,omitempty"`
+
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+// ValidAlertFrequency indica se a frequência é uma das aceitas
+func ValidAlertFrequency(frequency string) bool {
+	return frequency == AlertFrequencyInstant || frequency == AlertFrequencyDaily || frequency == AlertFrequencyWeekly
+}
+
+// ValidAlertChannel indica se o canal é um dos aceitos
+func ValidAlertChannel(channel string) bool {
+	return channel == AlertChannelEmail || channel == AlertChannelInApp
+}
+
+// HasChannel indica se os alertas da busca são entregues pelo canal informado
+func (s *SavedSearch) HasChannel(channel string) bool {
+	for _, c := range s.Channels {
+		if c == channel {
+			return true
+		}
+	}
+	return false
+}
+
+// ScheduleDigest define o próximo resumo conforme a frequência (nil para alertas instantâneos)
+func (s *SavedSearch) ScheduleDigest(after time.Time) {
+	s.NextDigestAt = NextAlertDigest(s.Frequency, after)
+}
+
+// NextAlertDigest calcula o horário do próximo resumo depois de after: todo dia às 8h ou
+// toda segunda-feira às 8h (horário de Brasília). Retorna nil para alertas instantâneos.
+func NextAlertDigest(frequency string, after time.Time) *time.Time {
+	local := after.In(alertLocation)
+	next := time.Date(local.Year(), local.Month(), local.Day(), alertDigestHour, 0, 0, 0, alertLocation)
+
+	switch frequency {
+	case AlertFrequencyDaily:
+		if !next.After(after) {
+			next = next.AddDate(0, 0, 1)
+		}
+	case AlertFrequencyWeekly:
+		next = next.AddDate(0, 0, (int(time.Monday)-int(next.Weekday())+7)%7)
+		if !next.After(after) {
+			next = next.AddDate(0, 0, 7)
+		}
+	default:
+		return nil
+	}
+
+	next = next.UTC()
+	return &next
+}
+
+// loadAlertLocation carrega o fuso dos resumos (sem tzdata, usa UTC-3 fixo)
+func loadAlertLocation(name string) *time.Location {
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return time.FixedZone("BRT", -3*60*60)
+	}
+	return location
+}
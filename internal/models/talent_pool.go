@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Situação do pedido de contato de uma empresa a um candidato do banco de talentos
+const (
+	ContactRequestPending  = "pending"
+	ContactRequestAccepted = "accepted" // contatos do candidato liberados para a empresa
+	ContactRequestDeclined = "declined"
+)
+
+// Registros do histórico do candidato no banco de talentos
+const (
+	TalentEventSearch = "search" // o perfil apareceu na busca de uma empresa
+	TalentEventView   = "view"   // uma empresa abriu o perfil
+)
+
+const (
+	MaxContactMessageLength = 1000
+	// Depois de uma recusa, a mesma empresa só pode pedir contato de novo após esse prazo
+	ContactRequestCooldown = 30 * 24 * time.Hour
+)
+
+// ContactRequest é o pedido de uma empresa para falar com um candidato encontrado no banco de
+// talentos. Os contatos só são liberados quando o candidato aceita.
+type ContactRequest struct {
+	ID          bson.ObjectID  `bson:"_id,omitempty" json:"id"`
+	CompanyID   bson.ObjectID  `bson:"company_id" json:"company_id"`
+	MemberID    *bson.ObjectID `bson:"member_id,omitempty" json:"-"` // membro que pediu (vazio = dono)
+	CandidateID bson.ObjectID  `bson:"candidate_id" json:"candidate_id"`
+	// Copiados na criação para as listagens
+	CompanyName   string `bson:"company_name" json:"company_name"`
+	CandidateName string `bson:"candidate_name" json:"candidate_name"`
+
+	Message     string     `bson:"message" json:"message"`
+	Status      string     `bson:"status" json:"status"`
+	RespondedAt *time.Time `bson:"responded_at,omitempty" json:"responded_at,omitempty"`
+	CreatedAt   time.Time  `bson:"created_at" json:"created_at"`
+}
+
+// TalentEvent registra que uma empresa encontrou ou abriu o perfil do candidato
+type TalentEvent struct {
+	ID          bson.ObjectID `bson:"_id,omitempty" json:"id"`
+	CandidateID bson.ObjectID `bson:"candidate_id" json:"-"`
+	CompanyID   bson.ObjectID `bson:"company_id" json:"company_id"`
+	CompanyName string        `bson:"company_name" json:"company_name"`
+	Type        string        `bson:"type" json:"type"`
+	Query       string        `bson:"query,omitempty" json:"query,omitempty"` // filtros da busca
+	CreatedAt   time.Time     `bson:"created_at" json:"created_at"`
+}
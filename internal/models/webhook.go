@@ -0,0 +1,83 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Eventos que uma empresa pode assinar nos webhooks
+const (
+	WebhookEventApplicationCreated       = "application.created"
+	WebhookEventApplicationStatusChanged = "application.status_changed"
+	WebhookEventJobPublished             = "job.published"
+	WebhookEventJobClosed                = "job.closed"
+	// Enviado apenas por POST /company/webhooks/{id}/test
+	WebhookEventPing = "ping"
+)
+
+// ValidWebhookEvents lista os eventos aceitos no cadastro de endpoints
+var ValidWebhookEvents = map[string]bool{
+	WebhookEventApplicationCreated:       true,
+	WebhookEventApplicationStatusChanged: true,
+	WebhookEventJobPublished:             true,
+	WebhookEventJobClosed:                true,
+}
+
+// WebhookEndpoint é uma URL da empresa que recebe os eventos assinados (ex: integração com ATS)
+type WebhookEndpoint struct {
+	ID          bson.ObjectID `bson:"_id,omitempty" json:"id"`
+	CompanyID   bson.ObjectID `bson:"company_id" json:"company_id"`
+	URL         string        `bson:"url" json:"url"`
+	Description string        `bson:"description,omitempty" json:"description,omitempty"`
+	Events      []string      `bson:"events" json:"events"`
+	Active      bool          `bson:"active" json:"active"`
+	Secret      string        `bson:"secret" json:"-"`
+	// Após a rotação, o segredo anterior continua assinando as entregas até PreviousSecretExpiresAt
+	PreviousSecret          string     `bson:"previous_secret,omitempty" json:"-"`
+	PreviousSecretExpiresAt *time.Time `bson:"previous_secret_expires_at,omitempty" json:"previous_secret_expires_at,omitempty"`
+	CreatedBy               string     `bson:"created_by,omitempty" json:"created_by,omitempty"` // ID do membro (vazio = dono)
+	CreatedAt               time.Time  `bson:"created_at" json:"created_at"`
+	UpdatedAt               time.Time  `bson:"updated_at" json:"updated_at"`
+}
+
+// Subscribed indica se o endpoint assina o evento
+func (e *WebhookEndpoint) Subscribed(eventType string) bool {
+	for _, event := range e.Events {
+		if event == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Status de uma entrega de webhook
+const (
+	WebhookDeliveryPending    = "pending"
+	WebhookDeliveryDelivering = "delivering"
+	WebhookDeliveryDelivered  = "delivered"
+	WebhookDeliveryFailed     = "failed"
+)
+
+// WebhookDelivery é o envio de um evento para um endpoint, com o histórico da última tentativa.
+// Reenvios criam uma entrega nova com o mesmo EventID (o receptor pode usá-lo para deduplicar).
+type WebhookDelivery struct {
+	ID             bson.ObjectID  `bson:"_id,omitempty" json:"id"`
+	EndpointID     bson.ObjectID  `bson:"endpoint_id" json:"endpoint_id"`
+	CompanyID      bson.ObjectID  `bson:"company_id" json:"-"`
+	EventID        string         `bson:"event_id" json:"event_id"`
+	EventType      string         `bson:"event_type" json:"event_type"`
+	Payload        string         `bson:"payload" json:"payload"` // corpo JSON enviado
+	Status         string         `bson:"status" json:"status"`
+	Attempts       int            `bson:"attempts" json:"attempts"`
+	NextAttemptAt  time.Time      `bson:"next_attempt_at" json:"next_attempt_at"`
+	LockedUntil    *time.Time     `bson:"locked_until,omitempty" json:"-"`
+	LastStatusCode int            `bson:"last_status_code,omitempty" json:"last_status_code,omitempty"`
+	LastError      string         `bson:"last_error,omitempty" json:"last_error,omitempty"`
+	LastResponse   string         `bson:"last_response,omitempty" json:"last_response,omitempty"` // início do corpo da resposta
+	LastDurationMs int64          `bson:"last_duration_ms,omitempty" json:"last_duration_ms,omitempty"`
+	LastAttemptAt  *time.Time     `bson:"last_attempt_at,omitempty" json:"last_attempt_at,omitempty"`
+	RedeliveryOf   *bson.ObjectID `bson:"redelivery_of,omitempty" json:"redelivery_of,omitempty"`
+	CreatedAt      time.Time      `bson:"created_at" json:"created_at"`
+	DeliveredAt    *time.Time     `bson:"delivered_at,omitempty" json:"delivered_at,omitempty"`
+}
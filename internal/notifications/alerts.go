@@ -0,0 +1,223 @@
+package notifications
+
+import (
+	"context"
+	"empregabemapi/candidates"
+	"empregabemapi/internal/auth"
+	"empregabemapi/internal/mail"
+	"empregabemapi/internal/models"
+	"empregabemapi/internal/repository"
+	"empregabemapi/jobs"
+	"log"
+	"net/url"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Só vagas criadas nesse período geram alertas: ao ativar o recurso, vagas antigas não são
+// enviadas, e vagas retidas por muito tempo na moderação não chegam como "novas"
+const alertLookback = 7 * 24 * time.Hour
+
+var alertFrequencyLabels = map[string]string{
+	models.AlertFrequencyInstant: "assim que novas vagas são publicadas",
+	models.AlertFrequencyDaily:   "uma vez por dia",
+	models.AlertFrequencyWeekly:  "uma vez por semana",
+}
+
+// JobAlertWorker compara as vagas recém-publicadas com as buscas salvas dos candidatos e envia
+// os alertas: na hora (instant) ou acumulados nos resumos diários e semanais
+type JobAlertWorker struct {
+	jobRepo       *jobs.MongoRepository
+	searchRepo    *repository.SavedSearchesRepository
+	candidateRepo *candidates.MongoRepository
+	service       *Service
+	mailer        *mail.Mailer
+	interval      time.Duration
+}
+
+func NewJobAlertWorker(jobRepo *jobs.MongoRepository, searchRepo *repository.SavedSearchesRepository, candidateRepo *candidates.MongoRepository, service *Service, mailer *mail.Mailer, interval time.Duration) *JobAlertWorker {
+	return &JobAlertWorker{
+		jobRepo:       jobRepo,
+		searchRepo:    searchRepo,
+		candidateRepo: candidateRepo,
+		service:       service,
+		mailer:        mailer,
+		interval:      interval,
+	}
+}
+
+// Run verifica vagas novas e resumos vencidos até o contexto ser cancelado
+func (w *JobAlertWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.matchNewJobs(ctx)
+		w.sendDigests(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// matchNewJobs processa uma única vez cada vaga que entrou no ar e a distribui entre as buscas salvas
+func (w *JobAlertWorker) matchNewJobs(ctx context.Context) {
+	pending, err := w.jobRepo.ListPendingAlerts(ctx, time.Now().Add(-alertLookback))
+	if err != nil {
+		log.Printf("[alerts] erro ao buscar vagas novas: %v", err)
+		return
+	}
+	if len(pending) == 0 {
+		return
+	}
+
+	// As buscas são carregadas antes de marcar as vagas: se a consulta falhar, as vagas continuam
+	// pendentes e são processadas na próxima rodada
+	searches, err := w.searchRepo.ListWithAlerts(ctx)
+	if err != nil {
+		log.Printf("[alerts] erro ao buscar buscas salvas: %v", err)
+		return
+	}
+
+	// A marcação condicional garante que a vaga gere alertas uma única vez, mesmo com várias instâncias
+	var published []*jobs.Job
+	for _, job := range pending {
+		processed, err := w.jobRepo.MarkAlertsProcessed(ctx, job.ID)
+		if err != nil {
+			log.Printf("[alerts] erro ao marcar vaga %s: %v", job.ID.Hex(), err)
+			continue
+		}
+		if processed {
+			published = append(published, job)
+		}
+	}
+	if len(published) == 0 {
+		return
+	}
+
+	for _, search := range searches {
+		var matched []*jobs.Job
+		for _, job := range published {
+			if search.Filters.Matches(job) {
+				matched = append(matched, job)
+			}
+		}
+		if len(matched) == 0 {
+			continue
+		}
+
+		if search.Frequency == models.AlertFrequencyInstant {
+			w.deliver(ctx, search, matched)
+			continue
+		}
+
+		jobIDs := make([]bson.ObjectID, 0, len(matched))
+		for _, job := range matched {
+			jobIDs = append(jobIDs, job.ID)
+		}
+		if err := w.searchRepo.AddPending(ctx, search.ID, jobIDs); err != nil {
+			log.Printf("[alerts] erro ao guardar vagas da busca %s: %v", search.ID.Hex(), err)
+		}
+	}
+}
+
+// sendDigests envia os resumos diários e semanais vencidos com as vagas que continuam no ar
+func (w *JobAlertWorker) sendDigests(ctx context.Context) {
+	now := time.Now()
+	due, err := w.searchRepo.ListDueDigests(ctx, now)
+	if err != nil {
+		log.Printf("[alerts] erro ao buscar resumos vencidos: %v", err)
+		return
+	}
+
+	for _, search := range due {
+		jobIDs, err := w.searchRepo.ClaimDigest(ctx, search, models.NextAlertDigest(search.Frequency, now))
+		if err != nil {
+			log.Printf("[alerts] erro ao agendar próximo resumo da busca %s: %v", search.ID.Hex(), err)
+			continue
+		}
+		if len(jobIDs) == 0 {
+			continue
+		}
+
+		open, err := w.jobRepo.ListOpenByIDs(ctx, jobIDs)
+		if err != nil {
+			log.Printf("[alerts] erro ao buscar vagas do resumo da busca %s: %v", search.ID.Hex(), err)
+			continue
+		}
+		if len(open) > 0 {
+			w.deliver(ctx, search, open)
+		}
+	}
+}
+
+// deliver envia o alerta pelos canais escolhidos na busca
+func (w *JobAlertWorker) deliver(ctx context.Context, search *models.SavedSearch, found []*jobs.Job) {
+	candidate, err := w.candidateRepo.GetByID(ctx, search.CandidateID.Hex())
+	if err != nil {
+		log.Printf("[alerts] erro ao buscar candidato da busca %s: %v", search.ID.Hex(), err)
+		return
+	}
+	if candidate.SuspendedAt != nil {
+		return
+	}
+
+	total := len(found)
+	if len(found) > models.MaxAlertDigestJobs {
+		found = found[:models.MaxAlertDigestJobs]
+	}
+
+	if search.HasChannel(models.AlertChannelInApp) {
+		w.service.JobAlert(ctx, search, found, total)
+	}
+	if search.HasChannel(models.AlertChannelEmail) {
+		w.sendEmail(ctx, candidate, search, found, total)
+	}
+
+	if err := w.searchRepo.MarkAlerted(ctx, search.ID); err != nil {
+		log.Printf("[alerts] erro ao registrar alerta da busca %s: %v", search.ID.Hex(), err)
+	}
+}
+
+func (w *JobAlertWorker) sendEmail(ctx context.Context, candidate *candidates.Candidate, search *models.SavedSearch, found []*jobs.Job, total int) {
+	token, err := auth.SignLink(auth.LinkPurposeAlertUnsubscribe, search.ID.Hex())
+	if err != nil {
+		log.Printf("[alerts] erro ao assinar link de descadastro da busca %s: %v", search.ID.Hex(), err)
+		return
+	}
+
+	type alertJob struct {
+		Title    string
+		Company  string
+		Location string
+		URL      string
+	}
+	list := make([]alertJob, 0, len(found))
+	for _, job := range found {
+		list = append(list, alertJob{
+			Title:    job.Title,
+			Company:  job.Company,
+			Location: job.Location,
+			URL:      w.mailer.URL("/jobs/" + job.ID.Hex()),
+		})
+	}
+
+	err = w.mailer.Enqueue(ctx, candidate.Email, mail.TemplateJobAlert, map[string]interface{}{
+		"Name":           candidate.Name,
+		"SearchName":     search.Name,
+		"Jobs":           list,
+		"Total":          total,
+		"More":           total - len(found),
+		"FrequencyLabel": alertFrequencyLabels[search.Frequency],
+		"SearchURL":      w.mailer.URL("/candidate/saved-searches/" + search.ID.Hex()),
+		"ManageURL":      w.mailer.URL("/candidate/saved-searches"),
+		"UnsubscribeURL": w.mailer.URL("/alerts/unsubscribe?token=" + url.QueryEscape(token)),
+	})
+	if err != nil {
+		log.Printf("[alerts] erro ao enfileirar alerta da busca %s: %v", search.ID.Hex(), err)
+	}
+}
@@ -0,0 +1,88 @@
+package notifications
+
+import (
+	"context"
+	"empregabemapi/internal/webhooks"
+	"empregabemapi/jobs"
+	"log"
+	"time"
+)
+
+// ExpiryWorker avisa as empresas sobre vagas perto de expirar e desativa as vencidas
+type ExpiryWorker struct {
+	jobRepo  *jobs.MongoRepository
+	service  *Service
+	webhooks *webhooks.Dispatcher
+	interval time.Duration
+}
+
+func NewExpiryWorker(jobRepo *jobs.MongoRepository, service *Service, webhookDispatcher *webhooks.Dispatcher, interval time.Duration) *ExpiryWorker {
+	return &ExpiryWorker{
+		jobRepo:  jobRepo,
+		service:  service,
+		webhooks: webhookDispatcher,
+		interval: interval,
+	}
+}
+
+// Run verifica as validades até o contexto ser cancelado
+func (w *ExpiryWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.warnExpiring(ctx)
+		w.expireDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// warnExpiring avisa uma única vez cada vaga que expira dentro de jobs.ExpiryWarning
+func (w *ExpiryWorker) warnExpiring(ctx context.Context) {
+	expiring, err := w.jobRepo.ListExpiringSoon(ctx, time.Now().Add(jobs.ExpiryWarning))
+	if err != nil {
+		log.Printf("[notifications] erro ao buscar vagas perto de expirar: %v", err)
+		return
+	}
+
+	for _, job := range expiring {
+		// A marcação condicional garante um único aviso mesmo com várias instâncias da API
+		warned, err := w.jobRepo.MarkExpiryWarned(ctx, job.ID)
+		if err != nil {
+			log.Printf("[notifications] erro ao marcar aviso de expiração da vaga %s: %v", job.ID.Hex(), err)
+			continue
+		}
+		if warned {
+			w.service.JobExpiring(ctx, job)
+		}
+	}
+}
+
+// expireDue desativa as vagas vencidas e avisa a empresa
+func (w *ExpiryWorker) expireDue(ctx context.Context) {
+	due, err := w.jobRepo.ListDueForExpiry(ctx)
+	if err != nil {
+		log.Printf("[notifications] erro ao buscar vagas expiradas: %v", err)
+		return
+	}
+
+	for _, job := range due {
+		expired, err := w.jobRepo.Expire(ctx, job.ID)
+		if err != nil {
+			log.Printf("[notifications] erro ao desativar vaga expirada %s: %v", job.ID.Hex(), err)
+			continue
+		}
+		if expired {
+			w.service.JobExpired(ctx, job)
+			if job.IsPublic() {
+				job.IsActive = false
+				w.webhooks.JobClosed(ctx, job, webhooks.CloseReasonExpired)
+			}
+		}
+	}
+}
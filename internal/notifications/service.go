@@ -0,0 +1,316 @@
+package notifications
+
+import (
+	"context"
+	"empregabemapi/applications"
+	"empregabemapi/companies"
+	"empregabemapi/internal/events"
+	"empregabemapi/internal/models"
+	"empregabemapi/internal/repository"
+	"empregabemapi/jobs"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Eventos em tempo real que não geram notificação
+const (
+	// Total de notificações não lidas do usuário
+	EventNotificationCount = "notification_count"
+	// O outro lado leu a conversa (confirmação de leitura)
+	EventMessagesRead = "messages_read"
+)
+
+// Service transforma eventos da plataforma em notificações para cada destinatário
+// e as envia em tempo real para as conexões abertas (GET /events).
+// Notificações são best-effort: falhas são registradas em log e nunca interrompem a ação
+// que gerou o evento.
+type Service struct {
+	repo       *repository.NotificationsRepository
+	memberRepo *companies.MemberRepository
+	hub        *events.Hub
+}
+
+func NewService(repo *repository.NotificationsRepository, memberRepo *companies.MemberRepository, hub *events.Hub) *Service {
+	return &Service{
+		repo:       repo,
+		memberRepo: memberRepo,
+		hub:        hub,
+	}
+}
+
+// Recipient identifica quem recebe uma notificação
+type Recipient struct {
+	Type string
+	ID   bson.ObjectID
+}
+
+// notify grava a mesma notificação para todos os destinatários
+func (s *Service) notify(ctx context.Context, recipients []Recipient, n models.Notification) {
+	if len(recipients) == 0 {
+		return
+	}
+
+	batch := make([]*models.Notification, 0, len(recipients))
+	for _, recipient := range recipients {
+		notification := n
+		notification.RecipientType = recipient.Type
+		notification.RecipientID = recipient.ID
+		batch = append(batch, &notification)
+	}
+
+	if err := s.repo.CreateMany(ctx, batch); err != nil {
+		log.Printf("[notifications] erro ao gravar notificações %s: %v", n.Type, err)
+		return
+	}
+
+	// O evento leva o nome do tipo da notificação (application_created, application_status_changed...)
+	for _, notification := range batch {
+		s.hub.Publish(ctx, events.Key(notification.RecipientType, notification.RecipientID.Hex()), notification.Type, notification)
+		s.UnreadCountChanged(ctx, notification.RecipientType, notification.RecipientID)
+	}
+}
+
+// UnreadCountChanged envia o total atualizado de não lidas para as conexões abertas do usuário
+func (s *Service) UnreadCountChanged(ctx context.Context, recipientType string, recipientID bson.ObjectID) {
+	unread, err := s.repo.CountUnread(ctx, recipientType, recipientID)
+	if err != nil {
+		log.Printf("[notifications] erro ao contar não lidas de %s %s: %v", recipientType, recipientID.Hex(), err)
+		return
+	}
+	s.hub.Publish(ctx, events.Key(recipientType, recipientID.Hex()), EventNotificationCount, map[string]int64{
+		"nao_lidas": unread,
+	})
+}
+
+// jobRecipients retorna quem acompanha a vaga do lado da empresa: o dono, os membros que
+// acessam todas as vagas (owner/admin) e os membros da equipe de contratação
+func (s *Service) jobRecipients(ctx context.Context, job *jobs.Job) []Recipient {
+	recipients := []Recipient{{Type: models.RecipientCompany, ID: job.CompanyID}}
+
+	members, err := s.memberRepo.ListByCompany(ctx, job.CompanyID.Hex())
+	if err != nil {
+		log.Printf("[notifications] erro ao buscar equipe da empresa %s: %v", job.CompanyID.Hex(), err)
+		return recipients
+	}
+	for _, member := range members {
+		if member.Role.Can(companies.PermAccessAllJobs) || job.HasTeamMember(member.ID.Hex()) {
+			recipients = append(recipients, Recipient{Type: models.RecipientMember, ID: member.ID})
+		}
+	}
+	return recipients
+}
+
+// ApplicationCreated avisa a empresa sobre um novo candidato na vaga
+func (s *Service) ApplicationCreated(ctx context.Context, job *jobs.Job, app *applications.Application) {
+	s.notify(ctx, s.jobRecipients(ctx, job), models.Notification{
+		Type:  models.NotificationApplicationCreated,
+		Title: "Nova candidatura",
+		Body:  fmt.Sprintf("A vaga %q recebeu uma nova candidatura.", job.Title),
+		Link:  "/company/jobs/" + job.ID.Hex() + "/applicants",
+		Data: map[string]string{
+			"job_id":         job.ID.Hex(),
+			"application_id": app.ID.Hex(),
+		},
+	})
+}
+
+// ApplicationStatusChanged avisa o candidato que a empresa movimentou a candidatura
+func (s *Service) ApplicationStatusChanged(ctx context.Context, job *jobs.Job, app *applications.Application, statusLabel string) {
+	s.notify(ctx, []Recipient{{Type: models.RecipientCandidate, ID: app.CandidateID}}, models.Notification{
+		Type:  models.NotificationApplicationStatusChanged,
+		Title: "Candidatura atualizada",
+		Body:  fmt.Sprintf("Sua candidatura para a vaga %q (%s) agora está: %s.", job.Title, job.Company, statusLabel),
+		Link:  "/candidate/applications",
+		Data: map[string]string{
+			"job_id":         job.ID.Hex(),
+			"application_id": app.ID.Hex(),
+			"status":         app.Status,
+		},
+	})
+}
+
+// JobExpiring avisa a empresa que a vaga será desativada em breve
+func (s *Service) JobExpiring(ctx context.Context, job *jobs.Job) {
+	s.notify(ctx, s.jobRecipients(ctx, job), models.Notification{
+		Type:  models.NotificationJobExpiring,
+		Title: "Vaga perto de expirar",
+		Body:  fmt.Sprintf("A vaga %q expira em %s. Edite a data de validade para mantê-la no ar.", job.Title, job.ExpiresAt.Format("02/01/2006")),
+		Link:  "/company/jobs",
+		Data: map[string]string{
+			"job_id":     job.ID.Hex(),
+			"expires_at": job.ExpiresAt.Format(time.RFC3339),
+		},
+	})
+}
+
+// JobExpired avisa a empresa que a vaga foi desativada por ter expirado
+func (s *Service) JobExpired(ctx context.Context, job *jobs.Job) {
+	s.notify(ctx, s.jobRecipients(ctx, job), models.Notification{
+		Type:  models.NotificationJobExpired,
+		Title: "Vaga expirada",
+		Body:  fmt.Sprintf("A vaga %q expirou e foi desativada. Você pode reativá-la a qualquer momento.", job.Title),
+		Link:  "/company/jobs",
+		Data: map[string]string{
+			"job_id": job.ID.Hex(),
+		},
+	})
+}
+
+// NewMessage avisa o outro lado da conversa sobre uma mensagem nova: o candidato, ou o dono
+// e a equipe que acompanha a vaga
+func (s *Service) NewMessage(ctx context.Context, job *jobs.Job, thread *models.MessageThread, message *models.Message) {
+	recipients := []Recipient{{Type: models.RecipientCandidate, ID: thread.CandidateID}}
+	title := "Nova mensagem de " + thread.CompanyName
+	link := "/candidate/applications/" + thread.ApplicationID.Hex() + "/messages"
+	if message.SenderSide == models.MessageSideCandidate {
+		recipients = s.jobRecipients(ctx, job)
+		title = "Nova mensagem de " + thread.CandidateName
+		link = "/company/applications/" + thread.ApplicationID.Hex() + "/messages"
+	}
+
+	s.notify(ctx, recipients, models.Notification{
+		Type:  models.NotificationNewMessage,
+		Title: title,
+		Body:  fmt.Sprintf("Sobre a vaga %q: %s", thread.JobTitle, thread.LastMessagePreview),
+		Link:  link,
+		Data: map[string]string{
+			"thread_id":      thread.ID.Hex(),
+			"application_id": thread.ApplicationID.Hex(),
+			"message_id":     message.ID.Hex(),
+		},
+	})
+}
+
+// MessagesRead envia a confirmação de leitura em tempo real para o outro lado da conversa
+// (readerSide é quem leu)
+func (s *Service) MessagesRead(ctx context.Context, job *jobs.Job, thread *models.MessageThread, readerSide string, readAt time.Time) {
+	recipients := s.jobRecipients(ctx, job)
+	if readerSide == models.MessageSideCompany {
+		recipients = []Recipient{{Type: models.RecipientCandidate, ID: thread.CandidateID}}
+	}
+
+	payload := map[string]string{
+		"thread_id":      thread.ID.Hex(),
+		"application_id": thread.ApplicationID.Hex(),
+		"read_by":        readerSide,
+		"read_at":        readAt.UTC().Format(time.RFC3339),
+	}
+	for _, recipient := range recipients {
+		s.hub.Publish(ctx, events.Key(recipient.Type, recipient.ID.Hex()), EventMessagesRead, payload)
+	}
+}
+
+// InterviewProposed avisa o candidato que a empresa propôs horários de entrevista para ele escolher
+func (s *Service) InterviewProposed(ctx context.Context, interview *models.Interview) {
+	s.notify(ctx, []Recipient{{Type: models.RecipientCandidate, ID: interview.CandidateID}}, models.Notification{
+		Type:  models.NotificationInterviewProposed,
+		Title: "Convite para entrevista",
+		Body:  fmt.Sprintf("%s propôs %d horários de entrevista para a vaga %q. Escolha o melhor para você.", interview.CompanyName, len(interview.Slots), interview.JobTitle),
+		Link:  "/candidate/interviews/" + interview.ID.Hex(),
+		Data:  interviewData(interview),
+	})
+}
+
+// InterviewScheduled avisa o outro lado que a entrevista tem horário definido: o candidato, quando a
+// empresa agenda ou remarca, ou a equipe da vaga, quando o candidato escolhe o horário.
+// when é o horário já formatado no fuso da entrevista.
+func (s *Service) InterviewScheduled(ctx context.Context, job *jobs.Job, interview *models.Interview, when string, byCandidate bool) {
+	recipients := []Recipient{{Type: models.RecipientCandidate, ID: interview.CandidateID}}
+	body := fmt.Sprintf("Sua entrevista para a vaga %q (%s) está marcada para %s.", interview.JobTitle, interview.CompanyName, when)
+	link := "/candidate/interviews/" + interview.ID.Hex()
+	if byCandidate {
+		recipients = s.jobRecipients(ctx, job)
+		body = fmt.Sprintf("%s escolheu %s para a entrevista da vaga %q.", interview.CandidateName, when, interview.JobTitle)
+		link = "/company/interviews/" + interview.ID.Hex()
+	}
+
+	s.notify(ctx, recipients, models.Notification{
+		Type:  models.NotificationInterviewScheduled,
+		Title: "Entrevista agendada",
+		Body:  body,
+		Link:  link,
+		Data:  interviewData(interview),
+	})
+}
+
+// InterviewCanceled avisa o candidato que a empresa cancelou a entrevista
+func (s *Service) InterviewCanceled(ctx context.Context, interview *models.Interview) {
+	s.notify(ctx, []Recipient{{Type: models.RecipientCandidate, ID: interview.CandidateID}}, models.Notification{
+		Type:  models.NotificationInterviewCanceled,
+		Title: "Entrevista cancelada",
+		Body:  fmt.Sprintf("%s cancelou a entrevista da vaga %q.", interview.CompanyName, interview.JobTitle),
+		Link:  "/candidate/interviews/" + interview.ID.Hex(),
+		Data:  interviewData(interview),
+	})
+}
+
+// JobAlert avisa o candidato sobre vagas novas que casam com uma busca salva
+// (total inclui as vagas que não couberam na lista do alerta)
+func (s *Service) JobAlert(ctx context.Context, search *models.SavedSearch, found []*jobs.Job, total int) {
+	body := fmt.Sprintf("%d novas vagas para a sua busca %q.", total, search.Name)
+	if total == 1 {
+		body = fmt.Sprintf("Nova vaga para a sua busca %q: %s (%s).", search.Name, found[0].Title, found[0].Company)
+	}
+
+	jobIDs := make([]string, 0, len(found))
+	for _, job := range found {
+		jobIDs = append(jobIDs, job.ID.Hex())
+	}
+
+	s.notify(ctx, []Recipient{{Type: models.RecipientCandidate, ID: search.CandidateID}}, models.Notification{
+		Type:  models.NotificationJobAlert,
+		Title: "Novas vagas: " + search.Name,
+		Body:  body,
+		Link:  "/candidate/saved-searches/" + search.ID.Hex() + "/jobs",
+		Data: map[string]string{
+			"saved_search_id": search.ID.Hex(),
+			"job_ids":         strings.Join(jobIDs, ","),
+			"total":           strconv.Itoa(total),
+		},
+	})
+}
+
+func interviewData(interview *models.Interview) map[string]string {
+	data := map[string]string{
+		"interview_id":   interview.ID.Hex(),
+		"application_id": interview.ApplicationID.Hex(),
+		"job_id":         interview.JobID.Hex(),
+		"status":         interview.Status,
+	}
+	if interview.StartsAt != nil {
+		data["starts_at"] = interview.StartsAt.Format(time.RFC3339)
+	}
+	return data
+}
+
+// ContactRequested avisa o candidato que uma empresa pediu contato pelo banco de talentos
+func (s *Service) ContactRequested(ctx context.Context, request *models.ContactRequest) {
+	s.notify(ctx, []Recipient{{Type: models.RecipientCandidate, ID: request.CandidateID}}, models.Notification{
+		Type:  models.NotificationContactRequest,
+		Title: "Pedido de contato",
+		Body:  fmt.Sprintf("%s encontrou seu perfil no banco de talentos e quer falar com você.", request.CompanyName),
+		Link:  "/candidate/contact-requests",
+		Data:  map[string]string{"contact_request_id": request.ID.Hex(), "company_id": request.CompanyID.Hex()},
+	})
+}
+
+// ContactAccepted avisa a empresa (e o membro que fez o pedido) que o candidato liberou os contatos
+func (s *Service) ContactAccepted(ctx context.Context, request *models.ContactRequest) {
+	recipients := []Recipient{{Type: models.RecipientCompany, ID: request.CompanyID}}
+	if request.MemberID != nil {
+		recipients = append(recipients, Recipient{Type: models.RecipientMember, ID: *request.MemberID})
+	}
+
+	s.notify(ctx, recipients, models.Notification{
+		Type:  models.NotificationContactAccepted,
+		Title: "Contato aceito",
+		Body:  fmt.Sprintf("%s aceitou seu pedido de contato. Os dados de contato já estão disponíveis.", request.CandidateName),
+		Link:  "/company/talent/" + request.CandidateID.Hex(),
+		Data:  map[string]string{"contact_request_id": request.ID.Hex(), "candidate_id": request.CandidateID.Hex()},
+	})
+}
@@ -0,0 +1,92 @@
+package ratelimit
+
+import (
+	"context"
+	"hash/fnv"
+	"sync"
+	"time"
+)
+
+const memoryShards = 32
+
+type memoryEntry struct {
+	windowStart time.Time
+	current     int64
+	previous    int64
+	window      time.Duration
+}
+
+type memoryShard struct {
+	mu      sync.Mutex
+	entries map[string]*memoryEntry
+}
+
+// MemoryStore guarda os contadores em memória, divididos em shards para reduzir contenção.
+// Serve para uma única instância; com várias instâncias use MongoStore.
+type MemoryStore struct {
+	shards [memoryShards]*memoryShard
+}
+
+// NewMemoryStore cria o store e inicia a limpeza periódica de contadores expirados
+func NewMemoryStore(cleanupInterval time.Duration) *MemoryStore {
+	s := &MemoryStore{}
+	for i := range s.shards {
+		s.shards[i] = &memoryShard{entries: make(map[string]*memoryEntry)}
+	}
+	go s.cleanup(cleanupInterval)
+	return s
+}
+
+func (s *MemoryStore) shard(key string) *memoryShard {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return s.shards[h.Sum32()%memoryShards]
+}
+
+func (s *MemoryStore) Increment(ctx context.Context, key string, windowStart time.Time, window time.Duration) (int64, int64, error) {
+	shard := s.shard(key)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	entry, ok := shard.entries[key]
+	if !ok {
+		entry = &memoryEntry{windowStart: windowStart, window: window}
+		shard.entries[key] = entry
+	}
+
+	switch {
+	case entry.windowStart.Equal(windowStart):
+		// mesma janela
+	case entry.windowStart.Add(window).Equal(windowStart):
+		// janela seguinte: a atual vira a anterior
+		entry.previous = entry.current
+		entry.current = 0
+		entry.windowStart = windowStart
+	default:
+		// passou mais de uma janela sem requisições
+		entry.previous = 0
+		entry.current = 0
+		entry.windowStart = windowStart
+	}
+
+	entry.current++
+	return entry.current, entry.previous, nil
+}
+
+// cleanup remove contadores que não influenciam mais nenhuma janela
+func (s *MemoryStore) cleanup(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		for _, shard := range s.shards {
+			shard.mu.Lock()
+			for key, entry := range shard.entries {
+				if now.Sub(entry.windowStart) > 2*entry.window {
+					delete(shard.entries, key)
+				}
+			}
+			shard.mu.Unlock()
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// MongoStore compartilha os contadores entre instâncias usando uma collection com TTL.
+// Cada documento é o contador de uma chave em uma janela (equivalente a INCR + EXPIRE no Redis).
+type MongoStore struct {
+	collection *mongo.Collection
+}
+
+func NewMongoStore(db *mongo.Database) *MongoStore {
+	return &MongoStore{
+		collection: db.Collection("rate_limits"),
+	}
+}
+
+// EnsureIndexes cria o índice TTL que remove contadores de janelas antigas
+func (s *MongoStore) EnsureIndexes(ctx context.Context) error {
+	_, err := s.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	})
+	return err
+}
+
+type mongoCounter struct {
+	Count int64 `bson:"count"`
+}
+
+func windowID(key string, windowStart time.Time) string {
+	return key + "|" + strconv.FormatInt(windowStart.Unix(), 10)
+}
+
+func (s *MongoStore) Increment(ctx context.Context, key string, windowStart time.Time, window time.Duration) (int64, int64, error) {
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var current mongoCounter
+	err := s.collection.FindOneAndUpdate(ctx,
+		bson.M{"_id": windowID(key, windowStart)},
+		bson.M{
+			"$inc": bson.M{"count": 1},
+			// mantém o contador até o fim da janela seguinte (quando ainda serve de "anterior")
+			"$setOnInsert": bson.M{"expires_at": windowStart.Add(2 * window)},
+		},
+		opts,
+	).Decode(&current)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var previous mongoCounter
+	err = s.collection.FindOne(ctx, bson.M{"_id": windowID(key, windowStart.Add(-window))}).Decode(&previous)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return current.Count, 0, err
+	}
+
+	return current.Count, previous.Count, nil
+}
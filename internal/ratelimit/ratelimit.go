@@ -0,0 +1,103 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Store guarda os contadores por janela fixa usados pelo sliding window.
+// Increment soma 1 ao contador da janela atual e devolve também o da janela anterior.
+// A semântica (INCR + EXPIRE por chave de janela) é compatível com Redis.
+type Store interface {
+	Increment(ctx context.Context, key string, windowStart time.Time, window time.Duration) (current, previous int64, err error)
+}
+
+// Rule define um limite de requisições por janela
+type Rule struct {
+	Name       string        // identifica a regra na chave do contador e no header RateLimit-Policy
+	PathPrefix string        // prefixo de rota ao qual a regra se aplica ("" = qualquer rota)
+	Methods    []string      // métodos HTTP da regra (vazio = todos)
+	Limit      int           // requisições permitidas por janela
+	Window     time.Duration // tamanho da janela
+}
+
+func (rule Rule) matches(r *http.Request) bool {
+	if rule.PathPrefix != "" && !strings.HasPrefix(r.URL.Path, rule.PathPrefix) {
+		return false
+	}
+	if len(rule.Methods) == 0 {
+		return true
+	}
+	for _, method := range rule.Methods {
+		if method == r.Method {
+			return true
+		}
+	}
+	return false
+}
+
+// Result é o resultado de uma verificação de limite
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	Reset     time.Duration // tempo até a janela atual terminar
+}
+
+// Limiter aplica limites de taxa com o algoritmo de janela deslizante (sliding window counter):
+// contagem estimada = anterior * (fração restante da janela anterior) + atual
+type Limiter struct {
+	store       Store
+	rules       []Rule
+	defaultRule Rule
+}
+
+// NewLimiter cria um limiter. As regras são avaliadas em ordem e a primeira que casar é usada;
+// se nenhuma casar, defaultRule é aplicada.
+func NewLimiter(store Store, defaultRule Rule, rules ...Rule) *Limiter {
+	return &Limiter{
+		store:       store,
+		rules:       rules,
+		defaultRule: defaultRule,
+	}
+}
+
+// RuleFor retorna a regra aplicável à requisição
+func (l *Limiter) RuleFor(r *http.Request) Rule {
+	for _, rule := range l.rules {
+		if rule.matches(r) {
+			return rule
+		}
+	}
+	return l.defaultRule
+}
+
+// Allow contabiliza uma requisição para a chave e informa se ela está dentro do limite
+func (l *Limiter) Allow(ctx context.Context, key string, rule Rule) (Result, error) {
+	now := time.Now()
+	windowStart := now.Truncate(rule.Window)
+
+	current, previous, err := l.store.Increment(ctx, rule.Name+":"+key, windowStart, rule.Window)
+	if err != nil {
+		return Result{Allowed: true, Limit: rule.Limit, Remaining: rule.Limit}, err
+	}
+
+	elapsed := now.Sub(windowStart)
+	weight := 1 - float64(elapsed)/float64(rule.Window)
+	estimated := float64(previous)*weight + float64(current)
+
+	remaining := rule.Limit - int(math.Ceil(estimated))
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	return Result{
+		Allowed:   estimated <= float64(rule.Limit),
+		Limit:     rule.Limit,
+		Remaining: remaining,
+		Reset:     windowStart.Add(rule.Window).Sub(now),
+	}, nil
+}
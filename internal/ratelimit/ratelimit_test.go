@@ -0,0 +1,149 @@
+package ratelimit
+
+import (
+	"context"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMemoryStoreIncrement(t *testing.T) {
+	store := NewMemoryStore(time.Hour)
+	ctx := context.Background()
+	window := time.Minute
+	start := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	steps := []struct {
+		name         string
+		windowStart  time.Time
+		wantCurrent  int64
+		wantPrevious int64
+	}{
+		{"primeira requisição", start, 1, 0},
+		{"mesma janela", start, 2, 0},
+		{"mesma janela de novo", start, 3, 0},
+		{"janela seguinte: atual vira anterior", start.Add(window), 1, 3},
+		{"janela seguinte", start.Add(window), 2, 3},
+		{"pulou uma janela: contadores zerados", start.Add(3 * window), 1, 0},
+	}
+	for _, step := range steps {
+		current, previous, err := store.Increment(ctx, "login:1.2.3.4", step.windowStart, window)
+		if err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		if current != step.wantCurrent || previous != step.wantPrevious {
+			t.Errorf("%s: (%d, %d), esperado (%d, %d)", step.name, current, previous, step.wantCurrent, step.wantPrevious)
+		}
+	}
+
+	// Chaves diferentes não compartilham contador
+	if current, _, _ := store.Increment(ctx, "login:5.6.7.8", start.Add(3*window), window); current != 1 {
+		t.Errorf("outra chave começou em %d", current)
+	}
+}
+
+func TestMemoryStoreConcurrentIncrements(t *testing.T) {
+	store := NewMemoryStore(time.Hour)
+	start := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 200; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			store.Increment(context.Background(), "default:ip", start, time.Minute)
+		}()
+	}
+	wg.Wait()
+
+	if current, _, _ := store.Increment(context.Background(), "default:ip", start, time.Minute); current != 201 {
+		t.Errorf("contador = %d, esperado 201", current)
+	}
+}
+
+// fixedStore devolve sempre os mesmos contadores
+type fixedStore struct {
+	current, previous int64
+}
+
+func (s fixedStore) Increment(ctx context.Context, key string, windowStart time.Time, window time.Duration) (int64, int64, error) {
+	return s.current, s.previous, nil
+}
+
+func TestLimiterAllow(t *testing.T) {
+	// Sem janela anterior o resultado não depende do instante dentro da janela
+	rule := Rule{Name: "login", Limit: 5, Window: 100 * 365 * 24 * time.Hour}
+
+	tests := []struct {
+		name          string
+		store         fixedStore
+		wantAllowed   bool
+		wantRemaining int
+	}{
+		{"primeira requisição", fixedStore{current: 1}, true, 4},
+		{"no limite", fixedStore{current: 5}, true, 0},
+		{"acima do limite", fixedStore{current: 6}, false, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := NewLimiter(tt.store, rule).Allow(context.Background(), "1.2.3.4", rule)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Allowed != tt.wantAllowed || result.Remaining != tt.wantRemaining || result.Limit != rule.Limit {
+				t.Errorf("resultado %+v, esperado allowed=%v remaining=%d", result, tt.wantAllowed, tt.wantRemaining)
+			}
+			if result.Reset <= 0 || result.Reset > rule.Window {
+				t.Errorf("reset fora da janela: %s", result.Reset)
+			}
+		})
+	}
+}
+
+func TestLimiterBlocksAfterLimit(t *testing.T) {
+	rule := Rule{Name: "register", Limit: 3, Window: 100 * 365 * 24 * time.Hour}
+	limiter := NewLimiter(NewMemoryStore(time.Hour), rule)
+
+	for i := 1; i <= 4; i++ {
+		result, err := limiter.Allow(context.Background(), "1.2.3.4", rule)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := i <= 3; result.Allowed != want {
+			t.Errorf("requisição %d: allowed=%v, esperado %v", i, result.Allowed, want)
+		}
+	}
+
+	// Outro IP tem o próprio limite
+	if result, _ := limiter.Allow(context.Background(), "5.6.7.8", rule); !result.Allowed {
+		t.Error("outro IP bloqueado")
+	}
+}
+
+func TestLimiterRuleFor(t *testing.T) {
+	defaultRule := Rule{Name: "default", Limit: 300, Window: time.Minute}
+	limiter := NewLimiter(fixedStore{}, defaultRule,
+		Rule{Name: "login", PathPrefix: "/company/login", Methods: []string{"POST"}, Limit: 10, Window: time.Minute},
+		Rule{Name: "upload", PathPrefix: "/company/me/logo", Methods: []string{"POST"}, Limit: 30, Window: time.Hour},
+		Rule{Name: "upload", PathPrefix: "/company/me/cover", Methods: []string{"POST"}, Limit: 30, Window: time.Hour},
+	)
+
+	tests := []struct {
+		method, path, want string
+	}{
+		{"POST", "/company/login", "login"},
+		{"GET", "/company/login", "default"},
+		{"POST", "/company/me/logo", "upload"},
+		{"POST", "/company/me/cover", "upload"},
+		{"POST", "/company/me/members", "default"},
+		{"POST", "/company/me/webhooks", "default"},
+		{"GET", "/jobs", "default"},
+	}
+	for _, tt := range tests {
+		got := limiter.RuleFor(httptest.NewRequest(tt.method, tt.path, nil))
+		if got.Name != tt.want {
+			t.Errorf("%s %s: regra %q, esperado %q", tt.method, tt.path, got.Name, tt.want)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+// Package recommendations ordena as vagas abertas para cada candidato, combinando aderência das
+// habilidades, localização, nível, histórico (vagas salvas e candidaturas) e novidade.
+package recommendations
+
+import (
+	"empregabemapi/candidates"
+	"empregabemapi/internal/matching"
+	"empregabemapi/jobs"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Peso de cada critério na pontuação final (somam 100)
+const (
+	weightSkills    = 35.0
+	weightLocation  = 15.0
+	weightLevel     = 15.0
+	weightHistory   = 20.0
+	weightWorkModel = 5.0
+	weightRecency   = 7.0
+	weightPriority  = 3.0
+)
+
+// Meia-vida da novidade: uma vaga publicada há 14 dias vale metade dos pontos de novidade
+const recencyHalfLife = 14 * 24 * time.Hour
+
+// Recommendation é uma vaga recomendada com a pontuação (0 a 100) e os motivos
+type Recommendation struct {
+	Job     *jobs.Job        `json:"job"`
+	Score   int              `json:"score"`
+	Reasons []string         `json:"reasons"`
+	Match   *matching.Result `json:"match,omitempty"`
+}
+
+// Níveis das vagas em ordem de senioridade (já normalizados)
+var levelOrder = map[string]int{
+	"estagio":      0,
+	"trainee":      0,
+	"junior":       1,
+	"pleno":        2,
+	"senior":       3,
+	"especialista": 4,
+	"lead":         4,
+}
+
+var levelLabels = []string{"estágio", "júnior", "pleno", "sênior", "especialista"}
+
+// profile reúne o que o ranking usa do candidato
+type profile struct {
+	skills    []string
+	city      string
+	state     string
+	level     int
+	history   []map[string]bool
+	workModel map[string]float64 // fração do histórico em cada modelo de trabalho
+}
+
+// newProfile monta o perfil a partir do candidato e das vagas salvas/candidatadas (history)
+func newProfile(candidate *candidates.Candidate, history []*jobs.Job, now time.Time) *profile {
+	p := &profile{
+		skills:    candidate.Skills,
+		level:     levelOrder[candidates.InferLevel(candidate.Experiences, now)],
+		workModel: map[string]float64{},
+	}
+	p.city, p.state = splitLocation(candidate.Location)
+
+	for _, job := range history {
+		p.history = append(p.history, jobTerms(job))
+		if model := workModel(job); model != "" {
+			p.workModel[model] += 1 / float64(len(history))
+		}
+	}
+	return p
+}
+
+// rank pontua e ordena as vagas (maior pontuação primeiro; empate, mais recente primeiro)
+func rank(p *profile, open []*jobs.Job, now time.Time) []Recommendation {
+	result := make([]Recommendation, 0, len(open))
+	for _, job := range open {
+		result = append(result, p.score(job, now))
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Score != result[j].Score {
+			return result[i].Score > result[j].Score
+		}
+		return result[i].Job.CreatedAt.After(result[j].Job.CreatedAt)
+	})
+	return result
+}
+
+func (p *profile) score(job *jobs.Job, now time.Time) Recommendation {
+	rec := Recommendation{Job: job, Reasons: []string{}}
+	total := 0.0
+
+	// Habilidades × requisitos (vaga sem requisitos: neutro)
+	rec.Match = matching.Score(job.Requirements, p.skills)
+	if rec.Match == nil {
+		total += weightSkills * 0.5
+	} else {
+		total += weightSkills * float64(rec.Match.Score) / 100
+		if rec.Match.Score >= 50 {
+			matched := len(rec.Match.Matched)
+			rec.Reasons = append(rec.Reasons, fmt.Sprintf("Você atende %d de %d requisitos", matched, matched+len(rec.Match.Missing)))
+		}
+	}
+
+	// Localização: remota vale para todos; mesma cidade ou mesmo estado
+	model := workModel(job)
+	city, state := splitLocation(job.Location)
+	switch {
+	case model == "remoto":
+		total += weightLocation
+		rec.Reasons = append(rec.Reasons, "Vaga remota")
+	case p.city == "":
+		total += weightLocation * 0.5
+	case city != "" && (city == p.city || strings.Contains(normalize(job.Location), p.city)):
+		total += weightLocation
+		rec.Reasons = append(rec.Reasons, "Na sua cidade")
+	case state != "" && state == p.state:
+		total += weightLocation * 0.6
+		rec.Reasons = append(rec.Reasons, "No seu estado")
+	}
+
+	// Modelo de trabalho mais frequente no histórico do candidato
+	if share := p.workModel[model]; model != "" {
+		total += weightWorkModel * share
+		if share >= 0.5 && model != "remoto" {
+			rec.Reasons = append(rec.Reasons, "Modelo de trabalho que você costuma buscar ("+strings.ToLower(job.JobType)+")")
+		}
+	}
+
+	// Nível da vaga × nível inferido das experiências
+	jobLevel, known := levelOrder[normalize(job.Level)]
+	switch {
+	case !known:
+		total += weightLevel * 0.5
+	case jobLevel == p.level:
+		total += weightLevel
+		rec.Reasons = append(rec.Reasons, "Nível compatível com sua experiência ("+levelLabels[p.level]+")")
+	case jobLevel == p.level-1 || jobLevel == p.level+1:
+		total += weightLevel * 0.5
+	}
+
+	// Parecida com vagas salvas ou candidatadas
+	similarity := 0.0
+	terms := jobTerms(job)
+	for _, past := range p.history {
+		similarity = math.Max(similarity, jaccard(terms, past))
+	}
+	total += weightHistory * similarity
+	if similarity >= 0.3 {
+		rec.Reasons = append(rec.Reasons, "Parecida com vagas que você salvou ou se candidatou")
+	}
+
+	// Novidade e destaque
+	age := now.Sub(job.CreatedAt)
+	if age < 0 {
+		age = 0
+	}
+	total += weightRecency * math.Pow(0.5, float64(age)/float64(recencyHalfLife))
+	if age < 3*24*time.Hour {
+		rec.Reasons = append(rec.Reasons, "Publicada recentemente")
+	}
+	if job.Priority > 0 {
+		total += weightPriority
+		rec.Reasons = append(rec.Reasons, "Vaga em destaque")
+	}
+
+	rec.Score = int(math.Round(total))
+	return rec
+}
+
+// splitLocation separa "São Paulo, SP" (ou "São Paulo - SP") em cidade e UF normalizadas
+func splitLocation(location string) (city, state string) {
+	parts := strings.FieldsFunc(location, func(r rune) bool { return r == ',' || r == '-' || r == '/' })
+	if len(parts) == 0 {
+		return "", ""
+	}
+	city = normalize(parts[0])
+	if last := normalize(parts[len(parts)-1]); len(parts) > 1 && len(last) == 2 {
+		state = last
+	}
+	return city, state
+}
+
+// workModel retorna o modelo de trabalho normalizado (remoto, presencial, hibrido) ou vazio
+func workModel(job *jobs.Job) string {
+	model := normalize(job.JobType)
+	if model == "" && strings.Contains(normalize(job.Location), "remoto") {
+		model = "remoto"
+	}
+	return model
+}
+
+// jobTerms retorna os termos que descrevem a vaga (palavras do título e requisitos)
+func jobTerms(job *jobs.Job) map[string]bool {
+	terms := map[string]bool{}
+	for _, term := range job.ComputeSimilarityTerms() {
+		terms[term] = true
+	}
+	return terms
+}
+
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for term := range a {
+		if b[term] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+func normalize(s string) string {
+	return matching.Normalize(s)
+}
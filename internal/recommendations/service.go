@@ -0,0 +1,172 @@
+package recommendations
+
+import (
+	"context"
+	"empregabemapi/applications"
+	"empregabemapi/candidates"
+	"empregabemapi/internal/repository"
+	"empregabemapi/jobs"
+	"sort"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const (
+	// Tempo que um ranking fica em cache (vagas novas entram no próximo cálculo)
+	cacheTTL = 15 * time.Minute
+	// Máximo de candidatos em cache; ao lotar, os vencidos são descartados (ou todos, se nenhum venceu)
+	cacheMaxEntries = 10000
+	// Vagas salvas/candidatadas consideradas no histórico (as mais recentes)
+	maxHistoryJobs = 50
+)
+
+type cacheEntry struct {
+	version   time.Time // updated_at do perfil usado no cálculo
+	expiresAt time.Time
+	ranked    []Recommendation
+}
+
+// Service calcula as recomendações e guarda o ranking de cada candidato em memória. O cache é
+// invalidado quando o perfil muda: a entrada só vale para o updated_at do perfil usado no cálculo,
+// o que funciona também com várias instâncias da API.
+type Service struct {
+	jobRepo       *jobs.MongoRepository
+	appRepo       *applications.MongoRepository
+	savedJobsRepo *repository.SavedJobsRepository
+
+	mu    sync.Mutex
+	cache map[string]*cacheEntry
+}
+
+func NewService(jobRepo *jobs.MongoRepository, appRepo *applications.MongoRepository, savedJobsRepo *repository.SavedJobsRepository) *Service {
+	return &Service{
+		jobRepo:       jobRepo,
+		appRepo:       appRepo,
+		savedJobsRepo: savedJobsRepo,
+		cache:         make(map[string]*cacheEntry),
+	}
+}
+
+// ForCandidate retorna as vagas recomendadas ao candidato, da mais para a menos aderente, sem as
+// vagas em que ele já se candidatou
+func (s *Service) ForCandidate(ctx context.Context, candidate *candidates.Candidate) ([]Recommendation, error) {
+	candidateID := candidate.ID.Hex()
+
+	// As candidaturas são sempre lidas na hora: a vaga some das recomendações assim que o
+	// candidato se candidata, mesmo com o ranking em cache
+	apps, err := s.appRepo.GetByCandidateID(ctx, candidateID)
+	if err != nil {
+		return nil, err
+	}
+	applied := make(map[bson.ObjectID]bool, len(apps))
+	for _, app := range apps {
+		applied[app.JobID] = true
+	}
+
+	ranked, ok := s.cached(candidateID, candidate.UpdatedAt)
+	if !ok {
+		ranked, err = s.compute(ctx, candidate, apps)
+		if err != nil {
+			return nil, err
+		}
+		s.store(candidateID, candidate.UpdatedAt, ranked)
+	}
+
+	result := make([]Recommendation, 0, len(ranked))
+	for _, rec := range ranked {
+		if !applied[rec.Job.ID] {
+			result = append(result, rec)
+		}
+	}
+	return result, nil
+}
+
+// compute monta o perfil com o histórico do candidato e ordena as vagas no ar
+func (s *Service) compute(ctx context.Context, candidate *candidates.Candidate, apps []*applications.Application) ([]Recommendation, error) {
+	saved, err := s.savedJobsRepo.GetByCandidate(ctx, candidate.ID.Hex())
+	if err != nil {
+		return nil, err
+	}
+
+	// Histórico: vagas salvas e candidaturas, mais recentes primeiro
+	type historyItem struct {
+		jobID bson.ObjectID
+		at    time.Time
+	}
+	var items []historyItem
+	for _, item := range saved {
+		items = append(items, historyItem{item.JobID, item.SavedAt})
+	}
+	for _, app := range apps {
+		items = append(items, historyItem{app.JobID, app.AppliedAt})
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].at.After(items[j].at) })
+
+	seen := map[bson.ObjectID]bool{}
+	var historyIDs []bson.ObjectID
+	for _, item := range items {
+		if !seen[item.jobID] && len(historyIDs) < maxHistoryJobs {
+			seen[item.jobID] = true
+			historyIDs = append(historyIDs, item.jobID)
+		}
+	}
+
+	var history []*jobs.Job
+	if len(historyIDs) > 0 {
+		history, err = s.jobRepo.ListByIDs(ctx, historyIDs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	open, err := s.jobRepo.ListOpen(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	return rank(newProfile(candidate, history, now), open, now), nil
+}
+
+func (s *Service) cached(candidateID string, version time.Time) ([]Recommendation, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.cache[candidateID]
+	if !ok || !entry.version.Equal(version) || time.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.ranked, true
+}
+
+func (s *Service) store(candidateID string, version time.Time, ranked []Recommendation) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.cache) >= cacheMaxEntries {
+		now := time.Now()
+		for id, entry := range s.cache {
+			if now.After(entry.expiresAt) {
+				delete(s.cache, id)
+			}
+		}
+		if len(s.cache) >= cacheMaxEntries {
+			s.cache = make(map[string]*cacheEntry)
+		}
+	}
+
+	s.cache[candidateID] = &cacheEntry{
+		version:   version,
+		expiresAt: time.Now().Add(cacheTTL),
+		ranked:    ranked,
+	}
+}
+
+// Invalidate descarta o ranking em cache do candidato (ex: após salvar uma vaga)
+func (s *Service) Invalidate(candidateID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.cache, candidateID)
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"empregabemapi/internal/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type AuditRepository struct {
+	collection *mongo.Collection
+}
+
+func NewAuditRepository(db *mongo.Database) *AuditRepository {
+	return &AuditRepository{
+		collection: db.Collection("audit_logs"),
+	}
+}
+
+// EnsureIndexes cria os índices de consulta do log de auditoria
+func (r *AuditRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "action", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "target_type", Value: 1}, {Key: "target_id", Value: 1}}},
+	})
+	return err
+}
+
+// Log grava uma entrada de auditoria
+func (r *AuditRepository) Log(ctx context.Context, entry *models.AuditLog) error {
+	entry.ID = bson.NewObjectID()
+	entry.CreatedAt = time.Now()
+
+	_, err := r.collection.InsertOne(ctx, entry)
+	return err
+}
+
+// List retorna as entradas mais recentes, opcionalmente filtradas por ação
+func (r *AuditRepository) List(ctx context.Context, action string, limit int64) ([]*models.AuditLog, error) {
+	filter := bson.M{}
+	if action != "" {
+		filter["action"] = action
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(limit)
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var entries []*models.AuditLog
+	if err = cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"empregabemapi/internal/models"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+var ErrAlreadyReviewed = errors.New("empresa já avaliada por este candidato")
+
+type CompanyReviewsRepository struct {
+	collection *mongo.Collection
+}
+
+func NewCompanyReviewsRepository(db *mongo.Database) *CompanyReviewsRepository {
+	return &CompanyReviewsRepository{
+		collection: db.Collection("company_reviews"),
+	}
+}
+
+// EnsureIndexes cria o índice único (uma avaliação por candidato e empresa), o do perfil público
+// e o da fila de moderação
+func (r *CompanyReviewsRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "company_id", Value: 1}, {Key: "candidate_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "company_id", Value: 1}, {Key: "status", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: 1}}},
+	})
+	return err
+}
+
+// Create grava a avaliação como pendente de moderação.
+// Retorna ErrAlreadyReviewed se o candidato já avaliou a empresa.
+func (r *CompanyReviewsRepository) Create(ctx context.Context, review *models.CompanyReview) error {
+	review.ID = bson.NewObjectID()
+	review.Status = models.ReviewStatusPending
+	review.CreatedAt = time.Now()
+
+	_, err := r.collection.InsertOne(ctx, review)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrAlreadyReviewed
+	}
+	return err
+}
+
+func (r *CompanyReviewsRepository) GetByID(ctx context.Context, id bson.ObjectID) (*models.CompanyReview, error) {
+	var review models.CompanyReview
+	if err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&review); err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+// ListByStatus lista a fila de moderação (mais antigas primeiro)
+func (r *CompanyReviewsRepository) ListByStatus(ctx context.Context, status string, limit int64) ([]*models.CompanyReview, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}).SetLimit(limit)
+	return r.find(ctx, bson.M{"status": status}, opts)
+}
+
+// ListApprovedByCompany lista as avaliações publicadas da empresa (mais recentes primeiro)
+func (r *CompanyReviewsRepository) ListApprovedByCompany(ctx context.Context, companyID bson.ObjectID, limit int64) ([]*models.CompanyReview, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(limit)
+	return r.find(ctx, bson.M{"company_id": companyID, "status": models.ReviewStatusApproved}, opts)
+}
+
+func (r *CompanyReviewsRepository) find(ctx context.Context, filter bson.M, opts *options.FindOptionsBuilder) ([]*models.CompanyReview, error) {
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var reviews []*models.CompanyReview
+	if err = cursor.All(ctx, &reviews); err != nil {
+		return nil, err
+	}
+
+	return reviews, nil
+}
+
+// Summarize calcula as médias das avaliações aprovadas da empresa (Total 0 se não houver)
+func (r *CompanyReviewsRepository) Summarize(ctx context.Context, companyID bson.ObjectID) (*models.CompanyReviewSummary, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"company_id": companyID, "status": models.ReviewStatusApproved}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":                  nil,
+			"total":                bson.M{"$sum": 1},
+			"rating":               bson.M{"$avg": "$rating"},
+			"communication":        bson.M{"$avg": "$communication"},
+			"response_time":        bson.M{"$avg": "$response_time"},
+			"interview_difficulty": bson.M{"$avg": "$interview_difficulty"},
+		}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	summary := &models.CompanyReviewSummary{}
+	if cursor.Next(ctx) {
+		if err := cursor.Decode(summary); err != nil {
+			return nil, err
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	summary.Round()
+	return summary, nil
+}
+
+// SetModeration registra a decisão do admin sobre a avaliação
+func (r *CompanyReviewsRepository) SetModeration(ctx context.Context, id bson.ObjectID, status, reason, adminID string) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{
+		"status":            status,
+		"moderation_reason": reason,
+		"moderated_by":      adminID,
+		"moderated_at":      time.Now(),
+	}})
+	return err
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"empregabemapi/internal/models"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// ErrEmailChangeUsed indica que a confirmação já foi usada, substituída ou expirou
+var ErrEmailChangeUsed = errors.New("confirmação de email já utilizada ou expirada")
+
+type EmailChangeRepository struct {
+	collection *mongo.Collection
+}
+
+func NewEmailChangeRepository(db *mongo.Database) *EmailChangeRepository {
+	return &EmailChangeRepository{
+		collection: db.Collection("email_changes"),
+	}
+}
+
+// EnsureIndexes cria o índice TTL em expires_at e o índice único em token_hash
+func (r *EmailChangeRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+		{
+			Keys:    bson.D{{Key: "token_hash", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "user_type", Value: 1}}},
+	})
+	return err
+}
+
+// Create salva uma nova solicitação, invalidando as anteriores do mesmo usuário
+func (r *EmailChangeRepository) Create(ctx context.Context, change *models.EmailChange) error {
+	_, err := r.collection.UpdateMany(ctx, bson.M{
+		"user_id":   change.UserID,
+		"user_type": change.UserType,
+		"used":      false,
+	}, bson.M{"$set": bson.M{"used": true}})
+	if err != nil {
+		return err
+	}
+
+	change.ID = bson.NewObjectID()
+	change.CreatedAt = time.Now()
+	change.Used = false
+
+	_, err = r.collection.InsertOne(ctx, change)
+	return err
+}
+
+// GetByToken busca uma solicitação pendente pelo hash do token
+func (r *EmailChangeRepository) GetByToken(ctx context.Context, tokenHash string) (*models.EmailChange, error) {
+	var change models.EmailChange
+	err := r.collection.FindOne(ctx, bson.M{
+		"token_hash": tokenHash,
+		"used":       false,
+		"expires_at": bson.M{"$gt": time.Now()},
+	}).Decode(&change)
+	if err != nil {
+		return nil, err
+	}
+	return &change, nil
+}
+
+// MarkAsUsed marca a solicitação como usada de forma atômica (uso único)
+func (r *EmailChangeRepository) MarkAsUsed(ctx context.Context, id bson.ObjectID) error {
+	now := time.Now()
+	result, err := r.collection.UpdateOne(ctx, bson.M{
+		"_id":        id,
+		"used":       false,
+		"expires_at": bson.M{"$gt": now},
+	}, bson.M{"$set": bson.M{"used": true, "used_at": now}})
+	if err != nil {
+		return err
+	}
+	if result.ModifiedCount == 0 {
+		return ErrEmailChangeUsed
+	}
+	return nil
+}
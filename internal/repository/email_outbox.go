@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"empregabemapi/internal/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// Tempo que um worker pode manter um email "em envio" antes de outro reaproveitá-lo
+const outboxLockDuration = 5 * time.Minute
+
+type EmailOutboxRepository struct {
+	collection *mongo.Collection
+}
+
+func NewEmailOutboxRepository(db *mongo.Database) *EmailOutboxRepository {
+	return &EmailOutboxRepository{
+		collection: db.Collection("email_outbox"),
+	}
+}
+
+// EnsureIndexes cria os índices usados pelo worker e remove emails enviados após 30 dias
+func (r *EmailOutboxRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}}},
+		{
+			Keys:    bson.D{{Key: "sent_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32((30 * 24 * time.Hour).Seconds())),
+		},
+	})
+	return err
+}
+
+// Enqueue adiciona um email ao outbox para envio assíncrono
+func (r *EmailOutboxRepository) Enqueue(ctx context.Context, email *models.OutboxEmail) error {
+	now := time.Now()
+	email.ID = bson.NewObjectID()
+	email.Status = models.OutboxStatusPending
+	email.Attempts = 0
+	email.CreatedAt = now
+	email.NextAttemptAt = now
+
+	_, err := r.collection.InsertOne(ctx, email)
+	return err
+}
+
+// ClaimNext reserva o próximo email pronto para envio.
+// Emails "sending" com lock expirado (worker caiu no meio do envio) também são reaproveitados.
+// Retorna mongo.ErrNoDocuments quando não há nada para enviar.
+func (r *EmailOutboxRepository) ClaimNext(ctx context.Context) (*models.OutboxEmail, error) {
+	now := time.Now()
+	filter := bson.M{
+		"$or": []bson.M{
+			{"status": models.OutboxStatusPending, "next_attempt_at": bson.M{"$lte": now}},
+			{"status": models.OutboxStatusSending, "locked_until": bson.M{"$lt": now}},
+		},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"status":       models.OutboxStatusSending,
+			"locked_until": now.Add(outboxLockDuration),
+		},
+		"$inc": bson.M{"attempts": 1},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var email models.OutboxEmail
+	if err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&email); err != nil {
+		return nil, err
+	}
+	return &email, nil
+}
+
+// MarkSent marca um email como enviado. O conteúdo é apagado: links de reset de senha e de
+// confirmação não podem ficar legíveis no outbox até o TTL remover o documento.
+func (r *EmailOutboxRepository) MarkSent(ctx context.Context, id bson.ObjectID) error {
+	now := time.Now()
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{
+		"$set":   bson.M{"status": models.OutboxStatusSent, "sent_at": now, "html_body": "", "text_body": ""},
+		"$unset": bson.M{"locked_until": "", "last_error": "", "attachments": ""},
+	})
+	return err
+}
+
+// MarkRetry devolve o email para a fila com uma nova tentativa agendada
+func (r *EmailOutboxRepository) MarkRetry(ctx context.Context, id bson.ObjectID, lastErr string, nextAttempt time.Time) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{
+		"$set": bson.M{
+			"status":          models.OutboxStatusPending,
+			"last_error":      lastErr,
+			"next_attempt_at": nextAttempt,
+		},
+		"$unset": bson.M{"locked_until": ""},
+	})
+	return err
+}
+
+// MarkFailed marca um email como falho definitivamente (esgotou as tentativas ou é inválido).
+// O conteúdo é apagado como em MarkSent.
+func (r *EmailOutboxRepository) MarkFailed(ctx context.Context, id bson.ObjectID, lastErr string) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{
+		"$set":   bson.M{"status": models.OutboxStatusFailed, "last_error": lastErr, "html_body": "", "text_body": ""},
+		"$unset": bson.M{"locked_until": "", "attachments": ""},
+	})
+	return err
+}
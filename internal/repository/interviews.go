@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"empregabemapi/internal/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type InterviewsRepository struct {
+	collection *mongo.Collection
+}
+
+func NewInterviewsRepository(db *mongo.Database) *InterviewsRepository {
+	return &InterviewsRepository{
+		collection: db.Collection("interviews"),
+	}
+}
+
+// EnsureIndexes cria os índices das entrevistas por candidatura e das agendas da empresa e do candidato
+func (r *InterviewsRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "application_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "company_id", Value: 1}, {Key: "sort_at", Value: 1}}},
+		{Keys: bson.D{{Key: "candidate_id", Value: 1}, {Key: "sort_at", Value: 1}}},
+	})
+	return err
+}
+
+func (r *InterviewsRepository) Create(ctx context.Context, interview *models.Interview) error {
+	now := time.Now()
+	interview.ID = bson.NewObjectID()
+	interview.CreatedAt = now
+	interview.UpdatedAt = now
+
+	_, err := r.collection.InsertOne(ctx, interview)
+	return err
+}
+
+// GetByID busca a entrevista (mongo.ErrNoDocuments se não existir)
+func (r *InterviewsRepository) GetByID(ctx context.Context, id bson.ObjectID) (*models.Interview, error) {
+	var interview models.Interview
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&interview)
+	if err != nil {
+		return nil, err
+	}
+	return &interview, nil
+}
+
+// Update grava a nova versão da entrevista se ninguém a alterou desde a leitura
+// (previousSequence é o Sequence lido). Retorna false se houve alteração concorrente.
+func (r *InterviewsRepository) Update(ctx context.Context, interview *models.Interview, previousSequence int) (bool, error) {
+	interview.UpdatedAt = time.Now()
+
+	result, err := r.collection.ReplaceOne(ctx, bson.M{"_id": interview.ID, "sequence": previousSequence}, interview)
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
+
+// ListByApplication retorna as entrevistas da candidatura, mais recentes primeiro
+func (r *InterviewsRepository) ListByApplication(ctx context.Context, applicationID bson.ObjectID) ([]*models.Interview, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	return r.find(ctx, bson.M{"application_id": applicationID}, opts)
+}
+
+// InterviewFilter restringe a agenda por período (pelo horário definido ou o primeiro proposto)
+// e situação. Zero/vazio = sem restrição.
+type InterviewFilter struct {
+	From   time.Time
+	To     time.Time
+	Status string
+	Limit  int64
+}
+
+func (f InterviewFilter) apply(filter bson.M) bson.M {
+	period := bson.M{}
+	if !f.From.IsZero() {
+		period["$gte"] = f.From
+	}
+	if !f.To.IsZero() {
+		period["$lt"] = f.To
+	}
+	if len(period) > 0 {
+		filter["sort_at"] = period
+	}
+	if f.Status != "" {
+		filter["status"] = f.Status
+	}
+	return filter
+}
+
+// ListByCompany retorna a agenda de entrevistas da empresa em ordem cronológica.
+// jobIDs restringe às vagas informadas (nil = todas as vagas da empresa).
+func (r *InterviewsRepository) ListByCompany(ctx context.Context, companyID bson.ObjectID, jobIDs []bson.ObjectID, f InterviewFilter) ([]*models.Interview, error) {
+	filter := bson.M{"company_id": companyID}
+	if jobIDs != nil {
+		filter["job_id"] = bson.M{"$in": jobIDs}
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "sort_at", Value: 1}}).SetLimit(f.Limit)
+	return r.find(ctx, f.apply(filter), opts)
+}
+
+// ListByCandidate retorna as entrevistas do candidato em ordem cronológica
+func (r *InterviewsRepository) ListByCandidate(ctx context.Context, candidateID bson.ObjectID, f InterviewFilter) ([]*models.Interview, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "sort_at", Value: 1}}).SetLimit(f.Limit)
+	return r.find(ctx, f.apply(bson.M{"candidate_id": candidateID}), opts)
+}
+
+func (r *InterviewsRepository) find(ctx context.Context, filter bson.M, opts *options.FindOptionsBuilder) ([]*models.Interview, error) {
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var interviews []*models.Interview
+	if err = cursor.All(ctx, &interviews); err != nil {
+		return nil, err
+	}
+
+	return interviews, nil
+}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"empregabemapi/internal/models"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+var ErrAlreadyReported = errors.New("vaga já denunciada por este candidato")
+
+type JobReportsRepository struct {
+	collection *mongo.Collection
+}
+
+func NewJobReportsRepository(db *mongo.Database) *JobReportsRepository {
+	return &JobReportsRepository{
+		collection: db.Collection("job_reports"),
+	}
+}
+
+// EnsureIndexes cria o índice único (uma denúncia por candidato e vaga) e o da triagem
+func (r *JobReportsRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "job_id", Value: 1}, {Key: "candidate_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: -1}}},
+	})
+	return err
+}
+
+// Create grava a denúncia. Retorna ErrAlreadyReported se o candidato já denunciou a vaga.
+func (r *JobReportsRepository) Create(ctx context.Context, report *models.JobReport) error {
+	report.ID = bson.NewObjectID()
+	report.Status = models.ReportStatusOpen
+	report.CreatedAt = time.Now()
+
+	_, err := r.collection.InsertOne(ctx, report)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrAlreadyReported
+	}
+	return err
+}
+
+// CountOpenByJob conta as denúncias abertas (de candidatos distintos) de uma vaga
+func (r *JobReportsRepository) CountOpenByJob(ctx context.Context, jobID bson.ObjectID) (int64, error) {
+	return r.collection.CountDocuments(ctx, bson.M{"job_id": jobID, "status": models.ReportStatusOpen})
+}
+
+// SummarizeOpen agrupa as denúncias abertas por vaga, mais denunciadas primeiro
+func (r *JobReportsRepository) SummarizeOpen(ctx context.Context, limit int64) ([]*models.JobReportSummary, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"status": models.ReportStatusOpen}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":             "$job_id",
+			"company_id":      bson.M{"$first": "$company_id"},
+			"reports":         bson.M{"$sum": 1},
+			"categories":      bson.M{"$push": "$category"},
+			"first_report_at": bson.M{"$min": "$created_at"},
+			"last_report_at":  bson.M{"$max": "$created_at"},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "reports", Value: -1}, {Key: "last_report_at", Value: -1}}}},
+		{{Key: "$limit", Value: limit}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var summaries []*models.JobReportSummary
+	if err = cursor.All(ctx, &summaries); err != nil {
+		return nil, err
+	}
+
+	for _, s := range summaries {
+		s.Categories = make(map[string]int)
+		for _, category := range s.CategoryList {
+			s.Categories[category]++
+		}
+	}
+
+	return summaries, nil
+}
+
+// ListByJob retorna as denúncias de uma vaga (mais recentes primeiro), opcionalmente filtradas por status
+func (r *JobReportsRepository) ListByJob(ctx context.Context, jobID bson.ObjectID, status string) ([]*models.JobReport, error) {
+	filter := bson.M{"job_id": jobID}
+	if status != "" {
+		filter["status"] = status
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var reports []*models.JobReport
+	if err = cursor.All(ctx, &reports); err != nil {
+		return nil, err
+	}
+
+	return reports, nil
+}
+
+// ResolveOpenByJob encerra todas as denúncias abertas da vaga com o status informado
+func (r *JobReportsRepository) ResolveOpenByJob(ctx context.Context, jobID bson.ObjectID, status, adminID, note string) (int64, error) {
+	now := time.Now()
+	result, err := r.collection.UpdateMany(ctx,
+		bson.M{"job_id": jobID, "status": models.ReportStatusOpen},
+		bson.M{"$set": bson.M{
+			"status":          status,
+			"resolved_by":     adminID,
+			"resolution_note": note,
+			"resolved_at":     now,
+		}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"empregabemapi/internal/models"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// JWTKeysRepository persiste as chaves de assinatura para que todas as instâncias usem o mesmo conjunto
+type JWTKeysRepository struct {
+	collection *mongo.Collection
+}
+
+func NewJWTKeysRepository(db *mongo.Database) *JWTKeysRepository {
+	return &JWTKeysRepository{
+		collection: db.Collection("jwt_keys"),
+	}
+}
+
+// EnsureIndexes cria o índice TTL que remove chaves aposentadas
+func (r *JWTKeysRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "retires_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	})
+	return err
+}
+
+// List retorna todas as chaves ainda não removidas
+func (r *JWTKeysRepository) List(ctx context.Context) ([]*models.JWTKey, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "activates_at", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var keys []*models.JWTKey
+	if err = cursor.All(ctx, &keys); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// Insert salva uma nova chave
+func (r *JWTKeysRepository) Insert(ctx context.Context, key *models.JWTKey) error {
+	_, err := r.collection.InsertOne(ctx, key)
+	return err
+}
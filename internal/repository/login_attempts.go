@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"empregabemapi/internal/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type LoginAttemptsRepository struct {
+	collection *mongo.Collection
+}
+
+func NewLoginAttemptsRepository(db *mongo.Database) *LoginAttemptsRepository {
+	return &LoginAttemptsRepository{
+		collection: db.Collection("login_attempts"),
+	}
+}
+
+// EnsureIndexes cria o índice único por chave e o TTL que limpa registros inativos
+func (r *LoginAttemptsRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "key", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	})
+	return err
+}
+
+// Get busca o registro de tentativas de uma chave.
+// Retorna mongo.ErrNoDocuments se não houver falhas registradas.
+func (r *LoginAttemptsRepository) Get(ctx context.Context, key string) (*models.LoginAttempt, error) {
+	var attempt models.LoginAttempt
+	if err := r.collection.FindOne(ctx, bson.M{"key": key}).Decode(&attempt); err != nil {
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+// RecordFailure incrementa o contador de falhas de forma atômica e retorna o registro atualizado.
+// Se a última falha for anterior a resetBefore, o contador recomeça do zero.
+func (r *LoginAttemptsRepository) RecordFailure(ctx context.Context, key, kind string, resetBefore time.Time, retention time.Duration) (*models.LoginAttempt, error) {
+	now := time.Now()
+
+	// Zera contadores de falhas antigas (fora da janela e sem bloqueio ativo)
+	_, err := r.collection.UpdateOne(ctx, bson.M{
+		"key":             key,
+		"last_failure_at": bson.M{"$lt": resetBefore},
+		"$or": []bson.M{
+			{"locked_until": bson.M{"$exists": false}},
+			{"locked_until": bson.M{"$lt": now}},
+		},
+	}, bson.M{"$set": bson.M{"failures": 0}})
+	if err != nil {
+		return nil, err
+	}
+
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	var attempt models.LoginAttempt
+	err = r.collection.FindOneAndUpdate(ctx, bson.M{"key": key}, bson.M{
+		"$inc":         bson.M{"failures": 1},
+		"$set":         bson.M{"last_failure_at": now, "expires_at": now.Add(retention)},
+		"$setOnInsert": bson.M{"kind": kind, "lock_count": 0},
+	}, opts).Decode(&attempt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &attempt, nil
+}
+
+// Lock bloqueia a chave até o horário informado e zera as falhas do ciclo atual
+func (r *LoginAttemptsRepository) Lock(ctx context.Context, key string, until time.Time, retention time.Duration) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"key": key}, bson.M{
+		"$set": bson.M{
+			"locked_until": until,
+			"failures":     0,
+			"expires_at":   until.Add(retention),
+		},
+		"$inc": bson.M{"lock_count": 1},
+	})
+	return err
+}
+
+// Reset remove o registro de tentativas (login bem-sucedido ou desbloqueio)
+func (r *LoginAttemptsRepository) Reset(ctx context.Context, key string) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"key": key})
+	return err
+}
@@ -0,0 +1,235 @@
+package repository
+
+import (
+	"context"
+	"empregabemapi/internal/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// Tamanho do trecho da última mensagem exibido na lista de conversas
+const messagePreviewLength = 140
+
+type MessagesRepository struct {
+	threads  *mongo.Collection
+	messages *mongo.Collection
+}
+
+func NewMessagesRepository(db *mongo.Database) *MessagesRepository {
+	return &MessagesRepository{
+		threads:  db.Collection("message_threads"),
+		messages: db.Collection("messages"),
+	}
+}
+
+// EnsureIndexes cria a unicidade de uma conversa por candidatura, as listagens de cada lado
+// e a paginação das mensagens
+func (r *MessagesRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.threads.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "application_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "candidate_id", Value: 1}, {Key: "last_message_at", Value: -1}}},
+		{Keys: bson.D{{Key: "company_id", Value: 1}, {Key: "last_message_at", Value: -1}}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = r.messages.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "thread_id", Value: 1}, {Key: "_id", Value: -1}},
+	})
+	return err
+}
+
+// GetThreadByApplication busca a conversa da candidatura (mongo.ErrNoDocuments se não existir)
+func (r *MessagesRepository) GetThreadByApplication(ctx context.Context, applicationID bson.ObjectID) (*models.MessageThread, error) {
+	var thread models.MessageThread
+	err := r.threads.FindOne(ctx, bson.M{"application_id": applicationID}).Decode(&thread)
+	if err != nil {
+		return nil, err
+	}
+	return &thread, nil
+}
+
+// GetOrCreateThread abre a conversa da candidatura ou retorna a existente
+// (duas aberturas simultâneas resultam na mesma conversa)
+func (r *MessagesRepository) GetOrCreateThread(ctx context.Context, thread *models.MessageThread) (*models.MessageThread, error) {
+	now := time.Now()
+	thread.ID = bson.NewObjectID()
+	thread.LastMessageAt = now
+	thread.CreatedAt = now
+
+	_, err := r.threads.InsertOne(ctx, thread)
+	if mongo.IsDuplicateKeyError(err) {
+		return r.GetThreadByApplication(ctx, thread.ApplicationID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return thread, nil
+}
+
+// AddMessage grava a mensagem e atualiza a conversa (também em thread): última mensagem e
+// não lidas do outro lado. O ID pode vir preenchido (anexos já gravados com ele na chave).
+func (r *MessagesRepository) AddMessage(ctx context.Context, thread *models.MessageThread, message *models.Message) error {
+	if message.ID.IsZero() {
+		message.ID = bson.NewObjectID()
+	}
+	message.ThreadID = thread.ID
+	message.CreatedAt = time.Now()
+
+	if _, err := r.messages.InsertOne(ctx, message); err != nil {
+		return err
+	}
+
+	preview := []rune(message.Body)
+	if len(preview) > messagePreviewLength {
+		preview = append(preview[:messagePreviewLength-1], '…')
+	}
+	if len(preview) == 0 && len(message.Attachments) > 0 {
+		preview = []rune("📎 " + message.Attachments[0].Filename)
+	}
+
+	unreadField := "unread_company"
+	if message.SenderSide == models.MessageSideCompany {
+		unreadField = "unread_candidate"
+		thread.UnreadCandidate++
+	} else {
+		thread.UnreadCompany++
+	}
+	thread.LastMessageAt = message.CreatedAt
+	thread.LastMessagePreview = string(preview)
+	thread.LastSenderSide = message.SenderSide
+	thread.MessageCount++
+
+	_, err := r.threads.UpdateOne(ctx, bson.M{"_id": thread.ID}, bson.M{
+		"$set": bson.M{
+			"last_message_at":      message.CreatedAt,
+			"last_message_preview": string(preview),
+			"last_sender_side":     message.SenderSide,
+		},
+		"$inc": bson.M{"message_count": 1, unreadField: 1},
+	})
+	return err
+}
+
+// ListMessages retorna uma página de mensagens da conversa, mais recentes primeiro.
+// before pagina pelo ID da mensagem mais antiga já recebida (zero para a primeira página).
+func (r *MessagesRepository) ListMessages(ctx context.Context, threadID, before bson.ObjectID, limit int64) ([]*models.Message, error) {
+	filter := bson.M{"thread_id": threadID}
+	if !before.IsZero() {
+		filter["_id"] = bson.M{"$lt": before}
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: -1}}).SetLimit(limit)
+	cursor, err := r.messages.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var messages []*models.Message
+	if err = cursor.All(ctx, &messages); err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+// GetMessage busca uma mensagem da conversa
+func (r *MessagesRepository) GetMessage(ctx context.Context, id, threadID bson.ObjectID) (*models.Message, error) {
+	var message models.Message
+	err := r.messages.FindOne(ctx, bson.M{"_id": id, "thread_id": threadID}).Decode(&message)
+	if err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
+
+// MarkRead zera as não lidas do lado informado e registra o momento da leitura
+// (as mensagens do outro lado enviadas até lá contam como lidas)
+func (r *MessagesRepository) MarkRead(ctx context.Context, threadID bson.ObjectID, side string) (time.Time, error) {
+	now := time.Now()
+	_, err := r.threads.UpdateOne(ctx, bson.M{"_id": threadID}, bson.M{
+		"$set": bson.M{
+			"unread_" + side:  0,
+			side + "_read_at": now,
+		},
+	})
+	return now, err
+}
+
+// ListCandidateThreads retorna as conversas do candidato, mais recentes primeiro
+func (r *MessagesRepository) ListCandidateThreads(ctx context.Context, candidateID bson.ObjectID, limit int64) ([]*models.MessageThread, error) {
+	return r.listThreads(ctx, bson.M{"candidate_id": candidateID}, limit)
+}
+
+// ListCompanyThreads retorna as conversas da empresa, mais recentes primeiro.
+// jobIDs restringe às vagas informadas (nil = todas as vagas da empresa).
+func (r *MessagesRepository) ListCompanyThreads(ctx context.Context, companyID bson.ObjectID, jobIDs []bson.ObjectID, limit int64) ([]*models.MessageThread, error) {
+	return r.listThreads(ctx, companyThreadsFilter(companyID, jobIDs), limit)
+}
+
+func companyThreadsFilter(companyID bson.ObjectID, jobIDs []bson.ObjectID) bson.M {
+	filter := bson.M{"company_id": companyID}
+	if jobIDs != nil {
+		filter["job_id"] = bson.M{"$in": jobIDs}
+	}
+	return filter
+}
+
+func (r *MessagesRepository) listThreads(ctx context.Context, filter bson.M, limit int64) ([]*models.MessageThread, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "last_message_at", Value: -1}}).SetLimit(limit)
+	cursor, err := r.threads.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var threads []*models.MessageThread
+	if err = cursor.All(ctx, &threads); err != nil {
+		return nil, err
+	}
+
+	return threads, nil
+}
+
+// CountCandidateUnread soma as mensagens não lidas do candidato em todas as conversas
+func (r *MessagesRepository) CountCandidateUnread(ctx context.Context, candidateID bson.ObjectID) (int64, error) {
+	return r.sumUnread(ctx, bson.M{"candidate_id": candidateID}, "unread_candidate")
+}
+
+// CountCompanyUnread soma as mensagens não lidas da empresa (nas vagas de jobIDs, nil = todas)
+func (r *MessagesRepository) CountCompanyUnread(ctx context.Context, companyID bson.ObjectID, jobIDs []bson.ObjectID) (int64, error) {
+	return r.sumUnread(ctx, companyThreadsFilter(companyID, jobIDs), "unread_company")
+}
+
+func (r *MessagesRepository) sumUnread(ctx context.Context, filter bson.M, field string) (int64, error) {
+	filter[field] = bson.M{"$gt": 0}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$group", Value: bson.M{"_id": nil, "total": bson.M{"$sum": "$" + field}}}},
+	}
+
+	cursor, err := r.threads.Aggregate(ctx, pipeline)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var result []struct {
+		Total int64 `bson:"total"`
+	}
+	if err = cursor.All(ctx, &result); err != nil {
+		return 0, err
+	}
+	if len(result) == 0 {
+		return 0, nil
+	}
+	return result[0].Total, nil
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"empregabemapi/internal/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// Notificações mais antigas que isso são removidas pelo MongoDB (lidas ou não)
+const notificationRetention = 90 * 24 * time.Hour
+
+type NotificationsRepository struct {
+	collection *mongo.Collection
+}
+
+func NewNotificationsRepository(db *mongo.Database) *NotificationsRepository {
+	return &NotificationsRepository{
+		collection: db.Collection("notifications"),
+	}
+}
+
+// EnsureIndexes cria os índices da listagem por destinatário, da contagem de não lidas e o TTL
+func (r *NotificationsRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "recipient_type", Value: 1}, {Key: "recipient_id", Value: 1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "recipient_type", Value: 1}, {Key: "recipient_id", Value: 1}, {Key: "read_at", Value: 1}}},
+		{
+			Keys:    bson.D{{Key: "created_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32(notificationRetention.Seconds())),
+		},
+	})
+	return err
+}
+
+// CreateMany grava as notificações de um evento (uma por destinatário)
+func (r *NotificationsRepository) CreateMany(ctx context.Context, notifications []*models.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	docs := make([]interface{}, 0, len(notifications))
+	for _, n := range notifications {
+		n.ID = bson.NewObjectID()
+		n.ReadAt = nil
+		n.CreatedAt = now
+		docs = append(docs, n)
+	}
+
+	_, err := r.collection.InsertMany(ctx, docs)
+	return err
+}
+
+func recipientFilter(recipientType string, recipientID bson.ObjectID) bson.M {
+	return bson.M{"recipient_type": recipientType, "recipient_id": recipientID}
+}
+
+// List retorna as notificações do destinatário, mais recentes primeiro. before pagina pelo ID
+// da última notificação da página anterior (zero para a primeira página).
+func (r *NotificationsRepository) List(ctx context.Context, recipientType string, recipientID bson.ObjectID, unreadOnly bool, before bson.ObjectID, limit int64) ([]*models.Notification, error) {
+	filter := recipientFilter(recipientType, recipientID)
+	if unreadOnly {
+		filter["read_at"] = nil
+	}
+	if !before.IsZero() {
+		filter["_id"] = bson.M{"$lt": before}
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: -1}}).SetLimit(limit)
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var notifications []*models.Notification
+	if err = cursor.All(ctx, &notifications); err != nil {
+		return nil, err
+	}
+
+	return notifications, nil
+}
+
+// CountUnread conta as notificações não lidas do destinatário
+func (r *NotificationsRepository) CountUnread(ctx context.Context, recipientType string, recipientID bson.ObjectID) (int64, error) {
+	filter := recipientFilter(recipientType, recipientID)
+	filter["read_at"] = nil
+	return r.collection.CountDocuments(ctx, filter)
+}
+
+// MarkRead marca uma notificação do destinatário como lida. Retorna false se ela não existe
+// ou pertence a outro usuário; marcar de novo uma notificação já lida não é erro.
+func (r *NotificationsRepository) MarkRead(ctx context.Context, id bson.ObjectID, recipientType string, recipientID bson.ObjectID) (bool, error) {
+	filter := recipientFilter(recipientType, recipientID)
+	filter["_id"] = id
+
+	result, err := r.collection.UpdateOne(ctx, filter, []bson.M{
+		{"$set": bson.M{"read_at": bson.M{"$ifNull": bson.A{"$read_at", time.Now()}}}},
+	})
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
+
+// MarkAllRead marca todas as notificações não lidas do destinatário como lidas
+func (r *NotificationsRepository) MarkAllRead(ctx context.Context, recipientType string, recipientID bson.ObjectID) (int64, error) {
+	filter := recipientFilter(recipientType, recipientID)
+	filter["read_at"] = nil
+
+	result, err := r.collection.UpdateMany(ctx, filter, bson.M{"$set": bson.M{"read_at": time.Now()}})
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
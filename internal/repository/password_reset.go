@@ -3,12 +3,17 @@ package repository
 import (
 	"context"
 	"empregabemapi/internal/models"
+	"errors"
 	"time"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
 
+// ErrResetTokenUsed indica que o token já foi usado, invalidado ou expirou
+var ErrResetTokenUsed = errors.New("token de reset já utilizado ou expirado")
+
 type PasswordResetRepository struct {
 	collection *mongo.Collection
 }
@@ -19,6 +24,23 @@ func NewPasswordResetRepository(db *mongo.Database) *PasswordResetRepository {
 	}
 }
 
+// EnsureIndexes cria o índice TTL em expires_at (o MongoDB remove tokens expirados
+// automaticamente) e o índice único em token_hash
+func (r *PasswordResetRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+		{
+			Keys:    bson.D{{Key: "token_hash", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "email", Value: 1}, {Key: "user_type", Value: 1}}},
+	})
+	return err
+}
+
 // Create salva um novo token de reset
 func (r *PasswordResetRepository) Create(ctx context.Context, reset *models.PasswordReset) error {
 	reset.ID = bson.NewObjectID()
@@ -45,12 +67,17 @@ func (r *PasswordResetRepository) GetByToken(ctx context.Context, tokenHash stri
 	return &reset, nil
 }
 
-// MarkAsUsed marca um token como usado
+// MarkAsUsed marca um token como usado de forma atômica.
+// Só um chamador consegue marcar o mesmo token: os demais recebem ErrResetTokenUsed.
 func (r *PasswordResetRepository) MarkAsUsed(ctx context.Context, id bson.ObjectID) error {
 	now := time.Now()
-	_, err := r.collection.UpdateOne(
+	result, err := r.collection.UpdateOne(
 		ctx,
-		bson.M{"_id": id},
+		bson.M{
+			"_id":        id,
+			"used":       false,
+			"expires_at": bson.M{"$gt": now},
+		},
 		bson.M{
 			"$set": bson.M{
 				"used":    true,
@@ -58,7 +85,13 @@ func (r *PasswordResetRepository) MarkAsUsed(ctx context.Context, id bson.Object
 			},
 		},
 	)
-	return err
+	if err != nil {
+		return err
+	}
+	if result.ModifiedCount == 0 {
+		return ErrResetTokenUsed
+	}
+	return nil
 }
 
 // DeleteExpired remove tokens expirados (limpeza)
@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"empregabemapi/internal/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type SavedSearchesRepository struct {
+	collection *mongo.Collection
+}
+
+func NewSavedSearchesRepository(db *mongo.Database) *SavedSearchesRepository {
+	return &SavedSearchesRepository{
+		collection: db.Collection("saved_searches"),
+	}
+}
+
+// EnsureIndexes cria os índices das buscas do candidato e dos resumos agendados
+func (r *SavedSearchesRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "candidate_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "alerts_enabled", Value: 1}, {Key: "next_digest_at", Value: 1}}},
+	})
+	return err
+}
+
+func (r *SavedSearchesRepository) Create(ctx context.Context, search *models.SavedSearch) error {
+	now := time.Now()
+	search.ID = bson.NewObjectID()
+	search.CreatedAt = now
+	search.UpdatedAt = now
+
+	_, err := r.collection.InsertOne(ctx, search)
+	return err
+}
+
+// GetByID busca a busca salva (mongo.ErrNoDocuments se não existir)
+func (r *SavedSearchesRepository) GetByID(ctx context.Context, id bson.ObjectID) (*models.SavedSearch, error) {
+	var search models.SavedSearch
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&search)
+	if err != nil {
+		return nil, err
+	}
+	return &search, nil
+}
+
+// ListByCandidate retorna as buscas salvas do candidato, mais recentes primeiro
+func (r *SavedSearchesRepository) ListByCandidate(ctx context.Context, candidateID bson.ObjectID) ([]*models.SavedSearch, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	return r.find(ctx, bson.M{"candidate_id": candidateID}, opts)
+}
+
+// CountByCandidate conta as buscas salvas do candidato
+func (r *SavedSearchesRepository) CountByCandidate(ctx context.Context, candidateID bson.ObjectID) (int64, error) {
+	return r.collection.CountDocuments(ctx, bson.M{"candidate_id": candidateID})
+}
+
+// Update grava a busca alterada pelo candidato
+func (r *SavedSearchesRepository) Update(ctx context.Context, search *models.SavedSearch) error {
+	search.UpdatedAt = time.Now()
+
+	_, err := r.collection.ReplaceOne(ctx, bson.M{"_id": search.ID, "candidate_id": search.CandidateID}, search)
+	return err
+}
+
+// Delete remove a busca do candidato. Retorna false se ela não existir.
+func (r *SavedSearchesRepository) Delete(ctx context.Context, id, candidateID bson.ObjectID) (bool, error) {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id, "candidate_id": candidateID})
+	if err != nil {
+		return false, err
+	}
+	return result.DeletedCount > 0, nil
+}
+
+// DisableAlerts desliga os alertas da busca (descadastro pelo link do email) e descarta as
+// vagas pendentes
+func (r *SavedSearchesRepository) DisableAlerts(ctx context.Context, id bson.ObjectID) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{
+		"$set":   bson.M{"alerts_enabled": false, "updated_at": time.Now()},
+		"$unset": bson.M{"pending_job_ids": "", "next_digest_at": ""},
+	})
+	return err
+}
+
+// ListWithAlerts retorna todas as buscas com alertas ligados
+func (r *SavedSearchesRepository) ListWithAlerts(ctx context.Context) ([]*models.SavedSearch, error) {
+	return r.find(ctx, bson.M{"alerts_enabled": true}, options.Find())
+}
+
+// AddPending guarda vagas encontradas para o próximo resumo (mantém as models.MaxPendingAlerts mais recentes)
+func (r *SavedSearchesRepository) AddPending(ctx context.Context, id bson.ObjectID, jobIDs []bson.ObjectID) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id, "alerts_enabled": true}, bson.M{
+		"$push": bson.M{"pending_job_ids": bson.M{"$each": jobIDs, "$slice": -models.MaxPendingAlerts}},
+	})
+	return err
+}
+
+// MarkAlerted registra o envio de um alerta
+func (r *SavedSearchesRepository) MarkAlerted(ctx context.Context, id bson.ObjectID) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{
+		"$set": bson.M{"last_alert_at": time.Now()},
+	})
+	return err
+}
+
+// ListDueDigests retorna as buscas com resumo diário/semanal vencido
+func (r *SavedSearchesRepository) ListDueDigests(ctx context.Context, now time.Time) ([]*models.SavedSearch, error) {
+	return r.find(ctx, bson.M{"alerts_enabled": true, "next_digest_at": bson.M{"$lte": now}}, options.Find())
+}
+
+// ClaimDigest agenda o próximo resumo e esvazia as vagas pendentes, retornando as que estavam
+// guardadas. Retorna nil se não havia vagas ou se outra instância da API já pegou este resumo.
+func (r *SavedSearchesRepository) ClaimDigest(ctx context.Context, search *models.SavedSearch, next *time.Time) ([]bson.ObjectID, error) {
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.Before)
+	var previous models.SavedSearch
+	err := r.collection.FindOneAndUpdate(ctx,
+		bson.M{"_id": search.ID, "alerts_enabled": true, "next_digest_at": search.NextDigestAt},
+		bson.M{
+			"$set":   bson.M{"next_digest_at": next},
+			"$unset": bson.M{"pending_job_ids": ""},
+		},
+		opts,
+	).Decode(&previous)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return previous.PendingJobIDs, nil
+}
+
+func (r *SavedSearchesRepository) find(ctx context.Context, filter bson.M, opts *options.FindOptionsBuilder) ([]*models.SavedSearch, error) {
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var searches []*models.SavedSearch
+	if err = cursor.All(ctx, &searches); err != nil {
+		return nil, err
+	}
+
+	return searches, nil
+}
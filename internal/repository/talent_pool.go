@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"context"
+	"empregabemapi/internal/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// Histórico de buscas e visualizações mais antigo que isso é removido pelo MongoDB
+const talentEventRetention = 180 * 24 * time.Hour
+
+// TalentPoolRepository guarda os pedidos de contato e o histórico de buscas e visualizações do
+// banco de talentos
+type TalentPoolRepository struct {
+	contacts *mongo.Collection
+	events   *mongo.Collection
+}
+
+func NewTalentPoolRepository(db *mongo.Database) *TalentPoolRepository {
+	return &TalentPoolRepository{
+		contacts: db.Collection("contact_requests"),
+		events:   db.Collection("talent_events"),
+	}
+}
+
+// EnsureIndexes cria os índices dos pedidos por empresa e candidato e do histórico (com TTL)
+func (r *TalentPoolRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.contacts.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "candidate_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "company_id", Value: 1}, {Key: "candidate_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = r.events.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "candidate_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		{
+			Keys:    bson.D{{Key: "created_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32(talentEventRetention.Seconds())),
+		},
+	})
+	return err
+}
+
+func (r *TalentPoolRepository) CreateContactRequest(ctx context.Context, request *models.ContactRequest) error {
+	request.ID = bson.NewObjectID()
+	request.Status = models.ContactRequestPending
+	request.RespondedAt = nil
+	request.CreatedAt = time.Now()
+
+	_, err := r.contacts.InsertOne(ctx, request)
+	return err
+}
+
+// GetContactRequest busca o pedido (mongo.ErrNoDocuments se não existir)
+func (r *TalentPoolRepository) GetContactRequest(ctx context.Context, id bson.ObjectID) (*models.ContactRequest, error) {
+	var request models.ContactRequest
+	err := r.contacts.FindOne(ctx, bson.M{"_id": id}).Decode(&request)
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// LatestContactRequest retorna o pedido mais recente da empresa ao candidato (nil se não houver)
+func (r *TalentPoolRepository) LatestContactRequest(ctx context.Context, companyID, candidateID bson.ObjectID) (*models.ContactRequest, error) {
+	opts := options.FindOne().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	var request models.ContactRequest
+	err := r.contacts.FindOne(ctx, bson.M{"company_id": companyID, "candidate_id": candidateID}, opts).Decode(&request)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// AcceptedCandidates retorna, entre os candidatos informados, os que aceitaram contato da empresa
+func (r *TalentPoolRepository) AcceptedCandidates(ctx context.Context, companyID bson.ObjectID, candidateIDs []bson.ObjectID) (map[bson.ObjectID]bool, error) {
+	accepted := map[bson.ObjectID]bool{}
+	if len(candidateIDs) == 0 {
+		return accepted, nil
+	}
+
+	requests, err := r.findContacts(ctx, bson.M{
+		"company_id":   companyID,
+		"candidate_id": bson.M{"$in": candidateIDs},
+		"status":       models.ContactRequestAccepted,
+	}, options.Find())
+	if err != nil {
+		return nil, err
+	}
+	for _, request := range requests {
+		accepted[request.CandidateID] = true
+	}
+	return accepted, nil
+}
+
+// ListContactRequestsByCandidate retorna os pedidos recebidos pelo candidato, mais recentes primeiro
+// (status vazio = todos)
+func (r *TalentPoolRepository) ListContactRequestsByCandidate(ctx context.Context, candidateID bson.ObjectID, status string) ([]*models.ContactRequest, error) {
+	filter := bson.M{"candidate_id": candidateID}
+	if status != "" {
+		filter["status"] = status
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	return r.findContacts(ctx, filter, opts)
+}
+
+// ListContactRequestsByCompany retorna os pedidos enviados pela empresa, mais recentes primeiro
+// (status vazio = todos)
+func (r *TalentPoolRepository) ListContactRequestsByCompany(ctx context.Context, companyID bson.ObjectID, status string) ([]*models.ContactRequest, error) {
+	filter := bson.M{"company_id": companyID}
+	if status != "" {
+		filter["status"] = status
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	return r.findContacts(ctx, filter, opts)
+}
+
+// RespondContactRequest registra a resposta do candidato a um pedido pendente.
+// Retorna false se o pedido não existe, é de outro candidato ou já foi respondido.
+func (r *TalentPoolRepository) RespondContactRequest(ctx context.Context, id, candidateID bson.ObjectID, status string) (bool, error) {
+	result, err := r.contacts.UpdateOne(ctx,
+		bson.M{"_id": id, "candidate_id": candidateID, "status": models.ContactRequestPending},
+		bson.M{"$set": bson.M{"status": status, "responded_at": time.Now()}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// LogEvents grava no histórico dos candidatos as buscas e visualizações de uma empresa
+func (r *TalentPoolRepository) LogEvents(ctx context.Context, events []*models.TalentEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	docs := make([]interface{}, 0, len(events))
+	for _, event := range events {
+		event.ID = bson.NewObjectID()
+		event.CreatedAt = now
+		docs = append(docs, event)
+	}
+
+	_, err := r.events.InsertMany(ctx, docs)
+	return err
+}
+
+// ListEvents retorna o histórico do candidato, mais recente primeiro
+func (r *TalentPoolRepository) ListEvents(ctx context.Context, candidateID bson.ObjectID, limit int64) ([]*models.TalentEvent, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(limit)
+	cursor, err := r.events.Find(ctx, bson.M{"candidate_id": candidateID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var events []*models.TalentEvent
+	if err = cursor.All(ctx, &events); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func (r *TalentPoolRepository) findContacts(ctx context.Context, filter bson.M, opts *options.FindOptionsBuilder) ([]*models.ContactRequest, error) {
+	cursor, err := r.contacts.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var requests []*models.ContactRequest
+	if err = cursor.All(ctx, &requests); err != nil {
+		return nil, err
+	}
+
+	return requests, nil
+}
@@ -0,0 +1,300 @@
+package repository
+
+import (
+	"context"
+	"empregabemapi/internal/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const (
+	// Tempo que um worker pode manter uma entrega "em andamento" antes de outro reaproveitá-la
+	webhookLockDuration = 2 * time.Minute
+	// Histórico de entregas mantido para consulta e reenvio
+	webhookDeliveryRetention = 30 * 24 * time.Hour
+)
+
+type WebhooksRepository struct {
+	endpoints  *mongo.Collection
+	deliveries *mongo.Collection
+}
+
+func NewWebhooksRepository(db *mongo.Database) *WebhooksRepository {
+	return &WebhooksRepository{
+		endpoints:  db.Collection("webhook_endpoints"),
+		deliveries: db.Collection("webhook_deliveries"),
+	}
+}
+
+// EnsureIndexes cria os índices dos endpoints por empresa, da fila de entregas do worker,
+// do histórico por endpoint e o TTL do histórico
+func (r *WebhooksRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.endpoints.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "company_id", Value: 1}, {Key: "events", Value: 1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = r.deliveries.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}}},
+		{Keys: bson.D{{Key: "endpoint_id", Value: 1}, {Key: "_id", Value: -1}}},
+		{
+			Keys:    bson.D{{Key: "created_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32(webhookDeliveryRetention.Seconds())),
+		},
+	})
+	return err
+}
+
+// CreateEndpoint grava um endpoint novo
+func (r *WebhooksRepository) CreateEndpoint(ctx context.Context, endpoint *models.WebhookEndpoint) error {
+	now := time.Now()
+	endpoint.ID = bson.NewObjectID()
+	endpoint.CreatedAt = now
+	endpoint.UpdatedAt = now
+
+	_, err := r.endpoints.InsertOne(ctx, endpoint)
+	return err
+}
+
+// GetEndpoint busca um endpoint da empresa (mongo.ErrNoDocuments se for de outra empresa)
+func (r *WebhooksRepository) GetEndpoint(ctx context.Context, id, companyID bson.ObjectID) (*models.WebhookEndpoint, error) {
+	var endpoint models.WebhookEndpoint
+	err := r.endpoints.FindOne(ctx, bson.M{"_id": id, "company_id": companyID}).Decode(&endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return &endpoint, nil
+}
+
+// GetEndpointByID busca um endpoint sem restringir a empresa (uso do worker)
+func (r *WebhooksRepository) GetEndpointByID(ctx context.Context, id bson.ObjectID) (*models.WebhookEndpoint, error) {
+	var endpoint models.WebhookEndpoint
+	err := r.endpoints.FindOne(ctx, bson.M{"_id": id}).Decode(&endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return &endpoint, nil
+}
+
+// ListEndpoints retorna os endpoints da empresa
+func (r *WebhooksRepository) ListEndpoints(ctx context.Context, companyID bson.ObjectID) ([]*models.WebhookEndpoint, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cursor, err := r.endpoints.Find(ctx, bson.M{"company_id": companyID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var endpoints []*models.WebhookEndpoint
+	if err = cursor.All(ctx, &endpoints); err != nil {
+		return nil, err
+	}
+
+	return endpoints, nil
+}
+
+// CountEndpoints conta os endpoints cadastrados pela empresa
+func (r *WebhooksRepository) CountEndpoints(ctx context.Context, companyID bson.ObjectID) (int64, error) {
+	return r.endpoints.CountDocuments(ctx, bson.M{"company_id": companyID})
+}
+
+// ListSubscribedEndpoints retorna os endpoints ativos da empresa que assinam o evento
+func (r *WebhooksRepository) ListSubscribedEndpoints(ctx context.Context, companyID bson.ObjectID, eventType string) ([]*models.WebhookEndpoint, error) {
+	cursor, err := r.endpoints.Find(ctx, bson.M{"company_id": companyID, "events": eventType, "active": true})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var endpoints []*models.WebhookEndpoint
+	if err = cursor.All(ctx, &endpoints); err != nil {
+		return nil, err
+	}
+
+	return endpoints, nil
+}
+
+// UpdateEndpoint altera URL, descrição, eventos e status do endpoint
+func (r *WebhooksRepository) UpdateEndpoint(ctx context.Context, endpoint *models.WebhookEndpoint) error {
+	endpoint.UpdatedAt = time.Now()
+	_, err := r.endpoints.UpdateOne(ctx, bson.M{"_id": endpoint.ID, "company_id": endpoint.CompanyID}, bson.M{
+		"$set": bson.M{
+			"url":         endpoint.URL,
+			"description": endpoint.Description,
+			"events":      endpoint.Events,
+			"active":      endpoint.Active,
+			"updated_at":  endpoint.UpdatedAt,
+		},
+	})
+	return err
+}
+
+// RotateSecret troca o segredo do endpoint; o anterior continua válido até previousExpiresAt
+func (r *WebhooksRepository) RotateSecret(ctx context.Context, id, companyID bson.ObjectID, secret, previous string, previousExpiresAt time.Time) error {
+	_, err := r.endpoints.UpdateOne(ctx, bson.M{"_id": id, "company_id": companyID}, bson.M{
+		"$set": bson.M{
+			"secret":                     secret,
+			"previous_secret":            previous,
+			"previous_secret_expires_at": previousExpiresAt,
+			"updated_at":                 time.Now(),
+		},
+	})
+	return err
+}
+
+// DeleteEndpoint remove o endpoint e cancela as entregas pendentes
+func (r *WebhooksRepository) DeleteEndpoint(ctx context.Context, id, companyID bson.ObjectID) (bool, error) {
+	result, err := r.endpoints.DeleteOne(ctx, bson.M{"_id": id, "company_id": companyID})
+	if err != nil {
+		return false, err
+	}
+	if result.DeletedCount == 0 {
+		return false, nil
+	}
+
+	_, err = r.deliveries.DeleteMany(ctx, bson.M{"endpoint_id": id})
+	return true, err
+}
+
+// EnqueueDeliveries agenda as entregas para envio imediato pelo worker
+func (r *WebhooksRepository) EnqueueDeliveries(ctx context.Context, deliveries []*models.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	docs := make([]interface{}, 0, len(deliveries))
+	for _, d := range deliveries {
+		d.ID = bson.NewObjectID()
+		d.Status = models.WebhookDeliveryPending
+		d.Attempts = 0
+		d.NextAttemptAt = now
+		d.CreatedAt = now
+		docs = append(docs, d)
+	}
+
+	_, err := r.deliveries.InsertMany(ctx, docs)
+	return err
+}
+
+// GetDelivery busca uma entrega de um endpoint da empresa
+func (r *WebhooksRepository) GetDelivery(ctx context.Context, id, endpointID, companyID bson.ObjectID) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	err := r.deliveries.FindOne(ctx, bson.M{"_id": id, "endpoint_id": endpointID, "company_id": companyID}).Decode(&delivery)
+	if err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// ListDeliveries retorna o histórico de entregas do endpoint, mais recentes primeiro
+func (r *WebhooksRepository) ListDeliveries(ctx context.Context, endpointID bson.ObjectID, status string, limit int64) ([]*models.WebhookDelivery, error) {
+	filter := bson.M{"endpoint_id": endpointID}
+	if status != "" {
+		filter["status"] = status
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: -1}}).SetLimit(limit)
+	cursor, err := r.deliveries.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var deliveries []*models.WebhookDelivery
+	if err = cursor.All(ctx, &deliveries); err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// ClaimNextDelivery reserva a próxima entrega pronta para envio.
+// Entregas "delivering" com lock expirado (worker caiu no meio do envio) também são reaproveitadas.
+// Retorna mongo.ErrNoDocuments quando não há nada para enviar.
+func (r *WebhooksRepository) ClaimNextDelivery(ctx context.Context) (*models.WebhookDelivery, error) {
+	now := time.Now()
+	filter := bson.M{
+		"$or": []bson.M{
+			{"status": models.WebhookDeliveryPending, "next_attempt_at": bson.M{"$lte": now}},
+			{"status": models.WebhookDeliveryDelivering, "locked_until": bson.M{"$lt": now}},
+		},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"status":       models.WebhookDeliveryDelivering,
+			"locked_until": now.Add(webhookLockDuration),
+		},
+		"$inc": bson.M{"attempts": 1},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var delivery models.WebhookDelivery
+	if err := r.deliveries.FindOneAndUpdate(ctx, filter, update, opts).Decode(&delivery); err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// WebhookAttempt é o resultado de uma tentativa de entrega
+type WebhookAttempt struct {
+	StatusCode int
+	Error      string
+	Response   string
+	Duration   time.Duration
+}
+
+func attemptFields(attempt WebhookAttempt) bson.M {
+	return bson.M{
+		"last_status_code": attempt.StatusCode,
+		"last_error":       attempt.Error,
+		"last_response":    attempt.Response,
+		"last_duration_ms": attempt.Duration.Milliseconds(),
+		"last_attempt_at":  time.Now(),
+	}
+}
+
+// MarkDelivered registra a entrega bem-sucedida
+func (r *WebhooksRepository) MarkDelivered(ctx context.Context, id bson.ObjectID, attempt WebhookAttempt) error {
+	set := attemptFields(attempt)
+	set["status"] = models.WebhookDeliveryDelivered
+	set["delivered_at"] = time.Now()
+
+	_, err := r.deliveries.UpdateOne(ctx, bson.M{"_id": id}, bson.M{
+		"$set":   set,
+		"$unset": bson.M{"locked_until": ""},
+	})
+	return err
+}
+
+// MarkRetry devolve a entrega para a fila com uma nova tentativa agendada
+func (r *WebhooksRepository) MarkRetry(ctx context.Context, id bson.ObjectID, attempt WebhookAttempt, nextAttempt time.Time) error {
+	set := attemptFields(attempt)
+	set["status"] = models.WebhookDeliveryPending
+	set["next_attempt_at"] = nextAttempt
+
+	_, err := r.deliveries.UpdateOne(ctx, bson.M{"_id": id}, bson.M{
+		"$set":   set,
+		"$unset": bson.M{"locked_until": ""},
+	})
+	return err
+}
+
+// MarkFailed marca a entrega como falha definitiva (esgotou as tentativas ou o endpoint sumiu)
+func (r *WebhooksRepository) MarkFailed(ctx context.Context, id bson.ObjectID, attempt WebhookAttempt) error {
+	set := attemptFields(attempt)
+	set["status"] = models.WebhookDeliveryFailed
+
+	_, err := r.deliveries.UpdateOne(ctx, bson.M{"_id": id}, bson.M{
+		"$set":   set,
+		"$unset": bson.M{"locked_until": ""},
+	})
+	return err
+}
@@ -0,0 +1,50 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNotFound   = errors.New("arquivo não encontrado")
+	ErrInvalidKey = errors.New("chave de arquivo inválida")
+)
+
+// Store guarda arquivos binários (imagens enviadas) identificados por uma chave no formato de caminho
+// ("companies/{id}/logo/{versao}/256.png"). O backend de filesystem atende uma instância;
+// um backend S3/GCS pode implementar a mesma interface.
+type Store interface {
+	Put(ctx context.Context, key string, r io.Reader, contentType string) error
+	Open(ctx context.Context, key string) (*Object, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// Object é um arquivo aberto para leitura. Quem chama Open deve fechar.
+type Object struct {
+	io.ReadSeekCloser
+	Size        int64
+	ContentType string
+	ModTime     time.Time
+}
+
+// ValidKey aceita apenas caminhos relativos sem "..", barras duplas ou caracteres fora de [a-z0-9._-/]
+func ValidKey(key string) bool {
+	if key == "" || len(key) > 256 || strings.HasPrefix(key, "/") || strings.HasSuffix(key, "/") {
+		return false
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return false
+		}
+	}
+	for _, c := range key {
+		if !(c >= 'a' && c <= 'z') && !(c >= 'A' && c <= 'Z') && !(c >= '0' && c <= '9') &&
+			c != '.' && c != '_' && c != '-' && c != '/' {
+			return false
+		}
+	}
+	return true
+}
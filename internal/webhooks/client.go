@@ -0,0 +1,90 @@
+package webhooks
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+var errPrivateAddress = errors.New("endereço de rede interno não permitido")
+
+// Faixa de CGNAT (RFC 6598), não coberta por net.IP.IsPrivate
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// publicIP indica se o IP é roteável na internet (bloqueia loopback, redes privadas,
+// link-local, que inclui o metadata de cloud 169.254.169.254, e afins)
+func publicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() || sharedAddressSpace.Contains(ip))
+}
+
+// NewHTTPClient cria o cliente das entregas. Sem allowPrivate, conexões para IPs internos são
+// recusadas no momento da conexão (vale também para DNS que resolve para rede interna).
+// Redirecionamentos não são seguidos: 3xx conta como falha.
+func NewHTTPClient(allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: 5 * time.Second}
+	if !allowPrivate {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !publicIP(ip) {
+				return errPrivateAddress
+			}
+			return nil
+		}
+	}
+
+	return &http.Client{
+		Timeout: 15 * time.Second,
+		Transport: &http.Transport{
+			DialContext:           dialer.DialContext,
+			TLSHandshakeTimeout:   5 * time.Second,
+			ResponseHeaderTimeout: 10 * time.Second,
+			MaxIdleConnsPerHost:   2,
+			IdleConnTimeout:       90 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// ValidateURL confere a URL de um endpoint no cadastro. Em produção (allowPrivate false) exige
+// HTTPS e recusa hosts internos; em desenvolvimento aceita http://localhost para testes locais.
+func ValidateURL(raw string, allowPrivate bool) error {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return errors.New("URL inválida")
+	}
+	if u.User != nil {
+		return errors.New("A URL não pode conter usuário e senha")
+	}
+	if len(raw) > 2048 {
+		return errors.New("URL muito longa")
+	}
+
+	if allowPrivate {
+		if u.Scheme != "https" && u.Scheme != "http" {
+			return errors.New("A URL deve usar http ou https")
+		}
+		return nil
+	}
+
+	if u.Scheme != "https" {
+		return errors.New("A URL deve usar https")
+	}
+	host := strings.ToLower(u.Hostname())
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") || strings.HasSuffix(host, ".internal") {
+		return errors.New("A URL deve apontar para um endereço público")
+	}
+	if ip := net.ParseIP(host); ip != nil && !publicIP(ip) {
+		return errors.New("A URL deve apontar para um endereço público")
+	}
+	return nil
+}
@@ -0,0 +1,251 @@
+package webhooks
+
+import (
+	"context"
+	"empregabemapi/applications"
+	"empregabemapi/candidates"
+	"empregabemapi/internal/models"
+	"empregabemapi/internal/repository"
+	"empregabemapi/jobs"
+	"encoding/json"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Motivos de encerramento enviados em job.closed
+const (
+	CloseReasonDeactivated = "deactivated"
+	CloseReasonExpired     = "expired"
+	CloseReasonDeleted     = "deleted"
+	CloseReasonTakenDown   = "taken_down"
+	CloseReasonUnderReview = "under_review" // retida ou rejeitada pela moderação
+)
+
+// Envelope é o corpo JSON de toda entrega
+type Envelope struct {
+	ID        string      `json:"id"` // igual em reenvios: use para deduplicar
+	Type      string      `json:"type"`
+	CreatedAt time.Time   `json:"created_at"`
+	CompanyID string      `json:"company_id"`
+	Data      interface{} `json:"data"`
+}
+
+// Resumos enviados nos payloads (sem campos internos de moderação, senhas etc.)
+type jobPayload struct {
+	ID        string     `json:"id"`
+	Title     string     `json:"title"`
+	Location  string     `json:"location"`
+	JobType   string     `json:"job_type,omitempty"`
+	Level     string     `json:"level,omitempty"`
+	Salary    float64    `json:"salary,omitempty"`
+	IsActive  bool       `json:"is_active"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type applicationPayload struct {
+	ID          string    `json:"id"`
+	JobID       string    `json:"job_id"`
+	CandidateID string    `json:"candidate_id"`
+	Status      string    `json:"status"`
+	Message     string    `json:"message,omitempty"`
+	AppliedAt   time.Time `json:"applied_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type candidatePayload struct {
+	ID        string   `json:"id"`
+	Name      string   `json:"name"`
+	Email     string   `json:"email"`
+	Phone     string   `json:"phone,omitempty"`
+	Location  string   `json:"location,omitempty"`
+	Skills    []string `json:"skills,omitempty"`
+	Resume    string   `json:"resume,omitempty"`
+	LinkedIn  string   `json:"linkedin,omitempty"`
+	GitHub    string   `json:"github,omitempty"`
+	Portfolio string   `json:"portfolio,omitempty"`
+}
+
+func newJobPayload(job *jobs.Job) jobPayload {
+	return jobPayload{
+		ID:        job.ID.Hex(),
+		Title:     job.Title,
+		Location:  job.Location,
+		JobType:   job.JobType,
+		Level:     job.Level,
+		Salary:    job.Salary,
+		IsActive:  job.IsActive,
+		ExpiresAt: job.ExpiresAt,
+		CreatedAt: job.CreatedAt,
+	}
+}
+
+func newApplicationPayload(app *applications.Application) applicationPayload {
+	return applicationPayload{
+		ID:          app.ID.Hex(),
+		JobID:       app.JobID.Hex(),
+		CandidateID: app.CandidateID.Hex(),
+		Status:      app.Status,
+		Message:     app.Message,
+		AppliedAt:   app.AppliedAt,
+		UpdatedAt:   app.UpdatedAt,
+	}
+}
+
+// Dispatcher transforma eventos da plataforma em entregas para os endpoints que os assinam.
+// O envio é feito pelo Worker; aqui só se grava a fila. Como nas notificações, falhas são
+// registradas em log e nunca interrompem a ação que gerou o evento.
+type Dispatcher struct {
+	repo         *repository.WebhooksRepository
+	allowPrivate bool // aceita URLs http e de rede interna (desenvolvimento)
+}
+
+func NewDispatcher(repo *repository.WebhooksRepository, allowPrivate bool) *Dispatcher {
+	return &Dispatcher{
+		repo:         repo,
+		allowPrivate: allowPrivate,
+	}
+}
+
+// ValidateURL confere a URL de um endpoint com a mesma política usada no envio
+func (d *Dispatcher) ValidateURL(raw string) error {
+	return ValidateURL(raw, d.allowPrivate)
+}
+
+// dispatch enfileira o evento para todos os endpoints ativos da empresa que o assinam
+func (d *Dispatcher) dispatch(ctx context.Context, companyID bson.ObjectID, eventType string, data interface{}) {
+	endpoints, err := d.repo.ListSubscribedEndpoints(ctx, companyID, eventType)
+	if err != nil {
+		log.Printf("[webhooks] erro ao buscar endpoints de %s para %s: %v", companyID.Hex(), eventType, err)
+		return
+	}
+	if len(endpoints) == 0 {
+		return
+	}
+
+	eventID, payload, err := buildPayload(companyID, eventType, data)
+	if err != nil {
+		log.Printf("[webhooks] erro ao montar payload de %s: %v", eventType, err)
+		return
+	}
+
+	deliveries := make([]*models.WebhookDelivery, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		deliveries = append(deliveries, &models.WebhookDelivery{
+			EndpointID: endpoint.ID,
+			CompanyID:  companyID,
+			EventID:    eventID,
+			EventType:  eventType,
+			Payload:    payload,
+		})
+	}
+
+	if err := d.repo.EnqueueDeliveries(ctx, deliveries); err != nil {
+		log.Printf("[webhooks] erro ao enfileirar %s para %s: %v", eventType, companyID.Hex(), err)
+	}
+}
+
+func buildPayload(companyID bson.ObjectID, eventType string, data interface{}) (string, string, error) {
+	envelope := Envelope{
+		ID:        "evt_" + bson.NewObjectID().Hex(),
+		Type:      eventType,
+		CreatedAt: time.Now().UTC(),
+		CompanyID: companyID.Hex(),
+		Data:      data,
+	}
+	body, err := json.Marshal(envelope)
+	if err != nil {
+		return "", "", err
+	}
+	return envelope.ID, string(body), nil
+}
+
+// ApplicationCreated envia application.created com a candidatura, a vaga e os dados do candidato
+func (d *Dispatcher) ApplicationCreated(ctx context.Context, job *jobs.Job, app *applications.Application, candidate *candidates.Candidate) {
+	data := map[string]interface{}{
+		"application": newApplicationPayload(app),
+		"job":         newJobPayload(job),
+	}
+	if candidate != nil {
+		data["candidate"] = candidatePayload{
+			ID:        candidate.ID.Hex(),
+			Name:      candidate.Name,
+			Email:     candidate.Email,
+			Phone:     candidate.Phone,
+			Location:  candidate.Location,
+			Skills:    candidate.Skills,
+			Resume:    candidate.Resume,
+			LinkedIn:  candidate.LinkedIn,
+			GitHub:    candidate.GitHub,
+			Portfolio: candidate.Portfolio,
+		}
+	}
+	d.dispatch(ctx, job.CompanyID, models.WebhookEventApplicationCreated, data)
+}
+
+// ApplicationStatusChanged envia application.status_changed com o status anterior
+func (d *Dispatcher) ApplicationStatusChanged(ctx context.Context, job *jobs.Job, app *applications.Application, previousStatus string) {
+	d.dispatch(ctx, app.CompanyID, models.WebhookEventApplicationStatusChanged, map[string]interface{}{
+		"application":     newApplicationPayload(app),
+		"job":             newJobPayload(job),
+		"previous_status": previousStatus,
+	})
+}
+
+// JobPublished envia job.published quando a vaga fica visível publicamente
+func (d *Dispatcher) JobPublished(ctx context.Context, job *jobs.Job) {
+	d.dispatch(ctx, job.CompanyID, models.WebhookEventJobPublished, map[string]interface{}{
+		"job": newJobPayload(job),
+	})
+}
+
+// JobClosed envia job.closed quando a vaga deixa de receber candidaturas (reason: CloseReason*)
+func (d *Dispatcher) JobClosed(ctx context.Context, job *jobs.Job, reason string) {
+	d.dispatch(ctx, job.CompanyID, models.WebhookEventJobClosed, map[string]interface{}{
+		"job":    newJobPayload(job),
+		"reason": reason,
+	})
+}
+
+// Ping enfileira um evento de teste para um único endpoint, mesmo que ele esteja inativo
+// ou não assine nenhum evento
+func (d *Dispatcher) Ping(ctx context.Context, endpoint *models.WebhookEndpoint) (*models.WebhookDelivery, error) {
+	eventID, payload, err := buildPayload(endpoint.CompanyID, models.WebhookEventPing, map[string]interface{}{
+		"endpoint_id": endpoint.ID.Hex(),
+		"mensagem":    "Entrega de teste do EmpregaBem",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	delivery := &models.WebhookDelivery{
+		EndpointID: endpoint.ID,
+		CompanyID:  endpoint.CompanyID,
+		EventID:    eventID,
+		EventType:  models.WebhookEventPing,
+		Payload:    payload,
+	}
+	if err := d.repo.EnqueueDeliveries(ctx, []*models.WebhookDelivery{delivery}); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// Redeliver enfileira de novo uma entrega anterior, com o mesmo evento e payload
+func (d *Dispatcher) Redeliver(ctx context.Context, original *models.WebhookDelivery) (*models.WebhookDelivery, error) {
+	originalID := original.ID
+	delivery := &models.WebhookDelivery{
+		EndpointID:   original.EndpointID,
+		CompanyID:    original.CompanyID,
+		EventID:      original.EventID,
+		EventType:    original.EventType,
+		Payload:      original.Payload,
+		RedeliveryOf: &originalID,
+	}
+	if err := d.repo.EnqueueDeliveries(ctx, []*models.WebhookDelivery{delivery}); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
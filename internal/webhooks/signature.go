@@ -0,0 +1,91 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Headers enviados em toda entrega
+const (
+	HeaderSignature = "X-EmpregaBem-Signature"
+	HeaderEvent     = "X-EmpregaBem-Event"
+	HeaderEventID   = "X-EmpregaBem-Event-Id"
+	HeaderDelivery  = "X-EmpregaBem-Delivery"
+)
+
+// Tolerância padrão de Verify para o timestamp da assinatura (protege contra replay)
+const DefaultTolerance = 5 * time.Minute
+
+var (
+	ErrMissingSignature = errors.New("assinatura ausente ou malformada")
+	ErrExpiredSignature = errors.New("timestamp da assinatura fora da tolerância")
+	ErrInvalidSignature = errors.New("assinatura não confere")
+)
+
+// GenerateSecret cria o segredo de assinatura de um endpoint ("whsec_" + 32 bytes aleatórios)
+func GenerateSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+// Sign calcula HMAC-SHA256(segredo, "{timestamp}.{corpo}") em hexadecimal
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// SignatureHeader monta o valor de X-EmpregaBem-Signature: "t={timestamp},v1={assinatura}".
+// Durante a rotação há um v1 para cada segredo válido; basta um conferir.
+func SignatureHeader(secrets []string, timestamp int64, body []byte) string {
+	parts := []string{"t=" + strconv.FormatInt(timestamp, 10)}
+	for _, secret := range secrets {
+		parts = append(parts, "v1="+Sign(secret, timestamp, body))
+	}
+	return strings.Join(parts, ",")
+}
+
+// Verify confere o header de assinatura de uma entrega recebida (uso dos receptores e do
+// cmd/webhook-receiver)
+func Verify(header string, body []byte, secret string, tolerance time.Duration) error {
+	var timestamp int64
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			timestamp, _ = strconv.ParseInt(value, 10, 64)
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	if timestamp == 0 || len(signatures) == 0 {
+		return ErrMissingSignature
+	}
+
+	if age := time.Since(time.Unix(timestamp, 0)); age > tolerance || age < -tolerance {
+		return ErrExpiredSignature
+	}
+
+	expected := []byte(Sign(secret, timestamp, body))
+	for _, signature := range signatures {
+		if hmac.Equal(expected, []byte(signature)) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
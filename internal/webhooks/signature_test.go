@@ -0,0 +1,84 @@
+package webhooks
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSign(t *testing.T) {
+	// HMAC-SHA256("whsec_teste", "1700000000.{corpo}") calculado fora do Go
+	body := []byte(`{"event":"job.published"}`)
+	want := "f1a38895c6e4f344e995c4dd55587c697153eafbbad00757ae72a6fd69f5f3af"
+
+	if got := Sign("whsec_teste", 1700000000, body); got != want {
+		t.Errorf("Sign = %s, esperado %s", got, want)
+	}
+	if Sign("whsec_outro", 1700000000, body) == want {
+		t.Error("segredos diferentes geraram a mesma assinatura")
+	}
+	if Sign("whsec_teste", 1700000001, body) == want {
+		t.Error("timestamps diferentes geraram a mesma assinatura")
+	}
+}
+
+func TestSignatureHeader(t *testing.T) {
+	body := []byte(`{}`)
+	header := SignatureHeader([]string{"whsec_novo", "whsec_antigo"}, 1700000000, body)
+
+	want := "t=1700000000,v1=" + Sign("whsec_novo", 1700000000, body) + ",v1=" + Sign("whsec_antigo", 1700000000, body)
+	if header != want {
+		t.Errorf("SignatureHeader = %s, esperado %s", header, want)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"event":"application.created","data":{"id":"1"}}`)
+	now := time.Now().Unix()
+	header := SignatureHeader([]string{"whsec_novo", "whsec_antigo"}, now, body)
+	stale := SignatureHeader([]string{"whsec_novo"}, now-int64((10*time.Minute).Seconds()), body)
+	future := SignatureHeader([]string{"whsec_novo"}, now+int64((10*time.Minute).Seconds()), body)
+
+	tests := []struct {
+		name   string
+		header string
+		body   []byte
+		secret string
+		want   error
+	}{
+		{"segredo atual", header, body, "whsec_novo", nil},
+		{"segredo anterior durante a rotação", header, body, "whsec_antigo", nil},
+		{"espaços entre as partes", strings.ReplaceAll(header, ",", ", "), body, "whsec_novo", nil},
+		{"segredo errado", header, body, "whsec_outro", ErrInvalidSignature},
+		{"corpo alterado", header, []byte(`{"event":"application.created","data":{"id":"2"}}`), "whsec_novo", ErrInvalidSignature},
+		{"timestamp antigo (replay)", stale, body, "whsec_novo", ErrExpiredSignature},
+		{"timestamp no futuro", future, body, "whsec_novo", ErrExpiredSignature},
+		{"header vazio", "", body, "whsec_novo", ErrMissingSignature},
+		{"sem assinatura", "t=" + strconv.FormatInt(now, 10), body, "whsec_novo", ErrMissingSignature},
+		{"sem timestamp", "v1=" + Sign("whsec_novo", now, body), body, "whsec_novo", ErrMissingSignature},
+		{"timestamp inválido", "t=abc,v1=" + Sign("whsec_novo", now, body), body, "whsec_novo", ErrMissingSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Verify(tt.header, tt.body, tt.secret, DefaultTolerance); !errors.Is(err, tt.want) {
+				t.Errorf("Verify = %v, esperado %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	a, err := GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := GenerateSecret()
+	if !strings.HasPrefix(a, "whsec_") || len(a) != len("whsec_")+64 {
+		t.Errorf("segredo com formato inesperado: %s", a)
+	}
+	if a == b {
+		t.Error("dois segredos iguais")
+	}
+}
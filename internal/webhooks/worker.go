@@ -0,0 +1,166 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"empregabemapi/internal/models"
+	"empregabemapi/internal/repository"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+const (
+	// Número máximo de tentativas antes de marcar a entrega como falha
+	maxDeliveryAttempts = 10
+	// Backoff inicial entre tentativas (dobra a cada falha)
+	baseRetryDelay = 30 * time.Second
+	// Backoff máximo entre tentativas
+	maxRetryDelay = 6 * time.Hour
+	// Tamanho máximo do corpo da resposta guardado no histórico
+	maxResponseBody = 1024
+)
+
+// Worker drena a fila de entregas enviando os webhooks assinados com retry exponencial
+type Worker struct {
+	repo     *repository.WebhooksRepository
+	client   *http.Client
+	interval time.Duration
+}
+
+func NewWorker(repo *repository.WebhooksRepository, client *http.Client, interval time.Duration) *Worker {
+	return &Worker{
+		repo:     repo,
+		client:   client,
+		interval: interval,
+	}
+}
+
+// Run processa a fila até o contexto ser cancelado
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.drain(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// drain envia todas as entregas prontas no momento
+func (w *Worker) drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		delivery, err := w.repo.ClaimNextDelivery(ctx)
+		if err != nil {
+			if !errors.Is(err, mongo.ErrNoDocuments) {
+				log.Printf("[webhooks] erro ao buscar entregas pendentes: %v", err)
+			}
+			return
+		}
+		w.deliver(ctx, delivery)
+	}
+}
+
+func (w *Worker) deliver(ctx context.Context, delivery *models.WebhookDelivery) {
+	endpoint, err := w.repo.GetEndpointByID(ctx, delivery.EndpointID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			w.repo.MarkFailed(ctx, delivery.ID, repository.WebhookAttempt{Error: "endpoint removido"})
+			return
+		}
+		log.Printf("[webhooks] erro ao buscar endpoint da entrega %s: %v", delivery.ID.Hex(), err)
+		w.repo.MarkRetry(ctx, delivery.ID, repository.WebhookAttempt{Error: "erro interno"}, time.Now().Add(retryDelay(delivery.Attempts)))
+		return
+	}
+	// Entregas de teste vão mesmo para endpoints desativados
+	if !endpoint.Active && delivery.EventType != models.WebhookEventPing {
+		w.repo.MarkFailed(ctx, delivery.ID, repository.WebhookAttempt{Error: "endpoint desativado"})
+		return
+	}
+
+	attempt := w.send(ctx, endpoint, delivery)
+	if attempt.Error == "" {
+		if err := w.repo.MarkDelivered(ctx, delivery.ID, attempt); err != nil {
+			log.Printf("[webhooks] erro ao marcar entrega %s como concluída: %v", delivery.ID.Hex(), err)
+		}
+		return
+	}
+
+	if delivery.Attempts >= maxDeliveryAttempts {
+		log.Printf("[webhooks] entrega %s para %s falhou definitivamente: %s", delivery.ID.Hex(), endpoint.URL, attempt.Error)
+		w.repo.MarkFailed(ctx, delivery.ID, attempt)
+		return
+	}
+
+	next := time.Now().Add(retryDelay(delivery.Attempts))
+	log.Printf("[webhooks] falha na entrega %s (tentativa %d), nova tentativa em %s: %s",
+		delivery.ID.Hex(), delivery.Attempts, next.Format(time.RFC3339), attempt.Error)
+	w.repo.MarkRetry(ctx, delivery.ID, attempt, next)
+}
+
+// send faz o POST assinado; qualquer status fora de 2xx conta como falha
+func (w *Worker) send(ctx context.Context, endpoint *models.WebhookEndpoint, delivery *models.WebhookDelivery) repository.WebhookAttempt {
+	body := []byte(delivery.Payload)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return repository.WebhookAttempt{Error: err.Error()}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "EmpregaBem-Webhooks/1.0")
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderEventID, delivery.EventID)
+	req.Header.Set(HeaderDelivery, delivery.ID.Hex())
+	req.Header.Set(HeaderSignature, SignatureHeader(signingSecrets(endpoint), time.Now().Unix(), body))
+
+	start := time.Now()
+	resp, err := w.client.Do(req)
+	duration := time.Since(start)
+	if err != nil {
+		return repository.WebhookAttempt{Error: err.Error(), Duration: duration}
+	}
+	defer resp.Body.Close()
+
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	attempt := repository.WebhookAttempt{
+		StatusCode: resp.StatusCode,
+		Response:   strings.ToValidUTF8(string(snippet), ""),
+		Duration:   duration,
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		attempt.Error = fmt.Sprintf("resposta HTTP %d", resp.StatusCode)
+	}
+	return attempt
+}
+
+// signingSecrets retorna o segredo atual e, durante a carência da rotação, o anterior
+func signingSecrets(endpoint *models.WebhookEndpoint) []string {
+	secrets := []string{endpoint.Secret}
+	if endpoint.PreviousSecret != "" && endpoint.PreviousSecretExpiresAt != nil && time.Now().Before(*endpoint.PreviousSecretExpiresAt) {
+		secrets = append(secrets, endpoint.PreviousSecret)
+	}
+	return secrets
+}
+
+// retryDelay calcula o backoff exponencial para a tentativa informada (1, 2, 3...)
+func retryDelay(attempt int) time.Duration {
+	delay := baseRetryDelay
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= maxRetryDelay {
+			return maxRetryDelay
+		}
+	}
+	return delay
+}
@@ -26,6 +26,37 @@ type Job struct {
 	// 3 — STATUS DA VAGA
 	IsActive bool `bson:"is_active" json:"is_active"` // vaga ativa ou encerrada
 
+	// Vagas ativas são desativadas automaticamente ao expirar; a empresa é avisada antes
+	ExpiresAt      *time.Time `bson:"expires_at,omitempty" json:"expires_at,omitempty"`
+	ExpiryWarnedAt *time.Time `bson:"expiry_warned_at,omitempty" json:"-"`
+
+	// Quando a vaga no ar foi comparada com as buscas salvas dos candidatos (alertas de vagas)
+	AlertsProcessedAt *time.Time `bson:"alerts_processed_at,omitempty" json:"-"`
+
+	// Selo exibido na vaga: empresa com cadastro verificado (CNPJ conferido ou aprovado por admin)
+	CompanyVerified bool `bson:"company_verified" json:"company_verified"`
+
+	// Vagas semelhantes: setor da empresa (copiado do perfil) e termos do título e dos requisitos,
+	// recalculados a cada Create/Update
+	CompanySector   string   `bson:"company_sector,omitempty" json:"-"`
+	SimilarityTerms []string `bson:"similarity_terms" json:"-"`
+
+	// Remoção pela moderação (admin): a vaga fica inativa e a empresa não pode reativá-la
+	TakenDown      bool       `bson:"taken_down,omitempty" json:"-"`
+	TakedownReason string     `bson:"takedown_reason,omitempty" json:"-"`
+	TakenDownAt    *time.Time `bson:"taken_down_at,omitempty" json:"-"`
+
+	// Moderação: vagas retidas (pending_review) ou rejeitadas não aparecem publicamente
+	ModerationStatus string           `bson:"moderation_status,omitempty" json:"moderation_status,omitempty"` // "approved", "pending_review", "rejected"
+	ModerationFlags  []ModerationFlag `bson:"moderation_flags,omitempty" json:"-"`
+	ModerationReason string           `bson:"moderation_reason,omitempty" json:"-"` // motivo informado pelo admin
+	ModeratedAt      *time.Time       `bson:"moderated_at,omitempty" json:"moderated_at,omitempty"`
+	ContentHash      string           `bson:"content_hash,omitempty" json:"-"`
+
+	// Equipe de contratação: membros da empresa (companies.Member) responsáveis pela vaga.
+	// Recrutadores e visualizadores só veem candidatos das vagas em que estão na equipe.
+	HiringTeam []bson.ObjectID `bson:"hiring_team,omitempty" json:"-"`
+
 	// 4 — METADADOS
 	CreatedAt time.Time `bson:"created_at" json:"created_at"`
 	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
@@ -35,6 +66,58 @@ type Job struct {
 	Applicants int `bson:"applicants" json:"applicants"` // número de candidatos aplicados
 	Priority   int `bson:"priority" json:"priority"`     // 0 normal, 1 destaque
 }
+
+// ManagedJob é a vaga como a própria empresa e os administradores a veem: além dos dados
+// públicos, traz a moderação, a remoção e a equipe de contratação (omitidos nas rotas públicas)
+type ManagedJob struct {
+	*Job
+	ModerationFlags  []ModerationFlag `json:"moderation_flags,omitempty"`
+	ModerationReason string           `json:"moderation_reason,omitempty"`
+	TakenDown        bool             `json:"taken_down,omitempty"`
+	TakedownReason   string           `json:"takedown_reason,omitempty"`
+	TakenDownAt      *time.Time       `json:"taken_down_at,omitempty"`
+	HiringTeam       []bson.ObjectID  `json:"hiring_team,omitempty"`
+}
+
+// Managed retorna a vaga com os dados internos, para as respostas da empresa e do back-office
+func (j *Job) Managed() ManagedJob {
+	return ManagedJob{
+		Job:              j,
+		ModerationFlags:  j.ModerationFlags,
+		ModerationReason: j.ModerationReason,
+		TakenDown:        j.TakenDown,
+		TakedownReason:   j.TakedownReason,
+		TakenDownAt:      j.TakenDownAt,
+		HiringTeam:       j.HiringTeam,
+	}
+}
+
+// ManagedList aplica Managed a uma lista de vagas
+func ManagedList(list []*Job) []ManagedJob {
+	managed := make([]ManagedJob, 0, len(list))
+	for _, job := range list {
+		managed = append(managed, job.Managed())
+	}
+	return managed
+}
+
+// Validade das vagas: padrão ao publicar, máximo aceito e antecedência do aviso de expiração
+const (
+	DefaultJobDuration = 60 * 24 * time.Hour
+	MaxJobDuration     = 180 * 24 * time.Hour
+	ExpiryWarning      = 3 * 24 * time.Hour
+)
+
+// HasTeamMember indica se o membro está na equipe de contratação da vaga
+func (j *Job) HasTeamMember(memberID string) bool {
+	for _, id := range j.HiringTeam {
+		if id.Hex() == memberID {
+			return true
+		}
+	}
+	return false
+}
+
 type JobRepository interface {
 	Create(job *Job) error
 	GetByID(id string) (*Job, error)
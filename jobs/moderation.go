@@ -0,0 +1,125 @@
+package jobs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strings"
+)
+
+// Status de moderação da vaga. Vagas antigas (sem o campo) são consideradas aprovadas.
+const (
+	ModerationApproved      = "approved"
+	ModerationPendingReview = "pending_review"
+	ModerationRejected      = "rejected"
+)
+
+// Regras que mandam uma vaga para a fila de revisão
+const (
+	RuleDiscrimination    = "discrimination"     // idade, gênero, aparência...
+	RuleContactInfo       = "contact_info"       // email/telefone/WhatsApp na descrição
+	RulePaymentRequest    = "payment_request"    // cobrança do candidato (taxa, PIX...)
+	RuleUnrealisticSalary = "unrealistic_salary" // salário fora da realidade (isca)
+	RuleDuplicate         = "duplicate"          // mesmo conteúdo publicado repetidas vezes
+	RuleCompanyUnverified = "company_unverified" // empresa ainda não verificada
+	RuleReported          = "reported"           // denunciada por vários candidatos
+)
+
+// Limites de salário mensal (R$) acima dos quais a vaga é revisada
+const (
+	MaxRealisticSalary       = 100000.0
+	MaxRealisticJuniorSalary = 25000.0
+)
+
+// ModerationFlag é um motivo pelo qual a vaga foi retida para revisão
+type ModerationFlag struct {
+	Rule   string `bson:"rule" json:"rule"`
+	Detail string `bson:"detail" json:"detail"`
+}
+
+type termRule struct {
+	pattern *regexp.Regexp
+	detail  string
+}
+
+// Os padrões são aplicados ao texto normalizado (minúsculo e sem acentos)
+var discriminationRules = []termRule{
+	{regexp.MustCompile(`boa aparencia`), "Exigência de boa aparência"},
+	{regexp.MustCompile(`\b(idade|faixa etaria)\b[^.\n]{0,20}\d{2}`), "Restrição de idade"},
+	{regexp.MustCompile(`\b\d{2} anos de idade\b`), "Restrição de idade"},
+	{regexp.MustCompile(`\bsexo (masculino|feminino)\b`), "Restrição de gênero"},
+	{regexp.MustCompile(`\b(apenas|somente|so) (homens|mulheres|do sexo)\b`), "Restrição de gênero"},
+	{regexp.MustCompile(`\b(solteir[ao]s?|sem filhos)\b`), "Restrição de estado civil ou filhos"},
+}
+
+var contactRules = []termRule{
+	{regexp.MustCompile(`[a-z0-9._%+\-]+@[a-z0-9.\-]+\.[a-z]{2,}`), "Email na descrição"},
+	{regexp.MustCompile(`\(?\b\d{2}\)?\s?9?\d{4}[\s\-.]?\d{4}\b`), "Telefone na descrição"},
+	{regexp.MustCompile(`\b(whatsapp|whats|zap|wpp)\b`), "Contato por WhatsApp na descrição"},
+}
+
+var paymentRules = []termRule{
+	{regexp.MustCompile(`\btaxa (de )?(inscricao|cadastro|adesao|matricula)\b`), "Cobrança de taxa do candidato"},
+	{regexp.MustCompile(`\b(pagar|pague|deposito|depositar)\b[^.]{0,30}\b(taxa|valor|kit|curso|material|uniforme|inscricao)\b`), "Pedido de pagamento ao candidato"},
+	{regexp.MustCompile(`\b(investimento inicial|compra do kit)\b`), "Pedido de pagamento ao candidato"},
+	{regexp.MustCompile(`\b(chave pix|(envie|enviar|pague|pagar|faca)( um| o)? pix)\b`), "Pedido de pagamento via PIX"},
+}
+
+var accentReplacer = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a",
+	"é", "e", "ê", "e", "è", "e",
+	"í", "i", "î", "i",
+	"ó", "o", "ô", "o", "õ", "o", "ö", "o",
+	"ú", "u", "ü", "u",
+	"ç", "c",
+)
+
+// normalizeText deixa o texto minúsculo, sem acentos e com espaços simples
+func normalizeText(s string) string {
+	return strings.Join(strings.Fields(accentReplacer.Replace(strings.ToLower(s))), " ")
+}
+
+// CheckContent aplica as regras de conteúdo (sem consultas ao banco) e retorna os motivos encontrados.
+// Discriminação vale para título, descrição e requisitos; contato e pagamento só para a descrição.
+func CheckContent(job *Job) []ModerationFlag {
+	var flags []ModerationFlag
+
+	all := normalizeText(job.Title + "\n" + job.Description + "\n" + strings.Join(job.Requirements, "\n"))
+	description := normalizeText(job.Description)
+
+	flags = appendMatches(flags, RuleDiscrimination, discriminationRules, all)
+	flags = appendMatches(flags, RuleContactInfo, contactRules, description)
+	flags = appendMatches(flags, RulePaymentRequest, paymentRules, description)
+
+	if job.Salary > MaxRealisticSalary || (strings.EqualFold(job.Level, "junior") && job.Salary > MaxRealisticJuniorSalary) {
+		flags = append(flags, ModerationFlag{Rule: RuleUnrealisticSalary, Detail: "Salário acima do esperado para a vaga"})
+	}
+
+	return flags
+}
+
+// appendMatches adiciona um flag por detalhe distinto encontrado
+func appendMatches(flags []ModerationFlag, rule string, rules []termRule, text string) []ModerationFlag {
+	seen := make(map[string]bool)
+	for _, r := range rules {
+		if !seen[r.detail] && r.pattern.MatchString(text) {
+			seen[r.detail] = true
+			flags = append(flags, ModerationFlag{Rule: rule, Detail: r.detail})
+		}
+	}
+	return flags
+}
+
+// ContentHash identifica vagas com o mesmo título e descrição (para detectar spam duplicado)
+func ContentHash(job *Job) string {
+	sum := sha256.Sum256([]byte(normalizeText(job.Title) + "\n" + normalizeText(job.Description)))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsPublic indica se a vaga pode ser exibida publicamente (não removida e aprovada na moderação)
+func (j *Job) IsPublic() bool {
+	if j.TakenDown {
+		return false
+	}
+	return j.ModerationStatus == "" || j.ModerationStatus == ModerationApproved
+}
@@ -2,10 +2,13 @@ package jobs
 
 import (
 	"context"
+	"regexp"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
 
 type MongoRepository struct {
@@ -18,10 +21,33 @@ func NewMongoRepository(db *mongo.Database) *MongoRepository {
 	}
 }
 
+// EnsureIndexes cria os índices usados pela fila de moderação, pela detecção de duplicadas
+// pela listagem de vagas da empresa, pela expiração automática e pelos alertas de vagas
+func (r *MongoRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "company_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "content_hash", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "moderation_status", Value: 1}, {Key: "created_at", Value: 1}}},
+		{Keys: bson.D{{Key: "is_active", Value: 1}, {Key: "expires_at", Value: 1}}},
+		{Keys: bson.D{{Key: "alerts_processed_at", Value: 1}, {Key: "created_at", Value: 1}}},
+		{Keys: bson.D{{Key: "similarity_terms", Value: 1}}},
+	})
+	return err
+}
+
+// publicFilter exclui vagas removidas pela moderação e as que não foram aprovadas na revisão
+func publicFilter() bson.M {
+	return bson.M{
+		"taken_down":        bson.M{"$ne": true},
+		"moderation_status": bson.M{"$nin": []string{ModerationPendingReview, ModerationRejected}},
+	}
+}
+
 func (r *MongoRepository) Create(ctx context.Context, job *Job) error {
 	job.ID = bson.NewObjectID()
 	job.CreatedAt = time.Now()
 	job.UpdatedAt = time.Now()
+	job.refreshSimilarityTerms()
 
 	// Inicializa contadores em 0 se não foram definidos
 	if job.Views == 0 {
@@ -51,7 +77,7 @@ func (r *MongoRepository) GetByID(ctx context.Context, id string) (*Job, error)
 }
 
 func (r *MongoRepository) List(ctx context.Context) ([]*Job, error) {
-	cursor, err := r.collection.Find(ctx, bson.M{})
+	cursor, err := r.collection.Find(ctx, publicFilter())
 	if err != nil {
 		return nil, err
 	}
@@ -65,15 +91,68 @@ func (r *MongoRepository) List(ctx context.Context) ([]*Job, error) {
 	return jobs, nil
 }
 
+// SearchFilters são os filtros da busca pública (GET /jobs), também gravados nas buscas salvas
+// dos candidatos para os alertas de vagas
 type SearchFilters struct {
-	Location  string
-	JobType   string
-	Level     string
-	MinSalary float64
+	// Texto buscado no título, descrição, empresa e requisitos (case-insensitive)
+	Query     string  `bson:"query,omitempty" json:"query,omitempty"`
+	Location  string  `bson:"location,omitempty" json:"location,omitempty"`
+	JobType   string  `bson:"job_type,omitempty" json:"job_type,omitempty"`
+	Level     string  `bson:"level,omitempty" json:"level,omitempty"`
+	MinSalary float64 `bson:"min_salary,omitempty" json:"min_salary,omitempty"`
+	// Apenas vagas de empresas verificadas
+	VerifiedOnly bool `bson:"verified_only,omitempty" json:"verified_only,omitempty"`
+}
+
+// IsEmpty indica se nenhum filtro foi informado
+func (f SearchFilters) IsEmpty() bool {
+	return f.Query == "" && f.Location == "" && f.JobType == "" && f.Level == "" && f.MinSalary <= 0 && !f.VerifiedOnly
+}
+
+// Matches aplica os mesmos critérios de Search a uma vaga já carregada
+// (usado para casar vagas recém-publicadas com as buscas salvas)
+func (f SearchFilters) Matches(job *Job) bool {
+	if f.Location != "" && !matchesPattern(f.Location, job.Location) {
+		return false
+	}
+	if f.JobType != "" && !matchesPattern("^"+f.JobType+"$", job.JobType) {
+		return false
+	}
+	if f.Level != "" && !matchesPattern("^"+f.Level+"$", job.Level) {
+		return false
+	}
+	if f.MinSalary > 0 && job.Salary < f.MinSalary {
+		return false
+	}
+	if f.VerifiedOnly && !job.CompanyVerified {
+		return false
+	}
+	if f.Query != "" {
+		query := strings.ToLower(f.Query)
+		fields := append([]string{job.Title, job.Description, job.Company}, job.Requirements...)
+		for _, field := range fields {
+			if strings.Contains(strings.ToLower(field), query) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// matchesPattern repete a busca por regex case-insensitive do MongoDB; padrões que não compilam
+// são comparados como texto
+func matchesPattern(pattern, value string) bool {
+	re, err := regexp.Compile("(?i)" + pattern)
+	if err != nil {
+		return strings.Contains(strings.ToLower(value), strings.ToLower(strings.Trim(pattern, "^$")))
+	}
+	return re.MatchString(value)
 }
 
 func (r *MongoRepository) Search(ctx context.Context, filters SearchFilters) ([]*Job, error) {
-	filter := bson.M{}
+	// Vagas removidas ou retidas pela moderação nunca aparecem na busca pública
+	filter := publicFilter()
 
 	// Filtro de localização (case-insensitive, busca parcial)
 	if filters.Location != "" {
@@ -95,6 +174,21 @@ func (r *MongoRepository) Search(ctx context.Context, filters SearchFilters) ([]
 		filter["salary"] = bson.M{"$gte": filters.MinSalary}
 	}
 
+	if filters.VerifiedOnly {
+		filter["company_verified"] = true
+	}
+
+	// Texto livre (literal, não é interpretado como regex)
+	if filters.Query != "" {
+		query := bson.M{"$regex": regexp.QuoteMeta(filters.Query), "$options": "i"}
+		filter["$or"] = bson.A{
+			bson.M{"title": query},
+			bson.M{"description": query},
+			bson.M{"company": query},
+			bson.M{"requirements": query},
+		}
+	}
+
 	cursor, err := r.collection.Find(ctx, filter)
 	if err != nil {
 		return nil, err
@@ -111,8 +205,13 @@ func (r *MongoRepository) Search(ctx context.Context, filters SearchFilters) ([]
 
 func (r *MongoRepository) Update(ctx context.Context, job *Job) error {
 	job.UpdatedAt = time.Now()
+	job.refreshSimilarityTerms()
 	filter := bson.M{"_id": job.ID}
 	update := bson.M{"$set": job}
+	// Sem motivos, o campo é omitido do $set: remove os motivos antigos da moderação anterior
+	if len(job.ModerationFlags) == 0 {
+		update["$unset"] = bson.M{"moderation_flags": ""}
+	}
 
 	_, err := r.collection.UpdateOne(ctx, filter, update)
 	return err
@@ -159,6 +258,406 @@ func (r *MongoRepository) GetByCompanyID(ctx context.Context, companyID bson.Obj
 	return jobs, nil
 }
 
+// GetByHiringTeamMember retorna as vagas da empresa em que o membro está na equipe de contratação
+func (r *MongoRepository) GetByHiringTeamMember(ctx context.Context, companyID, memberID bson.ObjectID) ([]*Job, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"company_id": companyID, "hiring_team": memberID})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var jobs []*Job
+	if err = cursor.All(ctx, &jobs); err != nil {
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+// SetHiringTeam substitui a equipe de contratação da vaga
+func (r *MongoRepository) SetHiringTeam(ctx context.Context, jobID bson.ObjectID, team []bson.ObjectID) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": jobID}, bson.M{
+		"$set": bson.M{"hiring_team": team, "updated_at": time.Now()},
+	})
+	return err
+}
+
+// RemoveFromHiringTeams tira o membro da equipe de todas as vagas (ex: membro removido da empresa)
+func (r *MongoRepository) RemoveFromHiringTeams(ctx context.Context, memberID bson.ObjectID) error {
+	_, err := r.collection.UpdateMany(ctx, bson.M{"hiring_team": memberID}, bson.M{
+		"$pull": bson.M{"hiring_team": memberID},
+	})
+	return err
+}
+
+// SetCompanyVerified atualiza o selo de empresa verificada em todas as vagas da empresa
+func (r *MongoRepository) SetCompanyVerified(ctx context.Context, companyID bson.ObjectID, verified bool) error {
+	_, err := r.collection.UpdateMany(ctx,
+		bson.M{"company_id": companyID},
+		bson.M{"$set": bson.M{"company_verified": verified, "updated_at": time.Now()}},
+	)
+	return err
+}
+
+// SetCompanyName propaga o novo nome da empresa para todas as suas vagas
+func (r *MongoRepository) SetCompanyName(ctx context.Context, companyID bson.ObjectID, name string) error {
+	_, err := r.collection.UpdateMany(ctx,
+		bson.M{"company_id": companyID},
+		bson.M{"$set": bson.M{"company": name, "updated_at": time.Now()}},
+	)
+	return err
+}
+
+// ListPublicByCompany retorna as vagas ativas e públicas da empresa (mais recentes primeiro)
+func (r *MongoRepository) ListPublicByCompany(ctx context.Context, companyID bson.ObjectID) ([]*Job, error) {
+	filter := publicFilter()
+	filter["company_id"] = companyID
+	filter["is_active"] = true
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var jobs []*Job
+	if err = cursor.All(ctx, &jobs); err != nil {
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+// ListByModerationStatus lista a fila de moderação (mais antigas primeiro)
+func (r *MongoRepository) ListByModerationStatus(ctx context.Context, status string, limit int64) ([]*Job, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}).SetLimit(limit)
+	cursor, err := r.collection.Find(ctx, bson.M{"moderation_status": status}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var jobs []*Job
+	if err = cursor.All(ctx, &jobs); err != nil {
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+// SetModeration registra a decisão de um admin sobre a vaga
+func (r *MongoRepository) SetModeration(ctx context.Context, id bson.ObjectID, status, reason string) error {
+	now := time.Now()
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{
+		"$set": bson.M{"moderation_status": status, "moderation_reason": reason, "moderated_at": now, "updated_at": now},
+	})
+	return err
+}
+
+// ReleaseUnverifiedHolds aprova as vagas da empresa retidas apenas por ela não ser verificada
+// (chamado quando a empresa é verificada). Vagas com outros motivos continuam na fila.
+func (r *MongoRepository) ReleaseUnverifiedHolds(ctx context.Context, companyID bson.ObjectID) error {
+	now := time.Now()
+	_, err := r.collection.UpdateMany(ctx,
+		bson.M{
+			"company_id":        companyID,
+			"moderation_status": ModerationPendingReview,
+			"moderation_flags":  bson.M{"$not": bson.M{"$elemMatch": bson.M{"rule": bson.M{"$ne": RuleCompanyUnverified}}}},
+		},
+		bson.M{
+			"$set":   bson.M{"moderation_status": ModerationApproved, "moderated_at": now, "updated_at": now},
+			"$unset": bson.M{"moderation_flags": ""},
+		},
+	)
+	return err
+}
+
+// HoldForReview tira uma vaga aprovada do ar e a coloca na fila de revisão com o motivo informado.
+// Retorna false se a vaga já estava retida ou rejeitada.
+func (r *MongoRepository) HoldForReview(ctx context.Context, id bson.ObjectID, flag ModerationFlag) (bool, error) {
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{
+			"_id":               id,
+			"moderation_status": bson.M{"$nin": []string{ModerationPendingReview, ModerationRejected}},
+		},
+		bson.M{
+			"$set":  bson.M{"moderation_status": ModerationPendingReview, "updated_at": time.Now()},
+			"$push": bson.M{"moderation_flags": flag},
+		},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// ReleaseHold remove os motivos de uma regra e aprova a vaga se não sobrar nenhum outro motivo
+func (r *MongoRepository) ReleaseHold(ctx context.Context, id bson.ObjectID, rule string) error {
+	now := time.Now()
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "moderation_status": ModerationPendingReview},
+		bson.M{
+			"$pull": bson.M{"moderation_flags": bson.M{"rule": rule}},
+			"$set":  bson.M{"updated_at": now},
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.collection.UpdateOne(ctx,
+		bson.M{
+			"_id":               id,
+			"moderation_status": ModerationPendingReview,
+			"$or": bson.A{
+				bson.M{"moderation_flags": bson.M{"$exists": false}},
+				bson.M{"moderation_flags": bson.M{"$size": 0}},
+			},
+		},
+		bson.M{
+			"$set":   bson.M{"moderation_status": ModerationApproved, "moderated_at": now},
+			"$unset": bson.M{"moderation_flags": ""},
+		},
+	)
+	return err
+}
+
+// CountDuplicates conta vagas recentes com o mesmo conteúdo, separando as da própria empresa das de outras
+func (r *MongoRepository) CountDuplicates(ctx context.Context, contentHash string, companyID, excludeID bson.ObjectID, since time.Time) (sameCompany, otherCompanies int, err error) {
+	filter := bson.M{
+		"content_hash": contentHash,
+		"created_at":   bson.M{"$gte": since},
+		"taken_down":   bson.M{"$ne": true},
+	}
+	if !excludeID.IsZero() {
+		filter["_id"] = bson.M{"$ne": excludeID}
+	}
+
+	opts := options.Find().SetProjection(bson.M{"company_id": 1})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var job struct {
+			CompanyID bson.ObjectID `bson:"company_id"`
+		}
+		if err := cursor.Decode(&job); err != nil {
+			return 0, 0, err
+		}
+		if job.CompanyID == companyID {
+			sameCompany++
+		} else {
+			otherCompanies++
+		}
+	}
+	return sameCompany, otherCompanies, cursor.Err()
+}
+
+// ListAll retorna todas as vagas, inclusive as removidas pela moderação (uso administrativo)
+func (r *MongoRepository) ListAll(ctx context.Context) ([]*Job, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var jobs []*Job
+	if err = cursor.All(ctx, &jobs); err != nil {
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+// SetTakenDown remove a vaga (desativando-a) ou desfaz a remoção. Desfazer não reativa a vaga:
+// a empresa decide quando publicá-la de novo.
+func (r *MongoRepository) SetTakenDown(ctx context.Context, id bson.ObjectID, takenDown bool, reason string) error {
+	now := time.Now()
+	update := bson.M{
+		"$set": bson.M{"taken_down": true, "takedown_reason": reason, "taken_down_at": now, "is_active": false, "updated_at": now},
+	}
+	if !takenDown {
+		update = bson.M{
+			"$set":   bson.M{"updated_at": now},
+			"$unset": bson.M{"taken_down": "", "takedown_reason": "", "taken_down_at": ""},
+		}
+	}
+
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	return err
+}
+
+// ListExpiringSoon retorna as vagas ativas que expiram até a data informada e cuja empresa
+// ainda não foi avisada
+func (r *MongoRepository) ListExpiringSoon(ctx context.Context, until time.Time) ([]*Job, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{
+		"is_active":        true,
+		"expires_at":       bson.M{"$gt": time.Now(), "$lte": until},
+		"expiry_warned_at": bson.M{"$exists": false},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var jobs []*Job
+	if err = cursor.All(ctx, &jobs); err != nil {
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+// MarkExpiryWarned registra o aviso de expiração. Retorna false se a vaga já tinha sido avisada
+// (evita avisos repetidos com mais de uma instância da API).
+func (r *MongoRepository) MarkExpiryWarned(ctx context.Context, id bson.ObjectID) (bool, error) {
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "expiry_warned_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"expiry_warned_at": time.Now()}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// SetExpiry define a nova validade da vaga e libera um novo aviso de expiração
+func (r *MongoRepository) SetExpiry(ctx context.Context, id bson.ObjectID, expiresAt time.Time) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{
+		"$set":   bson.M{"expires_at": expiresAt, "updated_at": time.Now()},
+		"$unset": bson.M{"expiry_warned_at": ""},
+	})
+	return err
+}
+
+// ListDueForExpiry retorna as vagas ativas cuja validade já passou
+func (r *MongoRepository) ListDueForExpiry(ctx context.Context) ([]*Job, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{
+		"is_active":  true,
+		"expires_at": bson.M{"$lte": time.Now()},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var jobs []*Job
+	if err = cursor.All(ctx, &jobs); err != nil {
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+// Expire desativa a vaga vencida. Retorna false se ela já foi desativada ou renovada nesse meio-tempo.
+func (r *MongoRepository) Expire(ctx context.Context, id bson.ObjectID) (bool, error) {
+	now := time.Now()
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "is_active": true, "expires_at": bson.M{"$lte": now}},
+		bson.M{"$set": bson.M{"is_active": false, "updated_at": now}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// ListPendingAlerts retorna as vagas no ar criadas desde a data informada que ainda não foram
+// comparadas com as buscas salvas dos candidatos
+func (r *MongoRepository) ListPendingAlerts(ctx context.Context, since time.Time) ([]*Job, error) {
+	filter := publicFilter()
+	filter["is_active"] = true
+	filter["created_at"] = bson.M{"$gte": since}
+	filter["alerts_processed_at"] = bson.M{"$exists": false}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var jobs []*Job
+	if err = cursor.All(ctx, &jobs); err != nil {
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+// MarkAlertsProcessed registra que a vaga já foi comparada com as buscas salvas. Retorna false se
+// outra instância da API já a processou.
+func (r *MongoRepository) MarkAlertsProcessed(ctx context.Context, id bson.ObjectID) (bool, error) {
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "alerts_processed_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"alerts_processed_at": time.Now()}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// ListOpenByIDs retorna, entre as vagas informadas, as que continuam no ar (mais recentes primeiro)
+func (r *MongoRepository) ListOpenByIDs(ctx context.Context, ids []bson.ObjectID) ([]*Job, error) {
+	filter := publicFilter()
+	filter["_id"] = bson.M{"$in": ids}
+	filter["is_active"] = true
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var jobs []*Job
+	if err = cursor.All(ctx, &jobs); err != nil {
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+// ListOpen retorna todas as vagas no ar (base das recomendações)
+func (r *MongoRepository) ListOpen(ctx context.Context) ([]*Job, error) {
+	filter := publicFilter()
+	filter["is_active"] = true
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var jobs []*Job
+	if err = cursor.All(ctx, &jobs); err != nil {
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+// ListByIDs retorna as vagas informadas, inclusive encerradas (histórico do candidato)
+func (r *MongoRepository) ListByIDs(ctx context.Context, ids []bson.ObjectID) ([]*Job, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var jobs []*Job
+	if err = cursor.All(ctx, &jobs); err != nil {
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
 // IncrementViews incrementa o contador de visualizações da vaga
 func (r *MongoRepository) IncrementViews(ctx context.Context, jobID string) error {
 	objectID, err := bson.ObjectIDFromHex(jobID)
@@ -0,0 +1,218 @@
+package jobs
+
+import (
+	"context"
+	"empregabemapi/internal/matching"
+	"sort"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// Peso de cada critério na semelhança entre duas vagas (somam 1)
+const (
+	similarityTitleWeight        = 0.35
+	similarityRequirementsWeight = 0.35
+	similarityLevelWeight        = 0.1
+	similarityLocationWeight     = 0.1
+	similaritySectorWeight       = 0.1
+)
+
+// Vagas com algum termo em comum avaliadas por consulta (as mais recentes)
+const similarCandidatesLimit = 300
+
+// Prefixos dos termos guardados em similarity_terms
+const (
+	titleTermPrefix       = "t:"
+	requirementTermPrefix = "r:"
+)
+
+// Palavras dos títulos que não indicam semelhança
+var titleStopwords = map[string]bool{
+	"de": true, "da": true, "do": true, "das": true, "dos": true, "e": true, "em": true,
+	"para": true, "com": true, "a": true, "o": true, "vaga": true,
+}
+
+// ComputeSimilarityTerms retorna os termos que descrevem a vaga: palavras do título e requisitos,
+// normalizados (sem acentos, apelidos resolvidos) e sem repetição
+func (j *Job) ComputeSimilarityTerms() []string {
+	seen := map[string]bool{}
+	var terms []string
+	add := func(term string) {
+		if !seen[term] {
+			seen[term] = true
+			terms = append(terms, term)
+		}
+	}
+
+	for _, word := range strings.Fields(matching.Normalize(j.Title)) {
+		if len(word) > 1 && !titleStopwords[word] {
+			add(titleTermPrefix + word)
+		}
+	}
+	for _, requirement := range j.Requirements {
+		if term := matching.Normalize(requirement); term != "" {
+			add(requirementTermPrefix + term)
+		}
+	}
+	return terms
+}
+
+// refreshSimilarityTerms atualiza os termos guardados na vaga (lista vazia, não nula, quando não há termos)
+func (j *Job) refreshSimilarityTerms() {
+	j.SimilarityTerms = j.ComputeSimilarityTerms()
+	if j.SimilarityTerms == nil {
+		j.SimilarityTerms = []string{}
+	}
+}
+
+// Similarity calcula a semelhança (0 a 1) entre duas vagas: termos do título, requisitos em comum,
+// nível, localização e setor da empresa. Usa os termos pré-calculados de cada vaga.
+func Similarity(a, b *Job) float64 {
+	aTitle, aRequirements := splitTerms(a.SimilarityTerms)
+	bTitle, bRequirements := splitTerms(b.SimilarityTerms)
+
+	score := similarityTitleWeight*jaccard(aTitle, bTitle) +
+		similarityRequirementsWeight*jaccard(aRequirements, bRequirements)
+
+	if level := matching.Normalize(a.Level); level != "" && level == matching.Normalize(b.Level) {
+		score += similarityLevelWeight
+	}
+	if sameLocation(a, b) {
+		score += similarityLocationWeight
+	}
+	if a.CompanySector != "" && strings.EqualFold(a.CompanySector, b.CompanySector) {
+		score += similaritySectorWeight
+	}
+	return score
+}
+
+// ListSimilar retorna as vagas no ar mais parecidas com a vaga informada (sem ela). A consulta usa
+// o índice de similarity_terms para trazer só vagas com algum termo em comum.
+func (r *MongoRepository) ListSimilar(ctx context.Context, job *Job, limit int) ([]*Job, error) {
+	if len(job.SimilarityTerms) == 0 {
+		return []*Job{}, nil
+	}
+
+	filter := publicFilter()
+	filter["is_active"] = true
+	filter["_id"] = bson.M{"$ne": job.ID}
+	filter["similarity_terms"] = bson.M{"$in": job.SimilarityTerms}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetLimit(similarCandidatesLimit)
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var candidates []*Job
+	if err = cursor.All(ctx, &candidates); err != nil {
+		return nil, err
+	}
+
+	scores := make(map[bson.ObjectID]float64, len(candidates))
+	for _, candidate := range candidates {
+		scores[candidate.ID] = Similarity(job, candidate)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return scores[candidates[i].ID] > scores[candidates[j].ID]
+	})
+
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	return candidates, nil
+}
+
+// BackfillSimilarity calcula os termos e copia o setor da empresa (companySector) nas vagas criadas
+// antes da busca por vagas semelhantes
+func (r *MongoRepository) BackfillSimilarity(ctx context.Context, companySector func(companyID bson.ObjectID) string) (int, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"similarity_terms": bson.M{"$exists": false}})
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var pending []*Job
+	if err = cursor.All(ctx, &pending); err != nil {
+		return 0, err
+	}
+
+	updated := 0
+	for _, job := range pending {
+		// Vaga sem termos aproveitáveis grava a lista vazia para não voltar aqui
+		job.refreshSimilarityTerms()
+		set := bson.M{"similarity_terms": job.SimilarityTerms}
+		if sector := companySector(job.CompanyID); sector != "" {
+			set["company_sector"] = sector
+		}
+		_, err = r.collection.UpdateOne(ctx, bson.M{"_id": job.ID}, bson.M{"$set": set})
+		if err != nil {
+			return updated, err
+		}
+		updated++
+	}
+
+	return updated, nil
+}
+
+// SetCompanySector propaga o setor da empresa para todas as suas vagas
+func (r *MongoRepository) SetCompanySector(ctx context.Context, companyID bson.ObjectID, sector string) error {
+	_, err := r.collection.UpdateMany(ctx,
+		bson.M{"company_id": companyID},
+		bson.M{"$set": bson.M{"company_sector": sector}},
+	)
+	return err
+}
+
+func splitTerms(terms []string) (title, requirements map[string]bool) {
+	title, requirements = map[string]bool{}, map[string]bool{}
+	for _, term := range terms {
+		switch {
+		case strings.HasPrefix(term, titleTermPrefix):
+			title[term] = true
+		case strings.HasPrefix(term, requirementTermPrefix):
+			requirements[term] = true
+		}
+	}
+	return title, requirements
+}
+
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for term := range a {
+		if b[term] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// sameLocation indica se as duas vagas são remotas ou ficam na mesma cidade
+func sameLocation(a, b *Job) bool {
+	if isRemote(a) || isRemote(b) {
+		return isRemote(a) && isRemote(b)
+	}
+	aCity, bCity := locationCity(a.Location), locationCity(b.Location)
+	return aCity != "" && aCity == bCity
+}
+
+func isRemote(job *Job) bool {
+	return matching.Normalize(job.JobType) == "remoto" || strings.Contains(matching.Normalize(job.Location), "remoto")
+}
+
+// locationCity retorna a cidade normalizada de "São Paulo, SP" ou "São Paulo - SP"
+func locationCity(location string) string {
+	parts := strings.FieldsFunc(location, func(r rune) bool { return r == ',' || r == '-' || r == '/' })
+	if len(parts) == 0 {
+		return ""
+	}
+	return matching.Normalize(parts[0])
+}